@@ -76,14 +76,102 @@ The following key types are supported by default:
 
 For an example of how to register a custom key type, refer to the [redis] driver package.
 
+# Watching Keys
+
+Drivers that implement [driver.Watcher] can report when keys are set, deleted or expire.
+Use [GenericCache.Watch] to receive these events on a channel:
+
+	events, err := c.Watch(ctx, "user:*")
+	if err != nil {
+	    log.Fatalf("Failed to watch keys: %v", err)
+	}
+	for ev := range events {
+	    log.Printf("%s %s", ev.Type, ev.Key)
+	}
+
+Drivers that cannot report events return an error wrapping [ErrNotSupported].
+
+# Batch Operations
+
+[GenericCache.GetMulti], [GenericCache.SetMulti] and [GenericCache.DelMulti] operate on
+several keys at once. Drivers that implement [driver.Batcher] do so in fewer round trips;
+the others handle the keys one by one.
+
+# Scripts
+
+Operations that the portable API can't express atomically can be registered as scripts
+with [RegisterScript], and run with [GenericCache.RunScript] by drivers that implement
+[driver.ScriptRunner]. A [Script] has a Lua implementation, run by Redis, and an
+equivalent Go implementation, run by in-memory caches, so that code using scripts can be
+tested against either:
+
+	cache.RegisterScript(&cache.Script{
+	    Name: "incrby",
+	    Lua:  "return redis.call('INCRBY', KEYS[1], ARGV[1])",
+	    Func: incrBy, // func(s driver.ScriptStore, keys, args []string) (interface{}, error)
+	})
+	n, err := c.RunScript(ctx, "incrby", []string{"counter"}, 5)
+
+The [ScriptGetEx], [ScriptDelIfEqual] and [ScriptCappedPush] scripts are registered by
+default.
+
+# Diagnostics
+
+Drivers that implement [driver.Diagnoser] report their connection pool statistics and
+the statistics of each server, such as the memory, keyspace and eviction sections of the
+Redis INFO command, with [GenericCache.Diagnostics]:
+
+	d, err := c.Diagnostics(ctx)
+	if err != nil {
+	    log.Fatalf("Failed to get diagnostics: %v", err)
+	}
+	for _, s := range d.Servers {
+	    log.Printf("%s: %s used", s.Addr, s.Info["memory"]["used_memory_human"])
+	}
+
+# Dump and Restore
+
+Drivers that implement [driver.Scanner] can be dumped with [GenericCache.Dump], and a
+dump can be loaded into any cache with [GenericCache.Restore]. This makes it possible,
+for example, to snapshot a Redis Cluster and load it into a RAM cache:
+
+	var buf bytes.Buffer
+	if err := src.Dump(ctx, &buf, "*"); err != nil {
+	    log.Fatalf("Failed to dump cache: %v", err)
+	}
+	if err := dst.Restore(ctx, &buf, &cache.RestoreOptions{Replace: true}); err != nil {
+	    log.Fatalf("Failed to restore cache: %v", err)
+	}
+
+A dump is a stream made up of a header followed by zero or more records, one per
+entry. Integers are encoded as unsigned varints (see [encoding/binary.PutUvarint]):
+
+	header   = magic version created
+	magic    = "GCDUMP"   (6 bytes)
+	version  = byte       (currently 1)
+	created  = uvarint    (Unix time in milliseconds when the dump was started)
+
+	record   = keylen key valuelen value ttl
+	keylen   = uvarint    (length of key in bytes)
+	key      = bytes
+	valuelen = uvarint    (length of value in bytes)
+	value    = bytes
+	ttl      = uvarint    (remaining TTL in milliseconds; 0 means no expiry)
+
+The stream ends at a record boundary. Keys and values are opaque byte strings of at
+most 512MiB each.
+
 [redis]: https://pkg.go.dev/github.com/bartventer/gocache/redis
 */
 package cache
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/bartventer/gocache/internal/gcerrors"
+
 	"github.com/bartventer/gocache/pkg/driver"
 	"github.com/bartventer/gocache/pkg/keymod"
 )
@@ -153,6 +241,121 @@ func (c *GenericCache[K]) SetWithTTL(ctx context.Context, key K, value interface
 	return c.driver.SetWithTTL(ctx, key, value, ttl)
 }
 
+// Watch returns a channel that receives [driver.Event] values for keys matching the given
+// pattern. The channel is closed when ctx is canceled. It returns an error wrapping
+// [ErrNotSupported] if the underlying driver does not implement [driver.Watcher].
+func (c *GenericCache[K]) Watch(ctx context.Context, pattern K) (<-chan driver.Event[K], error) {
+	w, ok := c.driver.(driver.Watcher[K])
+	if !ok {
+		return nil, gcerrors.New(errors.Join(ErrNotSupported, errors.New("Watch operation not supported")))
+	}
+	return w.Watch(ctx, pattern)
+}
+
+// Scan calls fn for each entry whose key matches the given pattern. It returns an error
+// wrapping [ErrNotSupported] if the underlying driver does not implement [driver.Scanner].
+func (c *GenericCache[K]) Scan(ctx context.Context, pattern K, fn func(driver.Entry[K]) error) error {
+	s, ok := c.driver.(driver.Scanner[K])
+	if !ok {
+		return gcerrors.New(errors.Join(ErrNotSupported, errors.New("Scan operation not supported")))
+	}
+	return s.Scan(ctx, pattern, fn)
+}
+
+// GetMulti returns the values of keys, in the order of keys. The value of a key that does
+// not exist is nil. Drivers that do not implement [driver.Batcher] get the keys one by one.
+func (c *GenericCache[K]) GetMulti(ctx context.Context, keys []K) ([][]byte, error) {
+	if b, ok := c.driver.(driver.Batcher[K]); ok {
+		return b.GetMulti(ctx, keys)
+	}
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		value, err := c.driver.Get(ctx, key)
+		if err != nil && !errors.Is(err, ErrKeyNotFound) {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// SetMulti stores the entries. Entries with a zero TTL do not expire. Drivers that do not
+// implement [driver.Batcher] set the entries one by one.
+func (c *GenericCache[K]) SetMulti(ctx context.Context, entries []driver.Entry[K]) error {
+	if b, ok := c.driver.(driver.Batcher[K]); ok {
+		return b.SetMulti(ctx, entries)
+	}
+	for _, e := range entries {
+		var err error
+		if e.TTL > 0 {
+			err = c.driver.SetWithTTL(ctx, e.Key, e.Value, e.TTL)
+		} else {
+			err = c.driver.Set(ctx, e.Key, e.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DelMulti removes keys and returns the number of keys that existed. Drivers that do not
+// implement [driver.Batcher] delete the keys one by one.
+func (c *GenericCache[K]) DelMulti(ctx context.Context, keys []K) (int64, error) {
+	if b, ok := c.driver.(driver.Batcher[K]); ok {
+		return b.DelMulti(ctx, keys)
+	}
+	var n int64
+	for _, key := range keys {
+		err := c.driver.Del(ctx, key)
+		if errors.Is(err, ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// DelKeysOptions are the options for [GenericCache.DelKeysWithOptions].
+type DelKeysOptions = driver.DelKeysOptions
+
+// DelKeysWithOptions removes all keys that match the given pattern, in bounded batches as
+// they are found, and returns the number of keys deleted. A nil opts is equivalent to a
+// zero [DelKeysOptions]. If more keys match than [DelKeysOptions.MaxKeys], it returns an
+// error wrapping [ErrMaxKeysExceeded] without deleting any key.
+//
+// It returns an error wrapping [ErrNotSupported] if the underlying driver does not
+// implement [driver.KeysDeleter].
+func (c *GenericCache[K]) DelKeysWithOptions(ctx context.Context, pattern K, opts *DelKeysOptions) (int64, error) {
+	d, ok := c.driver.(driver.KeysDeleter[K])
+	if !ok {
+		return 0, gcerrors.New(errors.Join(ErrNotSupported, errors.New("DelKeysWithOptions operation not supported")))
+	}
+	if opts == nil {
+		opts = &DelKeysOptions{}
+	}
+	return d.DelKeysWithOptions(ctx, pattern, *opts)
+}
+
+// Diagnostics is a report of the state of a cache, returned by [GenericCache.Diagnostics].
+type Diagnostics = driver.Diagnostics
+
+// Diagnostics returns a report of the client connection pool and the statistics of each
+// server, for debugging and monitoring.
+//
+// It returns an error wrapping [ErrNotSupported] if the underlying driver does not
+// implement [driver.Diagnoser].
+func (c *GenericCache[K]) Diagnostics(ctx context.Context) (*Diagnostics, error) {
+	d, ok := c.driver.(driver.Diagnoser)
+	if !ok {
+		return nil, gcerrors.New(errors.Join(ErrNotSupported, errors.New("Diagnostics operation not supported")))
+	}
+	return d.Diagnostics(ctx)
+}
+
 // NewCache creates a new [GenericCache] using the provided driver. Not intended for direct application use.
 func NewCache[K driver.String](driver driver.Cache[K]) *GenericCache[K] {
 	return &GenericCache[K]{driver: driver}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *mapCache) Get(_ context.Context, key string) ([]byte, error) {
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, fmt.Errorf("key %s: %w", key, ErrKeyNotFound)
+	}
+	return e.Value, nil
+}
+
+func (m *mapCache) Del(_ context.Context, key string) error {
+	if _, ok := m.entries[key]; !ok {
+		return fmt.Errorf("key %s: %w", key, ErrKeyNotFound)
+	}
+	delete(m.entries, key)
+	return nil
+}
+
+func TestMulti_Fallback(t *testing.T) {
+	ctx := context.Background()
+	m := newMapCache()
+	c := NewCache[string](m)
+
+	require.NoError(t, c.SetMulti(ctx, []driver.Entry[string]{
+		{Key: "a", Value: []byte("1")},
+		{Key: "b", Value: []byte("2"), TTL: time.Hour},
+	}))
+	assert.Equal(t, time.Hour, m.entries["b"].TTL)
+
+	values, err := c.GetMulti(ctx, []string{"b", "missing", "a"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("2"), nil, []byte("1")}, values)
+
+	n, err := c.DelMulti(ctx, []string{"a", "missing", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.Empty(t, m.entries)
+}
+
+func TestDelKeysWithOptions_NotSupported(t *testing.T) {
+	var c struct{ driver.Cache[string] }
+	_, err := NewCache[string](c).DelKeysWithOptions(context.Background(), "*", nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestDiagnostics_NotSupported(t *testing.T) {
+	var c struct{ driver.Cache[string] }
+	_, err := NewCache[string](c).Diagnostics(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNotSupported)
+}
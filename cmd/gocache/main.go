@@ -0,0 +1,77 @@
+/*
+Command gocache is a command-line client for any cache supported by gocache.
+
+The cache is selected with a URL, exactly as with [cache.OpenCache]. All the drivers
+in this repository are registered, so any of the following schemes can be used:
+redis, rediscluster, redissentinel, redisring, memcache and ramcache.
+
+# Usage
+
+	gocache [flags] <command> [arguments]
+
+The flags are:
+
+	-url string
+	    URL of the cache to open. Defaults to the GOCACHE_URL environment variable.
+	-format string
+	    Output format for values: raw, hex or json. Defaults to raw.
+	-timeout duration
+	    Timeout for the command. Zero means no timeout.
+
+The commands are:
+
+	get <key>                        print the value of a key
+	set [-ttl duration] <key> <value> set a key; a value of "-" is read from stdin
+	del <key>                        delete a key
+	exists <key>                     report whether a key exists
+	count <pattern>                  count the keys matching a pattern
+	delkeys <pattern>                delete the keys matching a pattern
+	clear                            delete all keys
+	ping                             check that the cache is reachable
+	scan <pattern>                   list the entries matching a pattern
+	dump [-o file] <pattern>         write the entries matching a pattern to a dump
+	restore [-replace] [-adjustttl] [file]
+	                                 load a dump from a file, or stdin if omitted
+
+# Output Formats
+
+With -format raw, values are written verbatim followed by a newline. With -format hex,
+values are written hex-encoded. With -format json, each value (or scanned entry) is
+written as a JSON object on its own line:
+
+	{"key":"user:1","value":"alice"}
+	{"key":"blob","value":"AP8=","encoding":"base64","ttl":"59m58s"}
+
+Values that are not valid UTF-8 are base64-encoded, as indicated by the encoding field.
+The scan command writes one key per line in the raw and hex formats, and one object per
+entry, including its remaining TTL, in the json format.
+
+# Exit Status
+
+	0  success
+	1  the command failed
+	2  invalid usage
+	3  the key was not found (get, del) or does not exist (exists)
+	4  the operation is not supported by the cache
+
+# Examples
+
+	gocache -url redis://localhost:6379 set -ttl 1h greeting hello
+	gocache -url redis://localhost:6379 -format json get greeting
+	gocache -url rediscluster://localhost:7000,localhost:7001 dump '*' > cache.dump
+	gocache -url redis://localhost:6379 restore -replace cache.dump
+*/
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bartventer/gocache/pkg/driver"
+)
+
+// format is an output format for values.
+type format string
+
+// Output formats.
+const (
+	formatRaw  format = "raw"  // formatRaw writes values verbatim.
+	formatHex  format = "hex"  // formatHex writes values hex-encoded.
+	formatJSON format = "json" // formatJSON writes values as JSON objects.
+)
+
+// parseFormat parses an output format name.
+func parseFormat(s string) (format, error) {
+	switch f := format(s); f {
+	case formatRaw, formatHex, formatJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown format %q: must be raw, hex or json", s)
+	}
+}
+
+// jsonEntry is the JSON representation of a cache entry.
+type jsonEntry struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Encoding string `json:"encoding,omitempty"`
+	TTL      string `json:"ttl,omitempty"`
+}
+
+// newJSONEntry returns the JSON representation of an entry. Values that are not valid
+// UTF-8 are base64-encoded.
+func newJSONEntry(key string, value []byte, ttl time.Duration) jsonEntry {
+	e := jsonEntry{Key: key, Value: string(value)}
+	if !utf8.Valid(value) {
+		e.Value = base64.StdEncoding.EncodeToString(value)
+		e.Encoding = "base64"
+	}
+	if ttl > 0 {
+		e.TTL = ttl.Round(time.Second).String()
+	}
+	return e
+}
+
+// writeJSON writes v as a single line of JSON.
+func writeJSON(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// WriteValue writes the value of key to w.
+func (f format) WriteValue(w io.Writer, key string, value []byte) error {
+	var err error
+	switch f {
+	case formatHex:
+		_, err = fmt.Fprintln(w, hex.EncodeToString(value))
+	case formatJSON:
+		err = writeJSON(w, newJSONEntry(key, value, 0))
+	default:
+		if _, err = w.Write(value); err == nil {
+			_, err = fmt.Fprintln(w)
+		}
+	}
+	return err
+}
+
+// WriteEntry writes a scanned entry to w. The raw and hex formats write the key only.
+func (f format) WriteEntry(w io.Writer, e driver.Entry[string]) error {
+	var err error
+	switch f {
+	case formatHex:
+		_, err = fmt.Fprintln(w, hex.EncodeToString([]byte(e.Key)))
+	case formatJSON:
+		err = writeJSON(w, newJSONEntry(e.Key, e.Value, e.TTL))
+	default:
+		_, err = fmt.Fprintln(w, e.Key)
+	}
+	return err
+}
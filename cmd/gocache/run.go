@@ -0,0 +1,379 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/pkg/driver"
+
+	// Register the drivers.
+	_ "github.com/bartventer/gocache/memcache"
+	_ "github.com/bartventer/gocache/ramcache"
+	_ "github.com/bartventer/gocache/redis"
+	_ "github.com/bartventer/gocache/rediscluster"
+	_ "github.com/bartventer/gocache/redisring"
+	_ "github.com/bartventer/gocache/redissentinel"
+)
+
+// Exit codes.
+const (
+	exitOK           = 0 // exitOK is returned on success.
+	exitError        = 1 // exitError is returned when the command fails.
+	exitUsage        = 2 // exitUsage is returned on invalid usage.
+	exitNotFound     = 3 // exitNotFound is returned when a key is not found.
+	exitNotSupported = 4 // exitNotSupported is returned when the cache does not support the operation.
+)
+
+// urlEnvVar is the environment variable holding the default cache URL.
+const urlEnvVar = "GOCACHE_URL"
+
+// usageError is returned when a command is invoked with invalid arguments.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// exitStatus is an error that only sets the exit status; it is not reported.
+type exitStatus int
+
+func (s exitStatus) Error() string {
+	return "exit status " + strconv.Itoa(int(s))
+}
+
+// env is the environment a command runs in.
+type env struct {
+	url    string    // url is the cache URL.
+	format format    // format is the output format.
+	stdin  io.Reader // stdin is the standard input.
+	stdout io.Writer // stdout is the standard output.
+	stderr io.Writer // stderr is the standard error.
+}
+
+// open opens the cache.
+func (e *env) open(ctx context.Context) (*cache.Cache, error) {
+	if e.url == "" {
+		return nil, &usageError{msg: "no cache URL: use -url or set " + urlEnvVar}
+	}
+	return cache.OpenCache(ctx, e.url)
+}
+
+// command is a gocache subcommand.
+type command struct {
+	args  string                                                    // args is the argument synopsis.
+	help  string                                                    // help is a short description.
+	flags func(fs *flag.FlagSet)                                    // flags registers the command flags, if any.
+	run   func(ctx context.Context, e *env, fs *flag.FlagSet) error // run runs the command with the parsed flags.
+}
+
+// commands are the supported subcommands.
+var commands = map[string]command{
+	"get":     {args: "<key>", help: "print the value of a key", run: runGet},
+	"set":     {args: "[-ttl duration] <key> <value>", help: `set a key; a value of "-" is read from stdin`, flags: setFlags, run: runSet},
+	"del":     {args: "<key>", help: "delete a key", run: runDel},
+	"exists":  {args: "<key>", help: "report whether a key exists", run: runExists},
+	"count":   {args: "<pattern>", help: "count the keys matching a pattern", run: runCount},
+	"delkeys": {args: "<pattern>", help: "delete the keys matching a pattern", run: runDelKeys},
+	"clear":   {args: "", help: "delete all keys", run: runClear},
+	"ping":    {args: "", help: "check that the cache is reachable", run: runPing},
+	"scan":    {args: "<pattern>", help: "list the entries matching a pattern", run: runScan},
+	"dump":    {args: "[-o file] <pattern>", help: "write the entries matching a pattern to a dump", flags: dumpFlags, run: runDump},
+	"restore": {args: "[-replace] [-adjustttl] [file]", help: "load a dump from a file, or stdin if omitted", flags: restoreFlags, run: runRestore},
+}
+
+// run runs the gocache command with the given arguments and returns the exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gocache", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	urlStr := fs.String("url", os.Getenv(urlEnvVar), "URL of the cache to open (default $"+urlEnvVar+")")
+	formatStr := fs.String("format", string(formatRaw), "output format for values: raw, hex or json")
+	timeout := fs.Duration("timeout", 0, "timeout for the command; zero means no timeout")
+	fs.Usage = func() { printUsage(fs) }
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	name := fs.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "gocache: unknown command %q\n", name)
+		fs.Usage()
+		return exitUsage
+	}
+	f, err := parseFormat(*formatStr)
+	if err != nil {
+		fmt.Fprintf(stderr, "gocache: %v\n", err)
+		return exitUsage
+	}
+
+	cmdFS := flag.NewFlagSet("gocache "+name, flag.ContinueOnError)
+	cmdFS.SetOutput(stderr)
+	cmdFS.Usage = func() {
+		fmt.Fprintf(stderr, "usage: gocache %s %s\n", name, cmd.args)
+		cmdFS.PrintDefaults()
+	}
+	if cmd.flags != nil {
+		cmd.flags(cmdFS)
+	}
+	if err := cmdFS.Parse(fs.Args()[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	e := &env{url: *urlStr, format: f, stdin: stdin, stdout: stdout, stderr: stderr}
+	err = cmd.run(ctx, e, cmdFS)
+	code := exitCode(err)
+	switch {
+	case err == nil:
+	case errors.As(err, new(exitStatus)):
+	case code == exitUsage:
+		fmt.Fprintf(stderr, "gocache %s: %v\n", name, err)
+		cmdFS.Usage()
+	default:
+		fmt.Fprintf(stderr, "gocache %s: %v\n", name, err)
+	}
+	return code
+}
+
+// exitCode returns the exit code for the error returned by a command.
+func exitCode(err error) int {
+	var status exitStatus
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &status):
+		return int(status)
+	case errors.As(err, new(*usageError)):
+		return exitUsage
+	case errors.Is(err, cache.ErrKeyNotFound):
+		return exitNotFound
+	case errors.Is(err, cache.ErrNotSupported), errors.Is(err, cache.ErrPatternMatchingNotSupported):
+		return exitNotSupported
+	default:
+		return exitError
+	}
+}
+
+// printUsage prints the usage message.
+func printUsage(fs *flag.FlagSet) {
+	w := fs.Output()
+	fmt.Fprintln(w, "usage: gocache [flags] <command> [arguments]")
+	fmt.Fprintln(w, "\nflags:")
+	fs.PrintDefaults()
+	fmt.Fprintln(w, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cmd := commands[name]
+		fmt.Fprintf(w, "  %-8s %s\n", name, strings.TrimSpace(cmd.args))
+		fmt.Fprintf(w, "           %s\n", cmd.help)
+	}
+}
+
+// exactArgs returns a usage error unless fs has exactly n arguments.
+func exactArgs(fs *flag.FlagSet, n int) error {
+	if fs.NArg() != n {
+		return &usageError{msg: fmt.Sprintf("expected %d argument(s), got %d", n, fs.NArg())}
+	}
+	return nil
+}
+
+// flagValue returns the value of a flag registered by the command's flags function.
+func flagValue[T any](fs *flag.FlagSet, name string) T {
+	return fs.Lookup(name).Value.(flag.Getter).Get().(T) //nolint:forcetypeassert // the flag is registered with this type
+}
+
+// withCache validates the number of arguments, opens the cache and calls fn with it.
+func withCache(ctx context.Context, e *env, fs *flag.FlagSet, nargs int, fn func(c *cache.Cache) error) error {
+	if err := exactArgs(fs, nargs); err != nil {
+		return err
+	}
+	c, err := e.open(ctx)
+	if err != nil {
+		return err
+	}
+	err = fn(c)
+	if closeErr := c.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func runGet(ctx context.Context, e *env, fs *flag.FlagSet) error {
+	return withCache(ctx, e, fs, 1, func(c *cache.Cache) error {
+		key := fs.Arg(0)
+		value, err := c.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		return e.format.WriteValue(e.stdout, key, value)
+	})
+}
+
+func setFlags(fs *flag.FlagSet) {
+	fs.Duration("ttl", 0, "time-to-live of the key; zero means no expiry")
+}
+
+func runSet(ctx context.Context, e *env, fs *flag.FlagSet) error {
+	ttl := flagValue[time.Duration](fs, "ttl")
+	if ttl < 0 {
+		return &usageError{msg: "ttl must not be negative"}
+	}
+	return withCache(ctx, e, fs, 2, func(c *cache.Cache) error {
+		key, value := fs.Arg(0), []byte(fs.Arg(1))
+		if fs.Arg(1) == "-" {
+			var err error
+			if value, err = io.ReadAll(e.stdin); err != nil {
+				return fmt.Errorf("error reading value: %w", err)
+			}
+		}
+		if ttl > 0 {
+			return c.SetWithTTL(ctx, key, value, ttl)
+		}
+		return c.Set(ctx, key, value)
+	})
+}
+
+func runDel(ctx context.Context, e *env, fs *flag.FlagSet) error {
+	return withCache(ctx, e, fs, 1, func(c *cache.Cache) error {
+		return c.Del(ctx, fs.Arg(0))
+	})
+}
+
+func runExists(ctx context.Context, e *env, fs *flag.FlagSet) error {
+	return withCache(ctx, e, fs, 1, func(c *cache.Cache) error {
+		exists, err := c.Exists(ctx, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(e.stdout, exists)
+		if !exists {
+			return exitStatus(exitNotFound)
+		}
+		return nil
+	})
+}
+
+func runCount(ctx context.Context, e *env, fs *flag.FlagSet) error {
+	return withCache(ctx, e, fs, 1, func(c *cache.Cache) error {
+		n, err := c.Count(ctx, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(e.stdout, n)
+		return nil
+	})
+}
+
+func runDelKeys(ctx context.Context, e *env, fs *flag.FlagSet) error {
+	return withCache(ctx, e, fs, 1, func(c *cache.Cache) error {
+		return c.DelKeys(ctx, fs.Arg(0))
+	})
+}
+
+func runClear(ctx context.Context, e *env, fs *flag.FlagSet) error {
+	return withCache(ctx, e, fs, 0, func(c *cache.Cache) error {
+		return c.Clear(ctx)
+	})
+}
+
+func runPing(ctx context.Context, e *env, fs *flag.FlagSet) error {
+	return withCache(ctx, e, fs, 0, func(c *cache.Cache) error {
+		if err := c.Ping(ctx); err != nil {
+			return err
+		}
+		fmt.Fprintln(e.stdout, "PONG")
+		return nil
+	})
+}
+
+func runScan(ctx context.Context, e *env, fs *flag.FlagSet) error {
+	return withCache(ctx, e, fs, 1, func(c *cache.Cache) error {
+		return c.Scan(ctx, fs.Arg(0), func(entry driver.Entry[string]) error {
+			return e.format.WriteEntry(e.stdout, entry)
+		})
+	})
+}
+
+func dumpFlags(fs *flag.FlagSet) {
+	fs.String("o", "", "write the dump to `file` instead of stdout")
+}
+
+func runDump(ctx context.Context, e *env, fs *flag.FlagSet) error {
+	return withCache(ctx, e, fs, 1, func(c *cache.Cache) error {
+		out := flagValue[string](fs, "o")
+		if out == "" {
+			return c.Dump(ctx, e.stdout, fs.Arg(0))
+		}
+		f, err := os.Create(out) //nolint:gosec // the path is provided by the user
+		if err != nil {
+			return err
+		}
+		err = c.Dump(ctx, f, fs.Arg(0))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	})
+}
+
+func restoreFlags(fs *flag.FlagSet) {
+	fs.Bool("replace", false, "overwrite keys that already exist")
+	fs.Bool("adjustttl", false, "reduce TTLs by the time elapsed since the dump was taken")
+}
+
+func runRestore(ctx context.Context, e *env, fs *flag.FlagSet) error {
+	if fs.NArg() > 1 {
+		return &usageError{msg: fmt.Sprintf("expected at most 1 argument, got %d", fs.NArg())}
+	}
+	opts := &cache.RestoreOptions{
+		Replace:   flagValue[bool](fs, "replace"),
+		AdjustTTL: flagValue[bool](fs, "adjustttl"),
+	}
+	in := e.stdin
+	if fs.NArg() == 1 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	c, err := e.open(ctx)
+	if err != nil {
+		return err
+	}
+	err = c.Restore(ctx, in, opts)
+	if closeErr := c.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
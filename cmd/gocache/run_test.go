@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testURL = "ramcache://"
+
+// runCmd runs gocache against the RAM cache and returns the exit code and output.
+func runCmd(t *testing.T, stdin string, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	var outBuf, errBuf bytes.Buffer
+	args = append([]string{"-url", testURL}, args...)
+	code = run(context.Background(), args, strings.NewReader(stdin), &outBuf, &errBuf)
+	return code, outBuf.String(), errBuf.String()
+}
+
+// resetCache clears the RAM cache shared by all tests.
+func resetCache(t *testing.T) {
+	t.Helper()
+	code, _, stderr := runCmd(t, "", "clear")
+	require.Equal(t, exitOK, code, stderr)
+}
+
+func TestRun_Usage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"no command", nil, exitUsage},
+		{"unknown command", []string{"frobnicate"}, exitUsage},
+		{"missing argument", []string{"get"}, exitUsage},
+		{"extra argument", []string{"del", "a", "b"}, exitUsage},
+		{"unknown flag", []string{"set", "-nope", "a", "b"}, exitUsage},
+		{"negative ttl", []string{"set", "-ttl", "-1s", "a", "b"}, exitUsage},
+		{"bad format", []string{"-format", "xml", "ping"}, exitUsage},
+		{"help", []string{"-h"}, exitOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := runCmd(t, "", tt.args...)
+			assert.Equal(t, tt.want, code)
+		})
+	}
+
+	t.Run("no url", func(t *testing.T) {
+		t.Setenv(urlEnvVar, "")
+		var stderr bytes.Buffer
+		code := run(context.Background(), []string{"ping"}, nil, new(bytes.Buffer), &stderr)
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr.String(), urlEnvVar)
+	})
+}
+
+func TestRun_URLFromEnv(t *testing.T) {
+	t.Setenv(urlEnvVar, testURL)
+	var stdout bytes.Buffer
+	code := run(context.Background(), []string{"ping"}, nil, &stdout, new(bytes.Buffer))
+	require.Equal(t, exitOK, code)
+	assert.Equal(t, "PONG\n", stdout.String())
+}
+
+func TestRun_Commands(t *testing.T) {
+	resetCache(t)
+
+	code, _, stderr := runCmd(t, "", "set", "greeting", "hello")
+	require.Equal(t, exitOK, code, stderr)
+	code, stdout, _ := runCmd(t, "", "get", "greeting")
+	require.Equal(t, exitOK, code)
+	assert.Equal(t, "hello\n", stdout)
+
+	code, stdout, _ = runCmd(t, "", "-format", "hex", "get", "greeting")
+	require.Equal(t, exitOK, code)
+	assert.Equal(t, "68656c6c6f\n", stdout)
+
+	code, _, stderr = runCmd(t, "from stdin", "set", "-ttl", "1h", "piped", "-")
+	require.Equal(t, exitOK, code, stderr)
+	code, stdout, _ = runCmd(t, "", "get", "piped")
+	require.Equal(t, exitOK, code)
+	assert.Equal(t, "from stdin\n", stdout)
+
+	code, stdout, _ = runCmd(t, "", "exists", "greeting")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "true\n", stdout)
+
+	code, _, stderr = runCmd(t, "", "del", "greeting")
+	require.Equal(t, exitOK, code, stderr)
+
+	code, stdout, _ = runCmd(t, "", "exists", "greeting")
+	assert.Equal(t, exitNotFound, code)
+	assert.Equal(t, "false\n", stdout)
+
+	code, _, stderr = runCmd(t, "", "get", "greeting")
+	assert.Equal(t, exitNotFound, code)
+	assert.NotEmpty(t, stderr)
+
+	code, _, _ = runCmd(t, "", "del", "greeting")
+	assert.Equal(t, exitNotFound, code)
+
+	code, stdout, _ = runCmd(t, "", "ping")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "PONG\n", stdout)
+}
+
+func TestRun_JSON(t *testing.T) {
+	resetCache(t)
+
+	code, _, stderr := runCmd(t, "\x00\xff", "set", "-ttl", "1h", "blob", "-")
+	require.Equal(t, exitOK, code, stderr)
+	code, _, stderr = runCmd(t, "", "set", "text", "hi")
+	require.Equal(t, exitOK, code, stderr)
+
+	code, stdout, _ := runCmd(t, "", "-format", "json", "get", "text")
+	require.Equal(t, exitOK, code)
+	assert.JSONEq(t, `{"key":"text","value":"hi"}`, stdout)
+
+	code, stdout, _ = runCmd(t, "", "-format", "json", "get", "blob")
+	require.Equal(t, exitOK, code)
+	assert.JSONEq(t, `{"key":"blob","value":"AP8=","encoding":"base64"}`, stdout)
+
+	code, stdout, _ = runCmd(t, "", "-format", "json", "scan", "blob")
+	require.Equal(t, exitOK, code)
+	var got jsonEntry
+	require.NoError(t, json.Unmarshal([]byte(stdout), &got))
+	assert.Equal(t, "blob", got.Key)
+	assert.Equal(t, "base64", got.Encoding)
+	assert.NotEmpty(t, got.TTL)
+}
+
+func TestRun_Scan(t *testing.T) {
+	resetCache(t)
+	for _, key := range []string{"user:1", "user:2", "order:1"} {
+		code, _, stderr := runCmd(t, "", "set", key, "v")
+		require.Equal(t, exitOK, code, stderr)
+	}
+
+	code, stdout, _ := runCmd(t, "", "scan", "user:*")
+	require.Equal(t, exitOK, code)
+	assert.ElementsMatch(t, []string{"user:1", "user:2"}, strings.Fields(stdout))
+}
+
+func TestRun_DumpRestore(t *testing.T) {
+	resetCache(t)
+	code, _, stderr := runCmd(t, "", "set", "-ttl", "1h", "a", "1")
+	require.Equal(t, exitOK, code, stderr)
+	code, _, stderr = runCmd(t, "", "set", "b", "2")
+	require.Equal(t, exitOK, code, stderr)
+
+	path := filepath.Join(t.TempDir(), "cache.dump")
+	code, _, stderr = runCmd(t, "", "dump", "-o", path, "*")
+	require.Equal(t, exitOK, code, stderr)
+
+	resetCache(t)
+	code, _, stderr = runCmd(t, "", "restore", path)
+	require.Equal(t, exitOK, code, stderr)
+	for key, want := range map[string]string{"a": "1\n", "b": "2\n"} {
+		code, stdout, _ := runCmd(t, "", "get", key)
+		require.Equal(t, exitOK, code)
+		assert.Equal(t, want, stdout)
+	}
+
+	// Existing keys are kept unless -replace is given.
+	dump, err := os.ReadFile(path)
+	require.NoError(t, err)
+	code, _, stderr = runCmd(t, "", "set", "a", "changed")
+	require.Equal(t, exitOK, code, stderr)
+	code, _, stderr = runCmd(t, string(dump), "restore")
+	require.Equal(t, exitOK, code, stderr)
+	_, stdout, _ := runCmd(t, "", "get", "a")
+	assert.Equal(t, "changed\n", stdout)
+	code, _, stderr = runCmd(t, string(dump), "restore", "-replace")
+	require.Equal(t, exitOK, code, stderr)
+	_, stdout, _ = runCmd(t, "", "get", "a")
+	assert.Equal(t, "1\n", stdout)
+
+	code, _, _ = runCmd(t, "not a dump", "restore")
+	assert.Equal(t, exitError, code)
+}
+
+func TestRun_Patterns(t *testing.T) {
+	resetCache(t)
+	for _, key := range []string{"user:1", "user:2", "order:1"} {
+		code, _, stderr := runCmd(t, "", "set", key, "v")
+		require.Equal(t, exitOK, code, stderr)
+	}
+
+	code, stdout, _ := runCmd(t, "", "count", "user:*")
+	require.Equal(t, exitOK, code)
+	assert.Equal(t, "2\n", stdout)
+
+	code, _, stderr := runCmd(t, "", "delkeys", "user:*")
+	require.Equal(t, exitOK, code, stderr)
+	code, stdout, _ = runCmd(t, "", "count", "*")
+	require.Equal(t, exitOK, code)
+	assert.Equal(t, "1\n", stdout)
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, exitOK},
+		{"status", exitStatus(exitNotFound), exitNotFound},
+		{"usage", &usageError{msg: "bad"}, exitUsage},
+		{"not found", fmt.Errorf("wrapped: %w", cache.ErrKeyNotFound), exitNotFound},
+		{"not supported", fmt.Errorf("wrapped: %w", cache.ErrNotSupported), exitNotSupported},
+		{"pattern matching not supported", cache.ErrPatternMatchingNotSupported, exitNotSupported},
+		{"other", errors.New("boom"), exitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, exitCode(tt.err))
+		})
+	}
+}
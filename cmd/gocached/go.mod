@@ -0,0 +1,37 @@
+module github.com/bartventer/gocache/cmd/gocached
+
+go 1.22
+
+toolchain go1.22.4
+
+replace (
+	github.com/bartventer/gocache => ../../
+	github.com/bartventer/gocache/memcache => ../../memcache
+	github.com/bartventer/gocache/ramcache => ../../ramcache
+	github.com/bartventer/gocache/redis => ../../redis
+	github.com/bartventer/gocache/rediscluster => ../../rediscluster
+	github.com/bartventer/gocache/redisring => ../../redisring
+	github.com/bartventer/gocache/redissentinel => ../../redissentinel
+)
+
+require (
+	github.com/bartventer/gocache v1.15.0
+	github.com/bartventer/gocache/memcache v1.15.0
+	github.com/bartventer/gocache/ramcache v1.15.0
+	github.com/bartventer/gocache/redis v1.15.0
+	github.com/bartventer/gocache/rediscluster v1.15.0
+	github.com/bartventer/gocache/redisring v1.15.0
+	github.com/bartventer/gocache/redissentinel v1.15.0
+	github.com/stretchr/testify v1.9.0
+)
+
+require (
+	github.com/bradfitz/gomemcache v0.0.0-20230905024940-24af94b03874 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/mitchellh/mapstructure v1.5.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/redis/go-redis/v9 v9.6.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
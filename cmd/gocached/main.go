@@ -0,0 +1,55 @@
+/*
+Command gocached serves any cache supported by gocache over HTTP.
+
+It opens the cache with [cache.OpenCache] and serves it with the handler from
+[cachehttp], so services written in other languages can share the same cache
+semantics. See the [cachehttp] package documentation for the endpoints and status codes.
+
+# Usage
+
+	gocached [flags]
+
+The flags are:
+
+	-url string
+	    URL of the cache to serve. Defaults to the GOCACHE_URL environment variable.
+	-addr string
+	    Address to listen on. Defaults to ":8080".
+	-maxbodysize int
+	    Maximum size of a request body, in bytes. Defaults to 1MiB.
+	-shutdowntimeout duration
+	    Time to wait for in-flight requests on shutdown. Defaults to 10s.
+
+If the GOCACHED_TOKEN environment variable is set, requests must carry it as a bearer
+token (see [cachehttp.Options.Token]). The token is read from the environment rather
+than a flag so that it does not show up in process listings.
+
+The server shuts down gracefully on SIGINT or SIGTERM.
+
+# Examples
+
+	GOCACHE_URL=redis://localhost:6379 gocached -addr :8080
+
+	curl -X PUT -H 'X-Cache-TTL: 1h' --data-binary hello localhost:8080/keys/greeting
+	curl localhost:8080/keys/greeting
+	curl 'localhost:8080/count?pattern=user:*'
+*/
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := run(ctx, os.Args[1:], os.Stderr)
+	stop()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gocached: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/pkg/cachehttp"
+
+	// Register the drivers.
+	_ "github.com/bartventer/gocache/memcache"
+	_ "github.com/bartventer/gocache/ramcache"
+	_ "github.com/bartventer/gocache/redis"
+	_ "github.com/bartventer/gocache/rediscluster"
+	_ "github.com/bartventer/gocache/redisring"
+	_ "github.com/bartventer/gocache/redissentinel"
+)
+
+// Environment variables.
+const (
+	urlEnvVar   = "GOCACHE_URL"    // urlEnvVar holds the default cache URL.
+	tokenEnvVar = "GOCACHED_TOKEN" // tokenEnvVar holds the bearer token.
+)
+
+// config is the server configuration.
+type config struct {
+	url             string        // url is the cache URL.
+	addr            string        // addr is the address to listen on.
+	token           string        // token is the bearer token; empty disables authentication.
+	maxBodySize     int64         // maxBodySize is the maximum size of a request body.
+	shutdownTimeout time.Duration // shutdownTimeout bounds the graceful shutdown.
+}
+
+// parseConfig parses the command-line arguments and environment into a config.
+func parseConfig(args []string, stderr io.Writer) (config, error) {
+	var cfg config
+	fs := flag.NewFlagSet("gocached", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&cfg.url, "url", os.Getenv(urlEnvVar), "URL of the cache to serve (default $"+urlEnvVar+")")
+	fs.StringVar(&cfg.addr, "addr", ":8080", "address to listen on")
+	fs.Int64Var(&cfg.maxBodySize, "maxbodysize", cachehttp.DefaultMaxBodySize, "maximum size of a request body, in bytes")
+	fs.DurationVar(&cfg.shutdownTimeout, "shutdowntimeout", 10*time.Second, "time to wait for in-flight requests on shutdown")
+	if err := fs.Parse(args); err != nil {
+		return config{}, err
+	}
+	if fs.NArg() > 0 {
+		return config{}, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	if cfg.url == "" {
+		return config{}, errors.New("no cache URL: use -url or set " + urlEnvVar)
+	}
+	cfg.token = os.Getenv(tokenEnvVar)
+	return cfg, nil
+}
+
+// run parses the arguments and serves the cache until ctx is done.
+func run(ctx context.Context, args []string, stderr io.Writer) error {
+	cfg, err := parseConfig(args, stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	ln, err := net.Listen("tcp", cfg.addr)
+	if err != nil {
+		return err
+	}
+	return serve(ctx, cfg, ln, log.New(stderr, "gocached: ", log.LstdFlags))
+}
+
+// serve opens the cache and serves it on ln until ctx is done, then shuts down gracefully.
+func serve(ctx context.Context, cfg config, ln net.Listener, logger *log.Logger) error {
+	c, err := cache.OpenCache(ctx, cfg.url)
+	if err != nil {
+		ln.Close()
+		return err
+	}
+	defer c.Close()
+
+	srv := &http.Server{
+		Handler: cachehttp.NewHandler(c, &cachehttp.Options{
+			Token:       cfg.token,
+			MaxBodySize: cfg.maxBodySize,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+		ErrorLog:          logger,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Printf("serving %s on %s", redactURL(cfg.url), ln.Addr())
+		errCh <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	logger.Print("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("error shutting down: %w", err)
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// redactURL returns the URL with any password replaced, for logging.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return "<invalid URL>"
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,278 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/pkg/clock"
+	"github.com/bartventer/gocache/pkg/driver"
+)
+
+// Constants for the dump format described in the package documentation.
+const (
+	dumpMagic   = "GCDUMP"
+	dumpVersion = 1
+
+	// maxDumpFieldSize is the maximum size of a key or value in a dump, matching the
+	// Redis limit of 512MiB.
+	maxDumpFieldSize = 512 << 20
+)
+
+// ErrInvalidDump is returned when a dump stream is malformed.
+var ErrInvalidDump = errors.New("gocache: invalid dump")
+
+// DumpOptions are the options for [GenericCache.DumpWithOptions].
+type DumpOptions struct {
+	// Clock tells the time the dump is created at, against which [RestoreOptions.AdjustTTL]
+	// measures the time elapsed. If not set, the system clock is used.
+	Clock clock.Clock
+}
+
+// RestoreOptions are the options for [GenericCache.Restore].
+type RestoreOptions struct {
+	// Replace overwrites keys that already exist in the cache. If false, existing
+	// keys are left untouched.
+	Replace bool
+
+	// AdjustTTL reduces the TTL of each entry by the time elapsed since the dump was
+	// created. Entries that would have expired in the meantime are skipped.
+	AdjustTTL bool
+
+	// Clock tells the current time for AdjustTTL. It should be the clock the dump was
+	// created with. If not set, the system clock is used.
+	Clock clock.Clock
+}
+
+// dumpWriter writes entries in the dump format.
+type dumpWriter struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+}
+
+// newDumpWriter writes the dump header to w and returns a writer for the records.
+func newDumpWriter(w io.Writer, created time.Time) (*dumpWriter, error) {
+	dw := &dumpWriter{w: bufio.NewWriter(w)}
+	if _, err := dw.w.WriteString(dumpMagic); err != nil {
+		return nil, err
+	}
+	if err := dw.w.WriteByte(dumpVersion); err != nil {
+		return nil, err
+	}
+	if err := dw.writeUvarint(uint64(created.UnixMilli())); err != nil {
+		return nil, err
+	}
+	return dw, nil
+}
+
+func (dw *dumpWriter) writeUvarint(v uint64) error {
+	n := binary.PutUvarint(dw.buf[:], v)
+	_, err := dw.w.Write(dw.buf[:n])
+	return err
+}
+
+func (dw *dumpWriter) writeBytes(b []byte) error {
+	if err := dw.writeUvarint(uint64(len(b))); err != nil {
+		return err
+	}
+	_, err := dw.w.Write(b)
+	return err
+}
+
+// Write writes a record. A positive TTL of less than a millisecond is written as one
+// millisecond, since a zero TTL means no expiry.
+func (dw *dumpWriter) Write(key string, value []byte, ttl time.Duration) error {
+	if err := dw.writeBytes([]byte(key)); err != nil {
+		return err
+	}
+	if err := dw.writeBytes(value); err != nil {
+		return err
+	}
+	var ms int64
+	if ttl > 0 {
+		ms = max(ttl.Milliseconds(), 1)
+	}
+	return dw.writeUvarint(uint64(ms))
+}
+
+// Flush flushes any buffered data to the underlying writer.
+func (dw *dumpWriter) Flush() error {
+	return dw.w.Flush()
+}
+
+// dumpRecord is a record read from a dump.
+type dumpRecord struct {
+	Key   string
+	Value []byte
+	TTL   time.Duration
+}
+
+// dumpReader reads entries in the dump format.
+type dumpReader struct {
+	r       *bufio.Reader
+	created time.Time
+}
+
+// newDumpReader reads and validates the dump header from r and returns a reader
+// for the records.
+func newDumpReader(r io.Reader) (*dumpReader, error) {
+	dr := &dumpReader{r: bufio.NewReader(r)}
+	header := make([]byte, len(dumpMagic)+1)
+	if _, err := io.ReadFull(dr.r, header); err != nil {
+		return nil, fmt.Errorf("%w: reading header: %w", ErrInvalidDump, err)
+	}
+	if !bytes.Equal(header[:len(dumpMagic)], []byte(dumpMagic)) {
+		return nil, fmt.Errorf("%w: bad magic %q", ErrInvalidDump, header[:len(dumpMagic)])
+	}
+	if v := header[len(dumpMagic)]; v != dumpVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidDump, v)
+	}
+	created, err := binary.ReadUvarint(dr.r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading creation time: %w", ErrInvalidDump, err)
+	}
+	dr.created = time.UnixMilli(int64(created)) //nolint:gosec // timestamps fit in an int64
+	return dr, nil
+}
+
+func (dr *dumpReader) readBytes() ([]byte, error) {
+	n, err := binary.ReadUvarint(dr.r)
+	if err != nil {
+		return nil, err
+	}
+	if n > maxDumpFieldSize {
+		return nil, fmt.Errorf("%w: field of %d bytes exceeds limit", ErrInvalidDump, n)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(dr.r, b); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return b, nil
+}
+
+// Next reads the next record. It returns [io.EOF] when there are no more records.
+func (dr *dumpReader) Next() (dumpRecord, error) {
+	key, err := dr.readBytes()
+	if err != nil {
+		if err == io.EOF {
+			return dumpRecord{}, io.EOF
+		}
+		return dumpRecord{}, fmt.Errorf("%w: reading key: %w", ErrInvalidDump, err)
+	}
+	value, err := dr.readBytes()
+	if err != nil {
+		return dumpRecord{}, fmt.Errorf("%w: reading value of key %s: %w", ErrInvalidDump, key, unexpectedEOF(err))
+	}
+	ttl, err := binary.ReadUvarint(dr.r)
+	if err != nil {
+		return dumpRecord{}, fmt.Errorf("%w: reading TTL of key %s: %w", ErrInvalidDump, key, unexpectedEOF(err))
+	}
+	return dumpRecord{
+		Key:   string(key),
+		Value: value,
+		TTL:   time.Duration(ttl) * time.Millisecond, //nolint:gosec // TTLs fit in an int64
+	}, nil
+}
+
+// unexpectedEOF converts [io.EOF] into [io.ErrUnexpectedEOF], for use when a record is truncated.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// Dump writes all entries whose key matches the pattern to w, in a portable streaming
+// format that can be loaded into any cache with [GenericCache.Restore]. Each entry is
+// written with its value and remaining TTL.
+//
+// It returns an error wrapping [ErrNotSupported] if the underlying driver does not
+// implement [driver.Scanner].
+func (c *GenericCache[K]) Dump(ctx context.Context, w io.Writer, pattern K) error {
+	return c.DumpWithOptions(ctx, w, pattern, nil)
+}
+
+// DumpWithOptions writes the entries whose key matches the pattern to w, as
+// [GenericCache.Dump] does. A nil opts is equivalent to a zero [DumpOptions].
+func (c *GenericCache[K]) DumpWithOptions(ctx context.Context, w io.Writer, pattern K, opts *DumpOptions) error {
+	s, ok := c.driver.(driver.Scanner[K])
+	if !ok {
+		return gcerrors.New(errors.Join(ErrNotSupported, errors.New("Dump operation not supported")))
+	}
+	if opts == nil {
+		opts = &DumpOptions{}
+	}
+	dw, err := newDumpWriter(w, clock.OrReal(opts.Clock).Now())
+	if err != nil {
+		return gcerrors.New(fmt.Errorf("error writing dump header: %w", err))
+	}
+	err = s.Scan(ctx, pattern, func(e driver.Entry[K]) error {
+		return dw.Write(string(e.Key), e.Value, e.TTL)
+	})
+	if err != nil {
+		return gcerrors.New(fmt.Errorf("error dumping keys: %w", err))
+	}
+	if err := dw.Flush(); err != nil {
+		return gcerrors.New(fmt.Errorf("error writing dump: %w", err))
+	}
+	return nil
+}
+
+// Restore loads entries written by [GenericCache.Dump] from r into the cache.
+// A nil opts is equivalent to a zero [RestoreOptions].
+//
+// It returns an error wrapping [ErrInvalidDump] if the stream is malformed. Entries
+// read before the error are kept.
+func (c *GenericCache[K]) Restore(ctx context.Context, r io.Reader, opts *RestoreOptions) error {
+	if opts == nil {
+		opts = &RestoreOptions{}
+	}
+	dr, err := newDumpReader(r)
+	if err != nil {
+		return gcerrors.New(err)
+	}
+	elapsed := clock.OrReal(opts.Clock).Now().Sub(dr.created)
+	for {
+		if err := ctx.Err(); err != nil {
+			return gcerrors.New(err)
+		}
+		rec, err := dr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return gcerrors.New(err)
+		}
+		ttl := rec.TTL
+		if opts.AdjustTTL && ttl > 0 {
+			ttl -= elapsed
+			if ttl <= 0 {
+				continue
+			}
+		}
+		key := K(rec.Key)
+		if !opts.Replace {
+			exists, err := c.driver.Exists(ctx, key)
+			if err != nil {
+				return gcerrors.New(fmt.Errorf("error restoring key %s: %w", key, err))
+			}
+			if exists {
+				continue
+			}
+		}
+		if ttl > 0 {
+			err = c.driver.SetWithTTL(ctx, key, rec.Value, ttl)
+		} else {
+			err = c.driver.Set(ctx, key, rec.Value)
+		}
+		if err != nil {
+			return gcerrors.New(fmt.Errorf("error restoring key %s: %w", key, err))
+		}
+	}
+}
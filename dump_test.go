@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/bartventer/gocache/internal/glob"
+	"github.com/bartventer/gocache/pkg/clock/clocktest"
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapCache is a minimal [driver.Cache] and [driver.Scanner] used to test dumps.
+type mapCache struct {
+	driver.Cache[string] // unimplemented methods panic
+	entries              map[string]driver.Entry[string]
+	lists                map[string][][]byte // lists holds the lists written by scripts.
+}
+
+func newMapCache() *mapCache {
+	return &mapCache{
+		entries: make(map[string]driver.Entry[string]),
+		lists:   make(map[string][][]byte),
+	}
+}
+
+func (m *mapCache) Exists(_ context.Context, key string) (bool, error) {
+	_, ok := m.entries[key]
+	return ok, nil
+}
+
+func (m *mapCache) Set(ctx context.Context, key string, value interface{}) error {
+	return m.SetWithTTL(ctx, key, value, 0)
+}
+
+func (m *mapCache) SetWithTTL(_ context.Context, key string, value interface{}, ttl time.Duration) error {
+	m.entries[key] = driver.Entry[string]{Key: key, Value: value.([]byte), TTL: ttl}
+	return nil
+}
+
+func (m *mapCache) Scan(_ context.Context, pattern string, fn func(driver.Entry[string]) error) error {
+	keys := make([]string, 0, len(m.entries))
+	for key := range m.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !glob.Match(pattern, key) {
+			continue
+		}
+		if err := fn(m.entries[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestDumpRestore(t *testing.T) {
+	ctx := context.Background()
+	src := newMapCache()
+	src.entries["user:1"] = driver.Entry[string]{Key: "user:1", Value: []byte("alice")}
+	src.entries["user:2"] = driver.Entry[string]{Key: "user:2", Value: []byte{0x00, 0xff}, TTL: time.Hour}
+	src.entries["user:3"] = driver.Entry[string]{Key: "user:3", Value: []byte{}}
+	src.entries["session:1"] = driver.Entry[string]{Key: "session:1", Value: []byte("s")}
+
+	var buf bytes.Buffer
+	require.NoError(t, NewCache[string](src).Dump(ctx, &buf, "user:*"))
+
+	dst := newMapCache()
+	dst.entries["user:1"] = driver.Entry[string]{Key: "user:1", Value: []byte("existing")}
+	require.NoError(t, NewCache[string](dst).Restore(ctx, bytes.NewReader(buf.Bytes()), nil))
+
+	assert.Equal(t, map[string]driver.Entry[string]{
+		"user:1": {Key: "user:1", Value: []byte("existing")},
+		"user:2": {Key: "user:2", Value: []byte{0x00, 0xff}, TTL: time.Hour},
+		"user:3": {Key: "user:3", Value: []byte{}},
+	}, dst.entries)
+
+	require.NoError(t, NewCache[string](dst).Restore(ctx, bytes.NewReader(buf.Bytes()), &RestoreOptions{Replace: true}))
+	assert.Equal(t, []byte("alice"), dst.entries["user:1"].Value)
+}
+
+func TestDumpRestore_SubMillisecondTTL(t *testing.T) {
+	ctx := context.Background()
+	src := newMapCache()
+	src.entries["expiring"] = driver.Entry[string]{Key: "expiring", Value: []byte("a"), TTL: 500 * time.Microsecond}
+
+	var buf bytes.Buffer
+	require.NoError(t, NewCache[string](src).Dump(ctx, &buf, "*"))
+	dst := newMapCache()
+	require.NoError(t, NewCache[string](dst).Restore(ctx, &buf, nil))
+
+	require.Contains(t, dst.entries, "expiring")
+	assert.Equal(t, time.Millisecond, dst.entries["expiring"].TTL, "expected the key to still expire")
+}
+
+func TestRestore_AdjustTTL(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	dw, err := newDumpWriter(&buf, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.NoError(t, dw.Write("expired", []byte("a"), 30*time.Second))
+	require.NoError(t, dw.Write("live", []byte("b"), time.Hour))
+	require.NoError(t, dw.Write("persistent", []byte("c"), 0))
+	require.NoError(t, dw.Flush())
+
+	dst := newMapCache()
+	require.NoError(t, NewCache[string](dst).Restore(ctx, &buf, &RestoreOptions{AdjustTTL: true}))
+
+	assert.NotContains(t, dst.entries, "expired")
+	assert.Contains(t, dst.entries, "persistent")
+	assert.Equal(t, time.Duration(0), dst.entries["persistent"].TTL)
+	require.Contains(t, dst.entries, "live")
+	assert.Less(t, dst.entries["live"].TTL, time.Hour)
+	assert.Greater(t, dst.entries["live"].TTL, 58*time.Minute)
+}
+
+func TestDumpRestore_Clock(t *testing.T) {
+	ctx := context.Background()
+	clk := clocktest.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	src := newMapCache()
+	src.entries["short"] = driver.Entry[string]{Key: "short", Value: []byte("a"), TTL: time.Minute}
+	src.entries["long"] = driver.Entry[string]{Key: "long", Value: []byte("b"), TTL: time.Hour}
+
+	var buf bytes.Buffer
+	require.NoError(t, NewCache[string](src).DumpWithOptions(ctx, &buf, "*", &DumpOptions{Clock: clk}))
+	clk.Advance(2 * time.Minute)
+	dst := newMapCache()
+	require.NoError(t, NewCache[string](dst).Restore(ctx, &buf, &RestoreOptions{AdjustTTL: true, Clock: clk}))
+
+	assert.NotContains(t, dst.entries, "short")
+	require.Contains(t, dst.entries, "long")
+	assert.Equal(t, 58*time.Minute, dst.entries["long"].TTL)
+}
+
+func TestRestore_InvalidDump(t *testing.T) {
+	ctx := context.Background()
+
+	var header bytes.Buffer
+	dw, err := newDumpWriter(&header, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, dw.Flush())
+
+	var valid bytes.Buffer
+	dw, err = newDumpWriter(&valid, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, dw.Write("key", []byte("value"), 0))
+	require.NoError(t, dw.Flush())
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "bad magic", data: []byte("NOTADUMP")},
+		{name: "bad version", data: append([]byte(dumpMagic), 99, 0)},
+		{name: "truncated record", data: valid.Bytes()[:valid.Len()-3]},
+		{name: "oversized field", data: append(header.Bytes(), 0xff, 0xff, 0xff, 0xff, 0x0f)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewCache[string](newMapCache()).Restore(ctx, bytes.NewReader(tt.data), nil)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidDump), "expected ErrInvalidDump, got %v", err)
+		})
+	}
+}
+
+// failingCache is a [mapCache] whose Exists and Set calls fail.
+type failingCache struct {
+	*mapCache
+	err error
+}
+
+func (f *failingCache) Exists(context.Context, string) (bool, error) { return false, f.err }
+
+func (f *failingCache) Set(context.Context, string, interface{}) error { return f.err }
+
+func TestRestore_DriverError(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	dw, err := newDumpWriter(&buf, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, dw.Write("key", []byte("value"), 0))
+	require.NoError(t, dw.Flush())
+
+	errFailed := errors.New("failed")
+	for _, opts := range []*RestoreOptions{nil, {Replace: true}} {
+		c := NewCache[string](&failingCache{mapCache: newMapCache(), err: errFailed})
+		err := c.Restore(ctx, bytes.NewReader(buf.Bytes()), opts)
+		require.ErrorIs(t, err, errFailed)
+		assert.ErrorContains(t, err, "error restoring key key")
+	}
+}
+
+func TestDump_NotSupported(t *testing.T) {
+	var c struct{ driver.Cache[string] }
+	err := NewCache[string](c).Dump(context.Background(), &bytes.Buffer{}, "*")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNotSupported)
+}
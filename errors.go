@@ -15,4 +15,15 @@ var (
 
 	// ErrInvalidTTL is returned when an invalid TTL is provided.
 	ErrInvalidTTL = errors.New("gocache: invalid TTL")
+
+	// ErrNotSupported is returned when an operation is not supported by the cache implementation.
+	ErrNotSupported = errors.New("gocache: operation not supported")
+
+	// ErrMaxKeysExceeded is returned when more keys match a pattern than the maximum
+	// number of keys allowed to be deleted. See [DelKeysOptions].
+	ErrMaxKeysExceeded = errors.New("gocache: maximum number of keys exceeded")
+
+	// ErrScriptNotFound is returned when running a script that is not registered. See
+	// [RegisterScript].
+	ErrScriptNotFound = errors.New("gocache: script not found")
 )
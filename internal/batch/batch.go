@@ -0,0 +1,109 @@
+// Package batch collects items submitted concurrently into batches, for drivers that
+// pipeline commands issued by concurrent callers.
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned by [Batcher.Do] once the batcher is closed.
+var ErrClosed = errors.New("batch: batcher closed")
+
+// batch is a set of items flushed together.
+type batch[T any] struct {
+	items []T           // items are the submitted items.
+	done  chan struct{} // done is closed when the items have been flushed.
+}
+
+// Batcher collects the items passed to [Batcher.Do] into batches, flushed when they hold
+// maxSize items, or window after their first item was added.
+type Batcher[T any] struct {
+	window  time.Duration // window is the maximum time an item waits for others.
+	maxSize int           // maxSize is the maximum number of items in a batch.
+	flush   func([]T)     // flush processes a batch.
+
+	mu      sync.Mutex  // mu guards the fields below.
+	current *batch[T]   // current is the batch being collected; nil if none.
+	timer   *time.Timer // timer flushes the current batch when its window elapses.
+	closed  bool        // closed reports whether Close was called.
+}
+
+// New creates a new Batcher that calls flush with each batch of at most maxSize items,
+// at most window after the first item of the batch was added. Batches may be flushed
+// concurrently.
+func New[T any](window time.Duration, maxSize int, flush func(items []T)) *Batcher[T] {
+	return &Batcher[T]{window: window, maxSize: max(maxSize, 1), flush: flush}
+}
+
+// Do adds item to the current batch and waits until the batch has been flushed. If ctx
+// is done first, Do returns its error without waiting; the item is flushed with its batch
+// nonetheless. Do returns [ErrClosed] once the batcher is closed.
+func (b *Batcher[T]) Do(ctx context.Context, item T) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrClosed
+	}
+	if b.current == nil {
+		b.current = &batch[T]{items: make([]T, 0, b.maxSize), done: make(chan struct{})}
+		current := b.current
+		b.timer = time.AfterFunc(b.window, func() { b.flushIf(current) })
+	}
+	current := b.current
+	current.items = append(current.items, item)
+	if len(current.items) < b.maxSize {
+		b.mu.Unlock()
+		select {
+		case <-current.done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	b.current = nil
+	b.timer.Stop()
+	b.mu.Unlock()
+	b.run(current)
+	return nil
+}
+
+// Close stops the timer of the batch being collected, if any, and flushes it before
+// returning. Later calls to Do return [ErrClosed].
+func (b *Batcher[T]) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	current := b.current
+	b.current = nil
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.mu.Unlock()
+	if current != nil {
+		b.run(current)
+	}
+}
+
+// flushIf flushes bt if it's still being collected.
+func (b *Batcher[T]) flushIf(bt *batch[T]) {
+	b.mu.Lock()
+	if b.current != bt {
+		b.mu.Unlock()
+		return
+	}
+	b.current = nil
+	b.mu.Unlock()
+	b.run(bt)
+}
+
+// run flushes bt and wakes the callers waiting for it.
+func (b *Batcher[T]) run(bt *batch[T]) {
+	defer close(bt.done)
+	b.flush(bt.items)
+}
@@ -0,0 +1,124 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatcher_MaxSize(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]int
+	b := New(time.Hour, 3, func(items []int) {
+		mu.Lock()
+		defer mu.Unlock()
+		batches = append(batches, append([]int(nil), items...))
+	})
+
+	var wg sync.WaitGroup
+	for i := range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = b.Do(context.Background(), i)
+		}()
+	}
+	wg.Wait()
+
+	if len(batches) != 2 {
+		t.Fatalf("got %d batches, want 2", len(batches))
+	}
+	for _, batch := range batches {
+		if len(batch) != 3 {
+			t.Errorf("got batch %v, want 3 items", batch)
+		}
+	}
+}
+
+func TestBatcher_Window(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]int
+	b := New(10*time.Millisecond, 100, func(items []int) {
+		mu.Lock()
+		defer mu.Unlock()
+		batches = append(batches, append([]int(nil), items...))
+	})
+
+	start := time.Now()
+	_ = b.Do(context.Background(), 1)
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("Do returned after %v, want at least the window", elapsed)
+	}
+	_ = b.Do(context.Background(), 2)
+	if len(batches) != 2 || len(batches[0]) != 1 || len(batches[1]) != 1 {
+		t.Errorf("got batches %v, want [[1] [2]]", batches)
+	}
+}
+
+func TestBatcher_FlushedBeforeReturn(t *testing.T) {
+	b := New(time.Millisecond, 10, func(items []*int) {
+		for _, p := range items {
+			*p = 1
+		}
+	})
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var v int
+			_ = b.Do(context.Background(), &v)
+			if v != 1 {
+				t.Error("Do returned before the item was flushed")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestBatcher_Canceled(t *testing.T) {
+	var flushed sync.WaitGroup
+	flushed.Add(1)
+	b := New(time.Hour, 10, func([]int) { flushed.Done() })
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Do(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Do() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	b.Close()
+	flushed.Wait()
+}
+
+func TestBatcher_Close(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]int
+	b := New(time.Hour, 10, func(items []int) {
+		mu.Lock()
+		defer mu.Unlock()
+		batches = append(batches, append([]int(nil), items...))
+	})
+	done := make(chan error)
+	go func() { done <- b.Do(context.Background(), 1) }()
+	for {
+		b.mu.Lock()
+		pending := b.current != nil
+		b.mu.Unlock()
+		if pending {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	b.Close()
+	if err := <-done; err != nil {
+		t.Errorf("Do() = %v, want nil", err)
+	}
+	if len(batches) != 1 || len(batches[0]) != 1 {
+		t.Errorf("got batches %v, want [[1]]", batches)
+	}
+	if err := b.Do(context.Background(), 2); !errors.Is(err, ErrClosed) {
+		t.Errorf("Do() after Close = %v, want %v", err, ErrClosed)
+	}
+}
@@ -0,0 +1,69 @@
+package batch
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// call is a function queueing commands on a pipeline.
+type call struct {
+	fn       func(redis.Cmdable) // fn queues the commands.
+	canceled atomic.Bool         // canceled reports whether the caller stopped waiting.
+}
+
+// Pipeline queues the commands issued by concurrent callers into shared go-redis
+// pipelines, each sent in a single round trip.
+type Pipeline struct {
+	batcher *Batcher[*call]
+	ctx     context.Context    // ctx is the context the pipelines are executed with.
+	cancel  context.CancelFunc // cancel cancels ctx once the pipeline is closed.
+}
+
+// NewPipeline creates a Pipeline executing its pipelines with c, each holding the
+// commands queued within window of the first one, up to maxSize commands. A cluster
+// client sends the commands of each pipeline to the nodes owning their keys.
+func NewPipeline(c redis.Cmdable, window time.Duration, maxSize int) *Pipeline {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pipeline{ctx: ctx, cancel: cancel}
+	p.batcher = New(window, maxSize, func(calls []*call) {
+		pipe := c.Pipeline()
+		for _, call := range calls {
+			// The commands of callers that stopped waiting are not sent.
+			if !call.canceled.Load() {
+				call.fn(pipe)
+			}
+		}
+		if pipe.Len() == 0 {
+			return
+		}
+		// Errors are set on the commands.
+		_, _ = pipe.Exec(p.ctx)
+	})
+	return p
+}
+
+// Do queues the commands issued by fn on the next pipeline, and waits until the pipeline
+// has been executed. If ctx is done before, the commands are not sent if the pipeline
+// hasn't been executed yet, and the context error is returned. Do returns [ErrClosed]
+// once the pipeline is closed.
+func (p *Pipeline) Do(ctx context.Context, fn func(c redis.Cmdable)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c := &call{fn: fn}
+	if err := p.batcher.Do(ctx, c); err != nil {
+		c.canceled.Store(true)
+		return err
+	}
+	return nil
+}
+
+// Close executes the commands already queued, and stops the pipeline. Later calls to Do
+// return [ErrClosed].
+func (p *Pipeline) Close() {
+	p.batcher.Close()
+	p.cancel()
+}
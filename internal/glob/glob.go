@@ -0,0 +1,113 @@
+// Package glob implements Redis-compatible glob-style pattern matching.
+//
+// The supported syntax mirrors the one used by the Redis KEYS, SCAN and PSUBSCRIBE
+// commands:
+//
+//	h?llo    matches hello, hallo and hxllo
+//	h*llo    matches hllo and heeeello
+//	h[ae]llo matches hello and hallo, but not hillo
+//	h[^e]llo matches hallo, hbllo, ... but not hello
+//	h[a-b]llo matches hallo and hbllo
+//
+// Use \ to escape special characters if you want to match them verbatim.
+// Matching is performed byte by byte, as Redis does.
+package glob
+
+// Match reports whether the string s matches the glob pattern.
+//
+// It is a port of the Redis stringmatchlen function.
+func Match(pattern, s string) bool { //nolint:gocyclo,cyclop // mirrors the Redis implementation
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			// Collapse consecutive stars.
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if Match(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+			s = s[1:]
+		case '[':
+			if len(s) == 0 {
+				return false
+			}
+			var matched bool
+			matched, pattern = matchClass(pattern[1:], s[0])
+			if !matched {
+				return false
+			}
+			s = s[1:]
+			// matchClass leaves pattern on the closing bracket (or at the end).
+			if len(pattern) == 0 {
+				return len(s) == 0
+			}
+		case '\\':
+			if len(pattern) >= 2 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if len(s) == 0 || pattern[0] != s[0] {
+				return false
+			}
+			s = s[1:]
+		}
+		pattern = pattern[1:]
+	}
+	return len(s) == 0
+}
+
+// matchClass matches c against the character class at the start of pattern (just
+// after the opening bracket). It returns whether c matched and the pattern positioned
+// at the closing bracket.
+func matchClass(pattern string, c byte) (bool, string) {
+	not := len(pattern) > 0 && pattern[0] == '^'
+	if not {
+		pattern = pattern[1:]
+	}
+	matched := false
+	for len(pattern) > 0 {
+		switch {
+		case pattern[0] == '\\' && len(pattern) >= 2:
+			pattern = pattern[1:]
+			if pattern[0] == c {
+				matched = true
+			}
+		case pattern[0] == ']':
+			if not {
+				matched = !matched
+			}
+			return matched, pattern
+		case len(pattern) >= 3 && pattern[1] == '-':
+			start, end := pattern[0], pattern[2]
+			if start > end {
+				start, end = end, start
+			}
+			pattern = pattern[2:]
+			if c >= start && c <= end {
+				matched = true
+			}
+		default:
+			if pattern[0] == c {
+				matched = true
+			}
+		}
+		pattern = pattern[1:]
+	}
+	// Unterminated class: Redis treats the end of the pattern as the closing bracket.
+	if not {
+		matched = !matched
+	}
+	return matched, pattern
+}
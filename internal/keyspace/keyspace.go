@@ -0,0 +1,158 @@
+// Package keyspace watches keys using Redis keyspace notifications. It is shared by the
+// Redis drivers.
+package keyspace
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/redis/go-redis/v9"
+)
+
+// Flags are the notify-keyspace-events flags required by Watch: keyspace events (K),
+// generic commands (g), string commands ($), expired events (x) and evicted events (e).
+const Flags = "Kg$xe"
+
+// ChannelPrefix returns the keyspace notification channel prefix for the database.
+func ChannelPrefix(db int) string {
+	return fmt.Sprintf("__keyspace@%d__:", db)
+}
+
+// MergeFlags returns the current notify-keyspace-events flags with the flags required by
+// Watch added. The "A" alias covers all event classes except K and E.
+func MergeFlags(current string) string {
+	merged := current
+	for _, f := range Flags {
+		if strings.ContainsRune(merged, f) {
+			continue
+		}
+		if f != 'K' && strings.ContainsRune(merged, 'A') {
+			continue
+		}
+		merged += string(f)
+	}
+	return merged
+}
+
+// Enable enables the keyspace notifications required by Watch on the server, preserving
+// any flags that are already configured.
+func Enable(ctx context.Context, client *redis.Client) error {
+	cfg, err := client.ConfigGet(ctx, "notify-keyspace-events").Result()
+	if err != nil {
+		return err
+	}
+	current := cfg["notify-keyspace-events"]
+	merged := MergeFlags(current)
+	if merged == current {
+		return nil
+	}
+	return client.ConfigSet(ctx, "notify-keyspace-events", merged).Err()
+}
+
+// EventType maps a keyspace notification payload to an event type. Set notifications
+// are reported as [driver.EventSet], del, unlink and evicted notifications as
+// [driver.EventDelete], and expired notifications as [driver.EventExpire]; other
+// notifications are not reported.
+func EventType(payload string) (driver.EventType, bool) {
+	switch payload {
+	case "set":
+		return driver.EventSet, true
+	case "del", "unlink", "evicted":
+		return driver.EventDelete, true
+	case "expired":
+		return driver.EventExpire, true
+	default:
+		return 0, false
+	}
+}
+
+// ForEachFunc calls fn for each server to watch, like
+// [redis.ClusterClient.ForEachMaster] and [redis.Ring.ForEachShard].
+type ForEachFunc func(ctx context.Context, fn func(ctx context.Context, client *redis.Client) error) error
+
+// Single returns a [ForEachFunc] for a single server.
+func Single(client *redis.Client) ForEachFunc {
+	return func(ctx context.Context, fn func(ctx context.Context, client *redis.Client) error) error {
+		return fn(ctx, client)
+	}
+}
+
+// Watch subscribes to the keyspace notifications of the keys matching pattern on every
+// server of forEach, since each server only publishes notifications for the keys it
+// stores. If enable is true, the notifications are first enabled with [Enable].
+//
+// The returned channel buffers [driver.WatchBufferSize] events. Events are dropped
+// while the buffer is full, so a slow consumer never stalls the subscriptions. The
+// channel is closed once ctx is done or all subscriptions are closed.
+func Watch[K driver.String](ctx context.Context, forEach ForEachFunc, db int, pattern string, enable bool) (<-chan driver.Event[K], error) {
+	prefix := ChannelPrefix(db)
+	var (
+		mu      sync.Mutex
+		pubsubs []*redis.PubSub
+	)
+	err := forEach(ctx, func(ctx context.Context, client *redis.Client) error {
+		if enable {
+			if err := Enable(ctx, client); err != nil {
+				return fmt.Errorf("error enabling keyspace notifications on %s: %w", client.Options().Addr, err)
+			}
+		}
+		pubsub := client.PSubscribe(ctx, prefix+pattern)
+		// Wait for the subscription to be confirmed so no events are missed.
+		if _, err := pubsub.Receive(ctx); err != nil {
+			pubsub.Close()
+			return fmt.Errorf("error subscribing to keyspace events on %s: %w", client.Options().Addr, err)
+		}
+		mu.Lock()
+		pubsubs = append(pubsubs, pubsub)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		for _, pubsub := range pubsubs {
+			pubsub.Close()
+		}
+		return nil, err
+	}
+
+	events := make(chan driver.Event[K], driver.WatchBufferSize)
+	var wg sync.WaitGroup
+	for _, pubsub := range pubsubs {
+		wg.Add(1)
+		go func(pubsub *redis.PubSub) {
+			defer wg.Done()
+			forward(ctx, pubsub.Channel(), prefix, events)
+			pubsub.Close()
+		}(pubsub)
+	}
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+	return events, nil
+}
+
+// forward forwards the keyspace notifications received on ch to events until ctx is done
+// or ch is closed. Delivery never blocks; events are dropped while events is full.
+func forward[K driver.String](ctx context.Context, ch <-chan *redis.Message, prefix string, events chan<- driver.Event[K]) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			typ, ok := EventType(msg.Payload)
+			if !ok {
+				continue
+			}
+			select {
+			case events <- driver.Event[K]{Type: typ, Key: K(strings.TrimPrefix(msg.Channel, prefix))}:
+			default:
+			}
+		}
+	}
+}
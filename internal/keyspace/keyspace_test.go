@@ -0,0 +1,86 @@
+package keyspace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestMergeFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		want    string
+	}{
+		{name: "disabled", current: "", want: "Kg$xe"},
+		{name: "already enabled", current: "Kg$xe", want: "Kg$xe"},
+		{name: "keyevent only", current: "Ex", want: "ExKg$e"},
+		{name: "all events", current: "AK", want: "AK"},
+		{name: "all keyevent events", current: "AE", want: "AEK"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MergeFlags(tt.current); got != tt.want {
+				t.Errorf("MergeFlags(%q) = %q, want %q", tt.current, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventType(t *testing.T) {
+	tests := []struct {
+		payload string
+		want    driver.EventType
+		wantOK  bool
+	}{
+		{payload: "set", want: driver.EventSet, wantOK: true},
+		{payload: "del", want: driver.EventDelete, wantOK: true},
+		{payload: "unlink", want: driver.EventDelete, wantOK: true},
+		{payload: "evicted", want: driver.EventDelete, wantOK: true},
+		{payload: "expired", want: driver.EventExpire, wantOK: true},
+		{payload: "expire", wantOK: false},
+		{payload: "rename_from", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.payload, func(t *testing.T) {
+			got, ok := EventType(tt.payload)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("EventType(%q) = (%v, %v), want (%v, %v)", tt.payload, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func Test_forward(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan *redis.Message)
+	events := make(chan driver.Event[string], 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		forward(ctx, ch, "__keyspace@0__:", events)
+	}()
+
+	ch <- &redis.Message{Channel: "__keyspace@0__:a", Payload: "set"}
+	ch <- &redis.Message{Channel: "__keyspace@0__:b", Payload: "expire"}
+	// The buffer is full, so this event must be dropped rather than block.
+	ch <- &redis.Message{Channel: "__keyspace@0__:c", Payload: "del"}
+	close(ch)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("forward blocked on a full events channel")
+	}
+	want := driver.Event[string]{Type: driver.EventSet, Key: "a"}
+	if got := <-events; got != want {
+		t.Errorf("event = %+v, want %+v", got, want)
+	}
+	if len(events) != 0 {
+		t.Errorf("got %d extra events, want 0", len(events))
+	}
+}
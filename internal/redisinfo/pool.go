@@ -0,0 +1,18 @@
+package redisinfo
+
+import (
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/redis/go-redis/v9"
+)
+
+// PoolStats converts the statistics of a go-redis connection pool.
+func PoolStats(s *redis.PoolStats) *driver.PoolStats {
+	return &driver.PoolStats{
+		Hits:       s.Hits,
+		Misses:     s.Misses,
+		Timeouts:   s.Timeouts,
+		TotalConns: s.TotalConns,
+		IdleConns:  s.IdleConns,
+		StaleConns: s.StaleConns,
+	}
+}
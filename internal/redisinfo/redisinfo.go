@@ -0,0 +1,35 @@
+// Package redisinfo parses the reply of the Redis INFO command, and converts the
+// statistics of go-redis connection pools, for the diagnostics of the Redis drivers.
+package redisinfo
+
+import "strings"
+
+// Parse parses the reply of the Redis INFO command into a map of section names to the
+// fields of that section. Section names are lower-cased, so "# Memory" becomes "memory".
+// Values are kept verbatim, including the comma-separated ones of the keyspace section.
+// Fields before the first section header are put in a section with an empty name.
+func Parse(info string) map[string]map[string]string {
+	sections := make(map[string]map[string]string)
+	section := ""
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if name, ok := strings.CutPrefix(line, "#"); ok {
+			section = strings.ToLower(strings.TrimSpace(name))
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields, ok := sections[section]
+		if !ok {
+			fields = make(map[string]string)
+			sections[section] = fields
+		}
+		fields[key] = value
+	}
+	return sections
+}
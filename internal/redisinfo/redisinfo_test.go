@@ -0,0 +1,33 @@
+package redisinfo
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	info := "# Memory\r\n" +
+		"used_memory:1024\r\n" +
+		"used_memory_human:1.00K\r\n" +
+		"\r\n" +
+		"# Stats\r\n" +
+		"evicted_keys:3\r\n" +
+		"\r\n" +
+		"# Keyspace\r\n" +
+		"db0:keys=2,expires=1,avg_ttl=1000\r\n"
+	want := map[string]map[string]string{
+		"memory":   {"used_memory": "1024", "used_memory_human": "1.00K"},
+		"stats":    {"evicted_keys": "3"},
+		"keyspace": {"db0": "keys=2,expires=1,avg_ttl=1000"},
+	}
+	if diff := cmp.Diff(want, Parse(info)); diff != "" {
+		t.Errorf("Parse() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	if got := Parse(""); len(got) != 0 {
+		t.Errorf("Parse(\"\") = %v, want empty", got)
+	}
+}
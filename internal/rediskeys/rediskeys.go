@@ -0,0 +1,269 @@
+// Package rediskeys scans and deletes the keys matching a pattern. It is shared by the
+// Redis drivers.
+package rediskeys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/internal/keyspace"
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/bartventer/gocache/pkg/keymod"
+	"github.com/redis/go-redis/v9"
+)
+
+// isWrongType reports whether err is a WRONGTYPE error, returned when a string command
+// is run against a key holding another data type.
+func isWrongType(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "WRONGTYPE")
+}
+
+// isUnknownCommand reports whether err is returned for a command the server doesn't
+// support, such as UNLINK before Redis 4.0.
+func isUnknownCommand(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "ERR unknown command")
+}
+
+// entries fetches the values and remaining TTLs of the keys in a single pipeline and
+// calls fn for each entry. Keys that no longer exist or do not hold string values are skipped.
+func entries[K driver.String](ctx context.Context, client redis.Cmdable, keys []string, fn func(driver.Entry[K]) error) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	getCmds := make([]*redis.StringCmd, len(keys))
+	ttlCmds := make([]*redis.DurationCmd, len(keys))
+	// Errors are checked per command below, since missing keys also fail the pipeline.
+	_, _ = client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			getCmds[i] = pipe.Get(ctx, key)
+			ttlCmds[i] = pipe.PTTL(ctx, key)
+		}
+		return nil
+	})
+	for i, key := range keys {
+		value, err := getCmds[i].Bytes()
+		if errors.Is(err, redis.Nil) || isWrongType(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error getting key %s: %w", key, err)
+		}
+		ttl, err := ttlCmds[i].Result()
+		if err != nil {
+			return fmt.Errorf("error getting TTL of key %s: %w", key, err)
+		}
+		switch {
+		case ttl == -2:
+			// The key expired after it was read.
+			continue
+		case ttl < 0:
+			ttl = 0
+		case ttl == 0:
+			// Less than a millisecond remains; keep the entry expiring.
+			ttl = time.Millisecond
+		}
+		if err := fn(driver.Entry[K]{Key: K(key), Value: value, TTL: ttl}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Scan enumerates the keys matching pattern with SCAN on every server of forEach, and
+// calls fn with their values and TTLs, fetched from the server in pipelines of count
+// keys. Keys that do not hold string values are skipped. Calls to fn are serialized, and
+// stop after the first error.
+func Scan[K driver.String](ctx context.Context, forEach keyspace.ForEachFunc, pattern string, count int64, fn func(driver.Entry[K]) error) error {
+	var mu sync.Mutex
+	var fnErr error
+	// emit serializes calls to fn across servers, and stops calling it after the first error.
+	emit := func(e driver.Entry[K]) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if fnErr != nil {
+			return fnErr
+		}
+		fnErr = fn(e)
+		return fnErr
+	}
+	return forEach(ctx, func(ctx context.Context, client *redis.Client) error {
+		iter := client.Scan(ctx, 0, pattern, count).Iterator()
+		keys := make([]string, 0, count)
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+			if int64(len(keys)) < count {
+				continue
+			}
+			if err := entries(ctx, client, keys, emit); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+		if err := iter.Err(); err != nil {
+			return fmt.Errorf("error scanning keys: %w", err)
+		}
+		return entries(ctx, client, keys, emit)
+	})
+}
+
+// Deleter deletes the keys matching a pattern with UNLINK, which frees their memory in
+// the background, in batches as they are scanned, so that neither the process nor the
+// server holds all of them at once. Servers without UNLINK fall back to DEL.
+type Deleter struct {
+	Count     int64 // Count is the hint to SCAN about the number of keys returned by each call.
+	BatchSize int   // BatchSize is the maximum number of keys deleted by each command.
+
+	// BySlot groups the keys of each batch by hash slot, and deletes each group with its
+	// own command in a single pipeline, since Redis Cluster rejects multi-key commands
+	// across slots.
+	BySlot bool
+
+	noUnlink atomic.Bool // noUnlink reports whether a server lacks the UNLINK command.
+}
+
+// maxKeysError returns the error for more than limit keys matching pattern.
+func maxKeysError(limit int64, pattern string) error {
+	return fmt.Errorf("more than %d keys match %s: %w", limit, pattern, cache.ErrMaxKeysExceeded)
+}
+
+// CheckMax counts the keys matching pattern on the servers of forEach, and returns an
+// error wrapping [cache.ErrMaxKeysExceeded] as soon as more than limit keys are found.
+func (d *Deleter) CheckMax(ctx context.Context, forEach keyspace.ForEachFunc, pattern string, limit int64) error {
+	var matched atomic.Int64
+	return forEach(ctx, func(ctx context.Context, client *redis.Client) error {
+		iter := client.Scan(ctx, 0, pattern, d.Count).Iterator()
+		for iter.Next(ctx) {
+			if matched.Add(1) > limit {
+				return maxKeysError(limit, pattern)
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return fmt.Errorf("error scanning keys: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteAll deletes the keys matching pattern from every server of forEach as Delete
+// does, and returns the number of keys deleted, or that would be in dry-run mode. If
+// opts.MaxKeys is set, the keys are counted with CheckMax first, and none are deleted if
+// there are too many.
+func (d *Deleter) DeleteAll(ctx context.Context, forEach keyspace.ForEachFunc, pattern string, opts driver.DelKeysOptions) (int64, error) {
+	if opts.MaxKeys > 0 {
+		if err := d.CheckMax(ctx, forEach, pattern, opts.MaxKeys); err != nil {
+			return 0, err
+		}
+	}
+	var matched, scanned, deleted atomic.Int64
+	err := forEach(ctx, func(ctx context.Context, client *redis.Client) error {
+		s, n, err := d.Delete(ctx, client, pattern, opts, &matched, nil)
+		scanned.Add(s)
+		deleted.Add(n)
+		return err
+	})
+	if opts.DryRun {
+		return scanned.Load(), err
+	}
+	return deleted.Load(), err
+}
+
+// Delete deletes the keys matching pattern from the server of client, and returns the
+// number of keys found and deleted. In dry-run mode, the keys are only counted.
+//
+// matched counts the matching keys of all servers of a single call, to enforce
+// opts.MaxKeys across servers; callers check the limit with CheckMax beforehand, so it's
+// only reached if keys are created meanwhile. progress, if not nil, is called with the
+// running totals after each batch.
+func (d *Deleter) Delete(ctx context.Context, client redis.Cmdable, pattern string, opts driver.DelKeysOptions, matched *atomic.Int64, progress func(scanned, deleted int64)) (scanned, deleted int64, err error) {
+	batch := make([]string, 0, d.BatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		n, err := d.unlink(ctx, client, batch)
+		deleted += n
+		batch = batch[:0]
+		if progress != nil {
+			progress(scanned, deleted)
+		}
+		if err != nil {
+			return fmt.Errorf("error deleting keys: %w", err)
+		}
+		return nil
+	}
+	iter := client.Scan(ctx, 0, pattern, d.Count).Iterator()
+	for iter.Next(ctx) {
+		if opts.MaxKeys > 0 && matched.Add(1) > opts.MaxKeys {
+			err := flush()
+			return scanned, deleted, errors.Join(err, maxKeysError(opts.MaxKeys, pattern))
+		}
+		scanned++
+		if opts.DryRun {
+			continue
+		}
+		batch = append(batch, iter.Val())
+		if len(batch) == cap(batch) {
+			if err := flush(); err != nil {
+				return scanned, deleted, err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		flushErr := flush()
+		return scanned, deleted, errors.Join(flushErr, fmt.Errorf("error scanning keys: %w", err))
+	}
+	return scanned, deleted, flush()
+}
+
+// unlink deletes keys with UNLINK, or with DEL if the server doesn't support it, and
+// returns the number of keys deleted.
+func (d *Deleter) unlink(ctx context.Context, client redis.Cmdable, keys []string) (int64, error) {
+	groups := [][]string{keys}
+	if d.BySlot {
+		groups = groupBySlot(keys)
+	}
+	useDel := d.noUnlink.Load()
+	cmds := make([]*redis.IntCmd, len(groups))
+	_, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, group := range groups {
+			if useDel {
+				cmds[i] = pipe.Del(ctx, group...)
+			} else {
+				cmds[i] = pipe.Unlink(ctx, group...)
+			}
+		}
+		return nil
+	})
+	if !useDel && isUnknownCommand(err) {
+		d.noUnlink.Store(true)
+		return d.unlink(ctx, client, keys)
+	}
+	var n int64
+	for _, cmd := range cmds {
+		n += cmd.Val()
+	}
+	return n, err
+}
+
+// groupBySlot groups keys by hash slot, keeping the order of the first key of each slot.
+func groupBySlot(keys []string) [][]string {
+	var groups [][]string
+	index := make(map[int]int)
+	for _, key := range keys {
+		slot := keymod.Slot(key)
+		i, ok := index[slot]
+		if !ok {
+			i = len(groups)
+			index[slot] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], key)
+	}
+	return groups
+}
@@ -0,0 +1,18 @@
+package rediskeys
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_groupBySlot(t *testing.T) {
+	keys := []string{"{user:1}:name", "{user:2}:name", "{user:1}:email", "{user:2}:email", "{user:1}:age"}
+	want := [][]string{
+		{"{user:1}:name", "{user:1}:email", "{user:1}:age"},
+		{"{user:2}:name", "{user:2}:email"},
+	}
+	if diff := cmp.Diff(want, groupBySlot(keys)); diff != "" {
+		t.Errorf("groupBySlot() mismatch (-want +got):\n%s", diff)
+	}
+}
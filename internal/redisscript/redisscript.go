@@ -0,0 +1,49 @@
+// Package redisscript runs the Lua implementation of scripts. It is shared by the Redis
+// drivers.
+package redisscript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/redis/go-redis/v9"
+)
+
+// Runner runs scripts, caching the go-redis script of each Lua source so that its SHA1
+// digest is computed once. The zero value is ready to use.
+type Runner struct {
+	scripts sync.Map // scripts holds the *redis.Script by Lua source.
+}
+
+// load returns the go-redis script for the Lua source of script.
+func (r *Runner) load(script *driver.Script) *redis.Script {
+	if s, ok := r.scripts.Load(script.Lua); ok {
+		return s.(*redis.Script)
+	}
+	s, _ := r.scripts.LoadOrStore(script.Lua, redis.NewScript(script.Lua))
+	return s.(*redis.Script)
+}
+
+// Run runs the Lua implementation of script with EVALSHA, falling back to EVAL if the
+// server doesn't have it cached yet, which also caches it. A nil reply is returned as a
+// nil result.
+//
+// It returns an error wrapping [cache.ErrNotSupported] if script has no Lua
+// implementation.
+func (r *Runner) Run(ctx context.Context, c redis.Scripter, script *driver.Script, keys []string, args []interface{}) (interface{}, error) {
+	if script.Lua == "" {
+		return nil, errors.Join(cache.ErrNotSupported, fmt.Errorf("script %s has no Lua implementation", script.Name))
+	}
+	result, err := r.load(script).Run(ctx, c, keys, args...).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error running script %s: %w", script.Name, err)
+	}
+	return result, nil
+}
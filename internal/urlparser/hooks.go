@@ -10,6 +10,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
 )
@@ -52,3 +55,53 @@ func StringToTLSConfigHookFunc() mapstructure.DecodeHookFuncType {
 		return &config, nil
 	}
 }
+
+// byteSizeUnits maps the supported byte size suffixes to their multipliers, longest first
+// so that "MiB" is matched before "B".
+var byteSizeUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30}, {"TiB", 1 << 40},
+	{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12},
+	{"B", 1},
+}
+
+// ParseByteSize parses a byte size such as "512", "64MiB" or "1GB". Binary (KiB, MiB,
+// GiB, TiB) and decimal (KB, MB, GB, TB) suffixes are supported, case-insensitively.
+// A number without a suffix is a number of bytes.
+func ParseByteSize(s string) (int64, error) {
+	num, mult := strings.TrimSpace(s), int64(1)
+	for _, u := range byteSizeUnits {
+		if len(num) > len(u.suffix) && strings.EqualFold(num[len(num)-len(u.suffix):], u.suffix) {
+			num, mult = strings.TrimSpace(num[:len(num)-len(u.suffix)]), u.size
+			break
+		}
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n < 0 || n > (1<<63-1)/mult {
+		return 0, fmt.Errorf("gocache: invalid byte size %q", s)
+	}
+	return n * mult, nil
+}
+
+// StringToByteSizeHookFunc creates a decode hook for converting a byte size string with a
+// unit suffix, such as "64MiB", into an integer. See [ParseByteSize] for the accepted
+// formats. Strings without a suffix are left to the default decoding.
+func StringToByteSizeHookFunc() mapstructure.DecodeHookFuncType {
+	return func(f, t reflect.Type, data interface{}) (interface{}, error) {
+		if f.Kind() != reflect.String || t == reflect.TypeOf(time.Duration(0)) {
+			return data, nil
+		}
+		switch t.Kind() {
+		case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		default:
+			return data, nil
+		}
+		s := data.(string)
+		if _, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64); err == nil {
+			return data, nil
+		}
+		return ParseByteSize(s)
+	}
+}
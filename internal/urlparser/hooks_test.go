@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestStringToCertificateHookFunc(t *testing.T) {
@@ -75,6 +76,67 @@ func TestStringToTLSConfigHookFunc(t *testing.T) {
 	}
 }
 
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{input: "0", want: 0},
+		{input: "512", want: 512},
+		{input: "512B", want: 512},
+		{input: "64MiB", want: 64 << 20},
+		{input: "64mib", want: 64 << 20},
+		{input: "2 KiB", want: 2 << 10},
+		{input: "1GB", want: 1e9},
+		{input: "3TiB", want: 3 << 40},
+		{input: "", wantErr: true},
+		{input: "MiB", wantErr: true},
+		{input: "-1KiB", wantErr: true},
+		{input: "1.5MiB", wantErr: true},
+		{input: "10XB", wantErr: true},
+		{input: "9999999TiB", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseByteSize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseByteSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseByteSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringToByteSizeHookFunc(t *testing.T) {
+	hook := StringToByteSizeHookFunc()
+	int64Type := reflect.TypeOf(int64(0))
+
+	got, err := hook(reflect.TypeOf(""), int64Type, "64MiB")
+	if err != nil || got != int64(64<<20) {
+		t.Errorf("StringToByteSizeHookFunc() = %v, %v, want %v", got, err, 64<<20)
+	}
+	// Plain numbers and non-integer targets are left untouched.
+	for _, tc := range []struct {
+		to   reflect.Type
+		data string
+	}{
+		{int64Type, "1024"},
+		{reflect.TypeOf(""), "64MiB"},
+		{reflect.TypeOf(time.Duration(0)), "1m"},
+	} {
+		got, err := hook(reflect.TypeOf(""), tc.to, tc.data)
+		if err != nil || got != tc.data {
+			t.Errorf("StringToByteSizeHookFunc(%v, %q) = %v, %v, want unchanged", tc.to, tc.data, got, err)
+		}
+	}
+	if _, err := hook(reflect.TypeOf(""), int64Type, "lots"); err == nil {
+		t.Error("StringToByteSizeHookFunc() expected an error for an invalid size")
+	}
+}
+
 func mustParseURL(s string) *url.URL {
 	u, err := url.Parse(s)
 	if err != nil {
@@ -0,0 +1,72 @@
+package urlparser
+
+// TLS configuration from URL query parameters.
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+)
+
+// Query parameters configuring TLS, read by [TLSConfigFromURL]. Drivers add them to their
+// blacklist so they aren't decoded into the options.
+const (
+	ParamTLSCAFile     = "tlscafile"     // ParamTLSCAFile is the path of a PEM file of trusted CA certificates.
+	ParamTLSCertFile   = "tlscertfile"   // ParamTLSCertFile is the path of a PEM client certificate.
+	ParamTLSKeyFile    = "tlskeyfile"    // ParamTLSKeyFile is the path of the PEM key of the client certificate.
+	ParamTLSServerName = "tlsservername" // ParamTLSServerName is the server name to verify.
+)
+
+// TLSConfigFromURL returns the TLS configuration for u, built on base, which may be nil.
+// TLS is enabled if enable is true, base is not nil, or any of the TLS parameters
+// is set; otherwise it returns nil.
+//
+// Without a CA file, the server certificate is verified against the system roots. The
+// client certificate and key files must be set together. The returned configuration
+// requires TLS 1.2 or later, unless base sets a minimum version.
+func TLSConfigFromURL(u *url.URL, base *tls.Config, enable bool) (*tls.Config, error) {
+	q := u.Query()
+	caFile, certFile, keyFile := q.Get(ParamTLSCAFile), q.Get(ParamTLSCertFile), q.Get(ParamTLSKeyFile)
+	serverName := q.Get(ParamTLSServerName)
+	if !enable && base == nil && caFile == "" && certFile == "" && keyFile == "" && serverName == "" {
+		return nil, nil
+	}
+
+	var config *tls.Config
+	if base != nil {
+		config = base.Clone()
+	} else {
+		config = &tls.Config{} //nolint:gosec // MinVersion is set below.
+	}
+	if config.MinVersion == 0 {
+		config.MinVersion = tls.VersionTLS12
+	}
+	if serverName != "" {
+		config.ServerName = serverName
+	}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("gocache: failed to read TLS CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("gocache: no certificates found in TLS CA file %s", caFile)
+		}
+		config.RootCAs = pool
+	}
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("gocache: tlscertfile and tlskeyfile must be set together")
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("gocache: failed to load TLS client certificate: %w", err)
+		}
+		config.Certificates = append(config.Certificates, cert)
+	}
+	return config, nil
+}
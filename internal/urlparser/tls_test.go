@@ -0,0 +1,154 @@
+package urlparser
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestKeyPair writes a self-signed certificate and its key to dir, and returns their paths.
+func writeTestKeyPair(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestTLSConfigFromURL(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestKeyPair(t, dir)
+	invalidFile := filepath.Join(dir, "invalid.pem")
+	if err := os.WriteFile(invalidFile, []byte("invalid"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	q := func(params ...string) *url.URL {
+		v := url.Values{}
+		for i := 0; i < len(params); i += 2 {
+			v.Set(params[i], params[i+1])
+		}
+		return &url.URL{Scheme: "fake", Host: "localhost:6379", RawQuery: v.Encode()}
+	}
+
+	tests := []struct {
+		name    string
+		u       *url.URL
+		base    *tls.Config
+		enable  bool
+		check   func(t *testing.T, config *tls.Config)
+		wantErr bool
+	}{
+		{
+			name: "disabled",
+			u:    q(),
+			check: func(t *testing.T, config *tls.Config) {
+				if config != nil {
+					t.Errorf("TLSConfigFromURL() = %v, want nil", config)
+				}
+			},
+		},
+		{
+			name:   "enabled with system roots",
+			u:      q(),
+			enable: true,
+			check: func(t *testing.T, config *tls.Config) {
+				if config == nil || config.RootCAs != nil || config.MinVersion != tls.VersionTLS12 {
+					t.Errorf("TLSConfigFromURL() = %v, want system roots and TLS 1.2", config)
+				}
+			},
+		},
+		{
+			name: "enabled by server name",
+			u:    q(ParamTLSServerName, "redis.internal"),
+			check: func(t *testing.T, config *tls.Config) {
+				if config == nil || config.ServerName != "redis.internal" {
+					t.Errorf("TLSConfigFromURL() = %v, want server name redis.internal", config)
+				}
+			},
+		},
+		{
+			name: "keeps base",
+			u:    q(ParamTLSServerName, "redis.internal"),
+			base: &tls.Config{MinVersion: tls.VersionTLS13, InsecureSkipVerify: true}, //nolint:gosec // Test configuration.
+			check: func(t *testing.T, config *tls.Config) {
+				if config == nil || !config.InsecureSkipVerify || config.MinVersion != tls.VersionTLS13 || config.ServerName != "redis.internal" {
+					t.Errorf("TLSConfigFromURL() = %v, want base with server name", config)
+				}
+			},
+		},
+		{
+			name: "loads files",
+			u:    q(ParamTLSCAFile, certFile, ParamTLSCertFile, certFile, ParamTLSKeyFile, keyFile),
+			check: func(t *testing.T, config *tls.Config) {
+				if config == nil || config.RootCAs == nil || len(config.Certificates) != 1 {
+					t.Errorf("TLSConfigFromURL() = %v, want CA pool and client certificate", config)
+				}
+			},
+		},
+		{
+			name:    "missing CA file",
+			u:       q(ParamTLSCAFile, filepath.Join(dir, "missing.pem")),
+			wantErr: true,
+		},
+		{
+			name:    "invalid CA file",
+			u:       q(ParamTLSCAFile, invalidFile),
+			wantErr: true,
+		},
+		{
+			name:    "certificate without key",
+			u:       q(ParamTLSCertFile, certFile),
+			wantErr: true,
+		},
+		{
+			name:    "invalid key pair",
+			u:       q(ParamTLSCertFile, certFile, ParamTLSKeyFile, invalidFile),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TLSConfigFromURL(tt.u, tt.base, tt.enable)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TLSConfigFromURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, got)
+			}
+		})
+	}
+}
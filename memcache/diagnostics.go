@@ -0,0 +1,80 @@
+package memcache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// Diagnostics implements driver.Diagnoser.
+func (m *memcacheCache[K]) Diagnostics(ctx context.Context) (*driver.Diagnostics, error) {
+	var servers []driver.ServerDiagnostics
+	err := m.servers.Each(func(addr net.Addr) error {
+		server := driver.ServerDiagnostics{Addr: addr.String()}
+		stats, err := m.stats(ctx, addr)
+		if err != nil {
+			server.Err = fmt.Errorf("error getting server stats: %w", err)
+		} else {
+			server.Info = map[string]map[string]string{"stats": stats}
+		}
+		servers = append(servers, server)
+		return nil
+	})
+	if err != nil {
+		return nil, gcerrors.NewWithScheme(Scheme, fmt.Errorf("error listing servers: %w", err))
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Addr < servers[j].Addr })
+	return &driver.Diagnostics{Servers: servers}, nil
+}
+
+// stats returns the general-purpose statistics of the server at addr. The memcache
+// client has no stats command, so it's sent on a connection of its own.
+//
+// The exchange is bounded by the client timeout. It's a socket deadline, which the
+// system clock measures, so it's derived from a context rather than an injected clock.
+func (m *memcacheCache[K]) stats(ctx context.Context, addr net.Addr) (map[string]string, error) {
+	timeout := m.client.Timeout
+	if timeout <= 0 {
+		timeout = memcache.DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, addr.Network(), addr.String())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(conn, "stats\r\n"); err != nil {
+		return nil, err
+	}
+	stats := make(map[string]string)
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSuffix(line, "\r\n")
+		if line == "END" {
+			return stats, nil
+		}
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 || fields[0] != "STAT" {
+			return nil, fmt.Errorf("unexpected response %q", line)
+		}
+		stats[fields[1]] = fields[2]
+	}
+}
@@ -10,6 +10,11 @@ The URL should have the following format:
 
 Each <host>:<port> pair corresponds to the Memcache Client node.
 
+To connect to servers listening on unix domain sockets, use the memcache+unix scheme
+with the socket paths, separated by commas:
+
+	memcache+unix:///var/run/memcached.sock
+
 # Usage
 
 	import (
@@ -54,6 +59,14 @@ You can create a Memcache cache with [New]:
 Please note that due to the limitations of the Memcache protocol, pattern matching
 operations are not supported. This includes the [cache.Cache] Count and DelKeys methods, which will return a
 [cache.ErrPatternMatchingNotSupported] error if called.
+
+Memcache has no mechanism for reporting key changes or enumerating keys, so the Watch
+and Scan methods return a [cache.ErrNotSupported] error. As a consequence, a Memcache
+cache cannot be dumped, although a dump can be restored into it.
+
+The diagnostics of a Memcache cache report the general-purpose statistics of each
+server, returned by the stats command, in the "stats" section. The connection pool of
+the client is not exposed.
 */
 package memcache
 
@@ -62,7 +75,6 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
-	"strings"
 	"sync"
 	"time"
 
@@ -73,18 +85,25 @@ import (
 	"github.com/bradfitz/gomemcache/memcache"
 )
 
-// Scheme is the cache scheme for Memcache.
-const Scheme = "memcache"
+const (
+	// Scheme is the cache scheme for Memcache.
+	Scheme = "memcache"
+	// SchemeUnix is the cache scheme for Memcache over unix domain sockets.
+	SchemeUnix = "memcache+unix"
+)
 
 func init() { //nolint:gochecknoinits // This is the entry point of the package.
 	cache.RegisterCache(Scheme, &memcacheCache[string]{})
 	cache.RegisterCache(Scheme, &memcacheCache[keymod.Key]{})
+	cache.RegisterCache(SchemeUnix, &memcacheCache[string]{})
+	cache.RegisterCache(SchemeUnix, &memcacheCache[keymod.Key]{})
 }
 
 // memcacheCache is a Memcache implementation of the cache.Cache interface.
 type memcacheCache[K driver.String] struct {
-	once   sync.Once        // once ensures that the cache is initialized only once.
-	client *memcache.Client // client is the Memcache client.
+	once    sync.Once            // once ensures that the cache is initialized only once.
+	client  *memcache.Client     // client is the Memcache client.
+	servers *memcache.ServerList // servers are the Memcache servers of the client.
 }
 
 // New returns a new Memcache cache implementation.
@@ -97,11 +116,19 @@ func New[K driver.String](ctx context.Context, opts *Options) *memcacheCache[K]
 // Ensure MemcacheCache implements the cache.Cache interface.
 var _ driver.Cache[string] = new(memcacheCache[string])
 var _ driver.Cache[keymod.Key] = new(memcacheCache[keymod.Key])
+var _ driver.Watcher[string] = new(memcacheCache[string])
+var _ driver.Watcher[keymod.Key] = new(memcacheCache[keymod.Key])
+var _ driver.Scanner[string] = new(memcacheCache[string])
+var _ driver.Scanner[keymod.Key] = new(memcacheCache[keymod.Key])
+var _ driver.Diagnoser = new(memcacheCache[string])
 
 // OpenCacheURL implements cache.URLOpener.
 func (m *memcacheCache[K]) OpenCacheURL(ctx context.Context, u *url.URL) (*cache.GenericCache[K], error) {
-	addrs := strings.Split(u.Host, ",")
-	m.init(ctx, &Options{Addrs: addrs})
+	opts, err := optionsFromURL(u)
+	if err != nil {
+		return nil, gcerrors.NewWithScheme(Scheme, fmt.Errorf("error parsing URL: %w", err))
+	}
+	m.init(ctx, &opts)
 	return cache.NewCache(m), nil
 }
 
@@ -110,7 +137,9 @@ func (m *memcacheCache[K]) init(_ context.Context, opts *Options) {
 		if opts == nil {
 			opts = &Options{}
 		}
-		m.client = memcache.New(opts.Addrs...)
+		m.servers = new(memcache.ServerList)
+		_ = m.servers.SetServers(opts.Addrs...) // Like memcache.New, invalid addresses fail on use.
+		m.client = memcache.NewFromSelector(m.servers)
 	})
 }
 
@@ -168,13 +197,35 @@ func (m *memcacheCache[K]) Get(_ context.Context, key K) ([]byte, error) {
 	return item.Value, nil
 }
 
+// valueBytes converts a value of type string or []byte to bytes.
+func valueBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type: %T", v)
+	}
+}
+
+// expirationSeconds converts a TTL to a memcache expiration in seconds, rounding up so
+// that sub-second TTLs do not become "never expires".
+func expirationSeconds(ttl time.Duration) int32 {
+	return int32((ttl + time.Second - 1) / time.Second) //nolint:gosec // TTLs are bounded by memcache
+}
+
 // Set implements cache.Cache.
 func (m *memcacheCache[K]) Set(_ context.Context, key K, value interface{}) error {
+	data, err := valueBytes(value)
+	if err != nil {
+		return gcerrors.NewWithScheme(Scheme, err)
+	}
 	item := &memcache.Item{
 		Key:   string(key),
-		Value: []byte(value.(string)),
+		Value: data,
 	}
-	err := m.client.Set(item)
+	err = m.client.Set(item)
 	if err != nil {
 		return gcerrors.NewWithScheme(Scheme, fmt.Errorf("error setting key %s: %w", key, err))
 	}
@@ -183,18 +234,32 @@ func (m *memcacheCache[K]) Set(_ context.Context, key K, value interface{}) erro
 
 // SetWithTTL implements cache.Cache.
 func (m *memcacheCache[K]) SetWithTTL(_ context.Context, key K, value interface{}, ttl time.Duration) error {
+	data, err := valueBytes(value)
+	if err != nil {
+		return gcerrors.NewWithScheme(Scheme, err)
+	}
 	item := &memcache.Item{
 		Key:        string(key),
-		Value:      []byte(value.(string)),
-		Expiration: int32(ttl.Seconds()),
+		Value:      data,
+		Expiration: expirationSeconds(ttl),
 	}
-	err := m.client.Set(item)
+	err = m.client.Set(item)
 	if err != nil {
 		return gcerrors.NewWithScheme(Scheme, fmt.Errorf("error setting key %s with expiry: %w", key, err))
 	}
 	return nil
 }
 
+// Watch implements driver.Watcher.
+func (m *memcacheCache[K]) Watch(_ context.Context, pattern K) (<-chan driver.Event[K], error) {
+	return nil, gcerrors.NewWithScheme(Scheme, errors.Join(cache.ErrNotSupported, fmt.Errorf("Watch operation not supported")))
+}
+
+// Scan implements driver.Scanner.
+func (m *memcacheCache[K]) Scan(_ context.Context, pattern K, fn func(driver.Entry[K]) error) error {
+	return gcerrors.NewWithScheme(Scheme, errors.Join(cache.ErrNotSupported, fmt.Errorf("Scan operation not supported")))
+}
+
 // Ping implements cache.Cache.
 func (m *memcacheCache[K]) Ping(_ context.Context) error {
 	return m.client.Ping()
@@ -1,9 +1,17 @@
 package memcache
 
 import (
+	"bufio"
 	"context"
+	"fmt"
+	"io"
+	"net"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -44,6 +52,136 @@ func TestMemcacheCache_New(t *testing.T) {
 	assert.NotNil(t, m.client)
 }
 
+// serveMemcache serves a minimal subset of the memcached text protocol on l: the set,
+// get, gets, version and stats commands. It stands in for a memcached server in tests.
+func serveMemcache(t *testing.T, l net.Listener) {
+	t.Helper()
+	var mu sync.Mutex
+	items := make(map[string][]byte)
+	handle := func(conn net.Conn) {
+		defer conn.Close()
+		r, w := bufio.NewReader(conn), bufio.NewWriter(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			switch fields[0] {
+			case "set":
+				var n int
+				if len(fields) < 5 {
+					return
+				}
+				fmt.Sscan(fields[4], &n)
+				data := make([]byte, n+2)
+				if _, err := io.ReadFull(r, data); err != nil {
+					return
+				}
+				mu.Lock()
+				items[fields[1]] = data[:n]
+				mu.Unlock()
+				w.WriteString("STORED\r\n")
+			case "get", "gets":
+				mu.Lock()
+				for _, key := range fields[1:] {
+					if data, ok := items[key]; ok {
+						fmt.Fprintf(w, "VALUE %s 0 %d 1\r\n%s\r\n", key, len(data), data)
+					}
+				}
+				mu.Unlock()
+				w.WriteString("END\r\n")
+			case "version":
+				w.WriteString("VERSION 1.6.0\r\n")
+			case "stats":
+				mu.Lock()
+				fmt.Fprintf(w, "STAT version 1.6.0\r\nSTAT curr_items %d\r\nEND\r\n", len(items))
+				mu.Unlock()
+			default:
+				w.WriteString("ERROR\r\n")
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go handle(conn)
+		}
+	}()
+}
+
+func TestMemcacheCache_Unix(t *testing.T) {
+	dir, err := os.MkdirTemp("", "memcache")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "memcached.sock")
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	serveMemcache(t, l)
+
+	ctx := context.Background()
+	m := &memcacheCache[string]{}
+	c, err := m.OpenCacheURL(ctx, &url.URL{Scheme: SchemeUnix, Path: path})
+	require.NoError(t, err)
+	defer c.Close()
+
+	require.NoError(t, c.Ping(ctx))
+	require.NoError(t, c.Set(ctx, "key", "value"))
+	got, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), got)
+}
+
+func TestMemcacheCache_Diagnostics(t *testing.T) {
+	dir, err := os.MkdirTemp("", "memcache")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	var paths []string
+	for _, name := range []string{"a.sock", "b.sock"} {
+		path := filepath.Join(dir, name)
+		l, err := net.Listen("unix", path)
+		require.NoError(t, err)
+		t.Cleanup(func() { l.Close() })
+		serveMemcache(t, l)
+		paths = append(paths, path)
+	}
+	// A server that is down is reported with its error.
+	down := filepath.Join(dir, "down.sock")
+
+	ctx := context.Background()
+	m := &memcacheCache[string]{}
+	c, err := m.OpenCacheURL(ctx, &url.URL{Scheme: SchemeUnix, Path: strings.Join(append(paths, down), ",")})
+	require.NoError(t, err)
+	defer c.Close()
+	require.NoError(t, c.Set(ctx, "key", "value"))
+
+	d, err := c.Diagnostics(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, d.Pool)
+	require.Len(t, d.Servers, 3)
+	var items int
+	for _, server := range d.Servers[:2] {
+		require.NoError(t, server.Err)
+		assert.Equal(t, "1.6.0", server.Info["stats"]["version"])
+		n, err := strconv.Atoi(server.Info["stats"]["curr_items"])
+		require.NoError(t, err)
+		items += n
+	}
+	assert.Equal(t, 1, items)
+	assert.Equal(t, down, d.Servers[2].Addr)
+	assert.Error(t, d.Servers[2].Err)
+}
+
 // setupCache creates a new Memcached container.
 func setupCache[K driver.String](t *testing.T) *memcacheCache[K] {
 	t.Helper()
@@ -137,6 +275,8 @@ func (h *harness[K]) Options() drivertest.Options {
 	return drivertest.Options{
 		PatternMatchingDisabled: true, // Memcached does not support pattern matching
 		CloseIsNoop:             true, // Cache can still be used after closing
+		WatchDisabled:           true, // Memcached does not support keyspace events
+		ScanDisabled:            true, // Memcached does not support enumerating keys
 	}
 }
 
@@ -0,0 +1,34 @@
+package memcache
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// optionsFromURL parses a [url.URL] into [Options].
+//
+// The URL should have one of the following formats:
+//
+//	memcache://<host1>:<port1>,<host2>:<port2>,...,<hostN>:<portN>
+//	memcache+unix://<path1>,<path2>,...,<pathN>
+//
+// With the memcache+unix scheme, each path is a unix domain socket, and must contain a
+// slash; an absolute path follows the empty host, as in
+// memcache+unix:///var/run/memcached.sock.
+func optionsFromURL(u *url.URL) (Options, error) {
+	if u.Scheme != SchemeUnix {
+		return Options{Addrs: strings.Split(u.Host, ",")}, nil
+	}
+	if u.Host+u.Path == "" {
+		return Options{}, errors.New("missing socket path")
+	}
+	paths := strings.Split(u.Host+u.Path, ",")
+	for _, path := range paths {
+		// gomemcache treats addresses containing a slash as unix socket paths.
+		if !strings.Contains(path, "/") {
+			return Options{}, errors.New("invalid socket path " + path)
+		}
+	}
+	return Options{Addrs: paths}, nil
+}
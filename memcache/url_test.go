@@ -0,0 +1,62 @@
+package memcache
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func Test_optionsFromURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		u       string
+		want    Options
+		wantErr bool
+	}{
+		{
+			name: "parses hosts",
+			u:    "memcache://localhost:11211,localhost:11212",
+			want: Options{Addrs: []string{"localhost:11211", "localhost:11212"}},
+		},
+		{
+			name: "parses unix socket",
+			u:    "memcache+unix:///var/run/memcached.sock",
+			want: Options{Addrs: []string{"/var/run/memcached.sock"}},
+		},
+		{
+			name: "parses unix sockets",
+			u:    "memcache+unix:///var/run/memcached1.sock,/var/run/memcached2.sock",
+			want: Options{Addrs: []string{"/var/run/memcached1.sock", "/var/run/memcached2.sock"}},
+		},
+		{
+			name: "parses relative unix socket",
+			u:    "memcache+unix://run/memcached.sock",
+			want: Options{Addrs: []string{"run/memcached.sock"}},
+		},
+		{
+			name:    "returns error for missing socket path",
+			u:       "memcache+unix://",
+			wantErr: true,
+		},
+		{
+			name:    "returns error for invalid socket path",
+			u:       "memcache+unix://memcached.sock",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.u)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := optionsFromURL(u)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("optionsFromURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("optionsFromURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
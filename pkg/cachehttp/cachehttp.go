@@ -0,0 +1,291 @@
+/*
+Package cachehttp exposes a [cache.Cache] over HTTP.
+
+The [Handler] can be embedded in any HTTP server, and is served on its own by the
+gocached command.
+
+# Endpoints
+
+	GET    /keys/{key}          returns the value of the key
+	HEAD   /keys/{key}          reports whether the key exists (200 or 404)
+	PUT    /keys/{key}          sets the key to the request body
+	DELETE /keys/{key}          deletes the key
+	GET    /count?pattern=P     returns the number of keys matching P as {"count":N}
+	DELETE /keys?pattern=P      deletes the keys matching P
+	GET    /healthz             pings the cache (200 or 503)
+
+Keys are taken from the rest of the path after /keys/, so they may contain slashes.
+Patterns use the syntax of the underlying cache.
+
+# TTL
+
+A PUT request may set the [TTLHeader] header to give the key a time-to-live. The value is
+either a Go duration (for example "1h30m") or a whole number of seconds. Without the
+header, the key does not expire.
+
+# Status Codes
+
+Errors are returned as plain text with one of the following status codes:
+
+	400 Bad Request            the key, pattern or TTL is missing or invalid
+	401 Unauthorized           the bearer token is missing or wrong
+	404 Not Found              the key does not exist
+	413 Content Too Large      the request body exceeds [Options.MaxBodySize]
+	501 Not Implemented        the cache does not support the operation
+	500 Internal Server Error  any other error
+
+# Authentication
+
+If [Options.Token] is set, every request except /healthz must carry the header
+"Authorization: Bearer <token>".
+*/
+package cachehttp
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	cache "github.com/bartventer/gocache"
+)
+
+// TTLHeader is the request header holding the TTL of a key set with PUT.
+const TTLHeader = "X-Cache-TTL"
+
+// DefaultMaxBodySize is the default maximum size of a request body.
+const DefaultMaxBodySize = 1 << 20 // 1MiB
+
+// Options are the options for [NewHandler].
+type Options struct {
+	// Token is the bearer token required to access the cache. If empty, requests are
+	// not authenticated.
+	Token string
+
+	// MaxBodySize is the maximum size of a request body, in bytes. Larger requests are
+	// rejected with 413 Content Too Large.
+	// Defaults to [DefaultMaxBodySize].
+	MaxBodySize int64
+}
+
+func (o *Options) revise() {
+	if o.MaxBodySize <= 0 {
+		o.MaxBodySize = DefaultMaxBodySize
+	}
+}
+
+// Handler is an [http.Handler] serving a cache.
+type Handler struct {
+	cache *cache.Cache   // cache is the cache being served.
+	opts  Options        // opts are the handler options.
+	mux   *http.ServeMux // mux routes the requests.
+}
+
+var _ http.Handler = new(Handler)
+
+// NewHandler returns a handler serving c. A nil opts is equivalent to a zero [Options].
+// The handler does not close c.
+func NewHandler(c *cache.Cache, opts *Options) *Handler {
+	h := &Handler{cache: c, mux: http.NewServeMux()}
+	if opts != nil {
+		h.opts = *opts
+	}
+	h.opts.revise()
+
+	h.mux.HandleFunc("GET /keys/{key...}", h.get)
+	h.mux.HandleFunc("HEAD /keys/{key...}", h.exists)
+	h.mux.HandleFunc("PUT /keys/{key...}", h.set)
+	h.mux.HandleFunc("DELETE /keys/{key...}", h.del)
+	h.mux.HandleFunc("GET /count", h.count)
+	h.mux.HandleFunc("DELETE /keys", h.delKeys)
+	h.mux.HandleFunc("GET /healthz", h.healthz)
+	return h
+}
+
+// ServeHTTP implements [http.Handler].
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.opts.Token != "" && r.URL.Path != "/healthz" && !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="gocache"`)
+		http.Error(w, "missing or invalid bearer token", http.StatusUnauthorized)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, h.opts.MaxBodySize)
+	h.mux.ServeHTTP(w, r)
+}
+
+// authorized reports whether the request carries the configured bearer token.
+func (h *Handler) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(h.opts.Token)) == 1
+}
+
+// keyFromPath returns the key of a /keys/{key} request, writing an error if it's empty.
+func keyFromPath(w http.ResponseWriter, r *http.Request) (string, bool) {
+	key := r.PathValue("key")
+	if key == "" {
+		http.Error(w, "missing key", http.StatusBadRequest)
+		return "", false
+	}
+	return key, true
+}
+
+// patternFromQuery returns the pattern query parameter, writing an error if it's empty.
+func patternFromQuery(w http.ResponseWriter, r *http.Request) (string, bool) {
+	pattern := r.URL.Query().Get("pattern")
+	if pattern == "" {
+		http.Error(w, "missing pattern query parameter", http.StatusBadRequest)
+		return "", false
+	}
+	return pattern, true
+}
+
+// parseTTL parses the value of the [TTLHeader] header.
+func parseTTL(s string) (time.Duration, error) {
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if secs < 0 || secs > int64(time.Duration(1<<63-1)/time.Second) {
+			return 0, fmt.Errorf("invalid TTL %q", s)
+		}
+		return time.Duration(secs) * time.Second, nil
+	}
+	ttl, err := time.ParseDuration(s)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid TTL %q", s)
+	}
+	return ttl, nil
+}
+
+// writeError writes the status code and message for err.
+func writeError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	code := http.StatusInternalServerError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		code = http.StatusRequestEntityTooLarge
+	case errors.Is(err, cache.ErrKeyNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, cache.ErrInvalidTTL):
+		code = http.StatusBadRequest
+	case errors.Is(err, cache.ErrNotSupported), errors.Is(err, cache.ErrPatternMatchingNotSupported):
+		code = http.StatusNotImplemented
+	}
+	http.Error(w, err.Error(), code)
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request) {
+	key, ok := keyFromPath(w, r)
+	if !ok {
+		return
+	}
+	value, err := h.cache.Get(r.Context(), key)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.Itoa(len(value)))
+	_, _ = w.Write(value)
+}
+
+func (h *Handler) exists(w http.ResponseWriter, r *http.Request) {
+	key, ok := keyFromPath(w, r)
+	if !ok {
+		return
+	}
+	exists, err := h.cache.Exists(r.Context(), key)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) set(w http.ResponseWriter, r *http.Request) {
+	key, ok := keyFromPath(w, r)
+	if !ok {
+		return
+	}
+	var ttl time.Duration
+	if s := r.Header.Get(TTLHeader); s != "" {
+		var err error
+		if ttl, err = parseTTL(s); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	value, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if ttl > 0 {
+		err = h.cache.SetWithTTL(r.Context(), key, value, ttl)
+	} else {
+		err = h.cache.Set(r.Context(), key, value)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) del(w http.ResponseWriter, r *http.Request) {
+	key, ok := keyFromPath(w, r)
+	if !ok {
+		return
+	}
+	if err := h.cache.Del(r.Context(), key); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// countResponse is the response body of the count endpoint.
+type countResponse struct {
+	Count int64 `json:"count"`
+}
+
+func (h *Handler) count(w http.ResponseWriter, r *http.Request) {
+	pattern, ok := patternFromQuery(w, r)
+	if !ok {
+		return
+	}
+	n, err := h.cache.Count(r.Context(), pattern)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(countResponse{Count: n})
+}
+
+func (h *Handler) delKeys(w http.ResponseWriter, r *http.Request) {
+	pattern, ok := patternFromQuery(w, r)
+	if !ok {
+		return
+	}
+	if err := h.cache.DelKeys(r.Context(), pattern); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) healthz(w http.ResponseWriter, r *http.Request) {
+	if err := h.cache.Ping(r.Context()); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = io.WriteString(w, "ok\n")
+}
@@ -0,0 +1,279 @@
+package cachehttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/internal/glob"
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memCache is a minimal [driver.Cache] backed by a map.
+type memCache struct {
+	mu      sync.Mutex
+	values  map[string][]byte
+	ttls    map[string]time.Duration
+	pingErr error
+}
+
+var _ driver.Cache[string] = new(memCache)
+
+func newMemCache() *memCache {
+	return &memCache{values: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (m *memCache) Count(_ context.Context, pattern string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int64
+	for key := range m.values {
+		if glob.Match(pattern, key) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *memCache) Exists(_ context.Context, key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.values[key]
+	return ok, nil
+}
+
+func (m *memCache) Del(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.values[key]; !ok {
+		return errors.Join(cache.ErrKeyNotFound, fmt.Errorf("key %s not found", key))
+	}
+	delete(m.values, key)
+	delete(m.ttls, key)
+	return nil
+}
+
+func (m *memCache) DelKeys(_ context.Context, pattern string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range m.values {
+		if glob.Match(pattern, key) {
+			delete(m.values, key)
+			delete(m.ttls, key)
+		}
+	}
+	return nil
+}
+
+func (m *memCache) Clear(_ context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clear(m.values)
+	clear(m.ttls)
+	return nil
+}
+
+func (m *memCache) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.values[key]
+	if !ok {
+		return nil, errors.Join(cache.ErrKeyNotFound, fmt.Errorf("key %s not found", key))
+	}
+	return value, nil
+}
+
+func (m *memCache) Set(ctx context.Context, key string, value interface{}) error {
+	return m.SetWithTTL(ctx, key, value, 0)
+}
+
+func (m *memCache) SetWithTTL(_ context.Context, key string, value interface{}, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value.([]byte)
+	m.ttls[key] = ttl
+	return nil
+}
+
+func (m *memCache) Ping(_ context.Context) error { return m.pingErr }
+
+func (m *memCache) Close() error { return nil }
+
+// do sends a request to the handler and returns the response.
+func do(t *testing.T, h http.Handler, method, target, body string, header http.Header) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for k, vs := range header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Result()
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestHandler_Keys(t *testing.T) {
+	m := newMemCache()
+	h := NewHandler(cache.NewCache[string](m), nil)
+
+	resp := do(t, h, http.MethodPut, "/keys/user/1", "alice", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, []byte("alice"), m.values["user/1"])
+	assert.Zero(t, m.ttls["user/1"])
+
+	resp = do(t, h, http.MethodGet, "/keys/user/1", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "alice", readBody(t, resp))
+
+	resp = do(t, h, http.MethodHead, "/keys/user/1", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, readBody(t, resp))
+
+	resp = do(t, h, http.MethodDelete, "/keys/user/1", "", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodDelete} {
+		resp = do(t, h, method, "/keys/user/1", "", nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, method)
+	}
+
+	resp = do(t, h, http.MethodGet, "/keys/", "", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = do(t, h, http.MethodPost, "/keys/user/1", "", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestHandler_TTL(t *testing.T) {
+	tests := []struct {
+		header   string
+		wantCode int
+		wantTTL  time.Duration
+	}{
+		{"90", http.StatusNoContent, 90 * time.Second},
+		{"1h30m", http.StatusNoContent, 90 * time.Minute},
+		{"0", http.StatusNoContent, 0},
+		{"-5", http.StatusBadRequest, 0},
+		{"-1s", http.StatusBadRequest, 0},
+		{"soon", http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			m := newMemCache()
+			h := NewHandler(cache.NewCache[string](m), nil)
+			resp := do(t, h, http.MethodPut, "/keys/k", "v", http.Header{TTLHeader: {tt.header}})
+			assert.Equal(t, tt.wantCode, resp.StatusCode)
+			assert.Equal(t, tt.wantTTL, m.ttls["k"])
+		})
+	}
+}
+
+func TestHandler_Patterns(t *testing.T) {
+	m := newMemCache()
+	h := NewHandler(cache.NewCache[string](m), nil)
+	for _, key := range []string{"user:1", "user:2", "order:1"} {
+		m.values[key] = []byte("v")
+	}
+
+	resp := do(t, h, http.MethodGet, "/count?pattern=user:*", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"count":2}`, readBody(t, resp))
+
+	resp = do(t, h, http.MethodDelete, "/keys?pattern=user:*", "", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Len(t, m.values, 1)
+
+	resp = do(t, h, http.MethodGet, "/count", "", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp = do(t, h, http.MethodDelete, "/keys", "", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Len(t, m.values, 1)
+}
+
+// unsupportedCache is a cache without pattern matching support.
+type unsupportedCache struct {
+	*memCache
+}
+
+func (unsupportedCache) Count(context.Context, string) (int64, error) {
+	return 0, cache.ErrPatternMatchingNotSupported
+}
+
+func TestHandler_NotSupported(t *testing.T) {
+	h := NewHandler(cache.NewCache[string](unsupportedCache{newMemCache()}), nil)
+	resp := do(t, h, http.MethodGet, "/count?pattern=*", "", nil)
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+}
+
+func TestHandler_Healthz(t *testing.T) {
+	m := newMemCache()
+	h := NewHandler(cache.NewCache[string](m), &Options{Token: "secret"})
+
+	resp := do(t, h, http.MethodGet, "/healthz", "", nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	m.pingErr = errors.New("connection refused")
+	resp = do(t, h, http.MethodGet, "/healthz", "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestHandler_Auth(t *testing.T) {
+	m := newMemCache()
+	m.values["k"] = []byte("v")
+	h := NewHandler(cache.NewCache[string](m), &Options{Token: "secret"})
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"missing", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer nope", http.StatusUnauthorized},
+		{"wrong scheme", "Basic secret", http.StatusUnauthorized},
+		{"valid", "Bearer secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.header != "" {
+				header.Set("Authorization", tt.header)
+			}
+			resp := do(t, h, http.MethodGet, "/keys/k", "", header)
+			assert.Equal(t, tt.want, resp.StatusCode)
+			if tt.want == http.StatusUnauthorized {
+				assert.NotEmpty(t, resp.Header.Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestHandler_MaxBodySize(t *testing.T) {
+	m := newMemCache()
+	h := NewHandler(cache.NewCache[string](m), &Options{MaxBodySize: 4})
+
+	resp := do(t, h, http.MethodPut, "/keys/k", "1234", nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp = do(t, h, http.MethodPut, "/keys/k", "12345", nil)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	assert.Equal(t, []byte("1234"), m.values["k"])
+}
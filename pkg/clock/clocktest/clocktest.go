@@ -0,0 +1,98 @@
+// Package clocktest provides a fake [clock.Clock] for tests.
+package clocktest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bartventer/gocache/pkg/clock"
+)
+
+// Fake is a [clock.Clock] whose time only moves when it's advanced. It's safe for
+// concurrent use.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+}
+
+var _ clock.Clock = new(Fake)
+
+// NewFake returns a fake clock set to now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the time of the fake clock.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// NewTicker returns a ticker that ticks as the fake clock is advanced past each
+// multiple of d. It panics if d is not greater than zero, like [time.NewTicker].
+func (f *Fake) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("clocktest: non-positive interval for NewTicker")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t := &fakeTicker{
+		clock:  f,
+		c:      make(chan time.Time, 1),
+		period: d,
+		next:   f.now.Add(d),
+	}
+	f.tickers = append(f.tickers, t)
+	return t
+}
+
+// Tickers returns the number of tickers that have not been stopped.
+func (f *Fake) Tickers() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.tickers)
+}
+
+// Advance moves the fake clock forward by d, delivering the ticks that are due. Like
+// with [time.Ticker], a ticker delivers at most one pending tick, so ticks are dropped
+// if the previous one was not received yet.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	for _, t := range f.tickers {
+		if t.next.After(f.now) {
+			continue
+		}
+		select {
+		case t.c <- t.next:
+		default:
+		}
+		// Skip the ticks that were missed, which would have been dropped.
+		missed := f.now.Sub(t.next) / t.period
+		t.next = t.next.Add((missed + 1) * t.period)
+	}
+}
+
+// fakeTicker is a ticker of a fake clock.
+type fakeTicker struct {
+	clock  *Fake
+	c      chan time.Time
+	period time.Duration
+	next   time.Time // next is the time of the next tick.
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.c }
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, other := range t.clock.tickers {
+		if other == t {
+			t.clock.tickers = append(t.clock.tickers[:i], t.clock.tickers[i+1:]...)
+			return
+		}
+	}
+}
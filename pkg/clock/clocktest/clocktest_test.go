@@ -0,0 +1,40 @@
+package clocktest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := NewFake(start)
+	assert.Equal(t, start, f.Now())
+
+	ticker := f.NewTicker(time.Minute)
+	assert.Equal(t, 1, f.Tickers())
+
+	f.Advance(30 * time.Second)
+	assert.Equal(t, start.Add(30*time.Second), f.Now())
+	assert.Empty(t, ticker.C(), "expected no tick before the interval elapsed")
+
+	f.Advance(30 * time.Second)
+	assert.Equal(t, start.Add(time.Minute), <-ticker.C())
+
+	// Ticks that are not received are dropped.
+	f.Advance(10 * time.Minute)
+	assert.Equal(t, start.Add(2*time.Minute), <-ticker.C())
+	assert.Empty(t, ticker.C())
+	f.Advance(time.Minute)
+	assert.Equal(t, start.Add(12*time.Minute), <-ticker.C())
+
+	ticker.Stop()
+	assert.Equal(t, 0, f.Tickers())
+	f.Advance(time.Hour)
+	assert.Empty(t, ticker.C(), "expected no tick after Stop")
+}
+
+func TestFake_NewTickerPanics(t *testing.T) {
+	assert.Panics(t, func() { NewFake(time.Now()).NewTicker(0) })
+}
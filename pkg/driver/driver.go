@@ -50,3 +50,203 @@ type Cache[K String] interface {
 	// Close terminates the connection to the cache, releasing any allocated resources.
 	Close() error
 }
+
+// EventType is the type of a key event reported by a [Watcher].
+type EventType int
+
+const (
+	// EventSet is reported when a key is set or overwritten.
+	EventSet EventType = iota + 1
+	// EventDelete is reported when a key is explicitly deleted.
+	EventDelete
+	// EventExpire is reported when a key is removed because its TTL elapsed.
+	EventExpire
+)
+
+// String returns the name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case EventSet:
+		return "set"
+	case EventDelete:
+		return "delete"
+	case EventExpire:
+		return "expire"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes a change to a key in the cache.
+type Event[K String] struct {
+	Type EventType // Type is the type of the event.
+	Key  K         // Key is the key affected by the event.
+}
+
+// Watcher is an optional interface implemented by caches that can report key events.
+type Watcher[K String] interface {
+	// Watch returns a channel that receives events for keys matching the given glob-style
+	// pattern. The channel is closed when the context is canceled or the cache is closed.
+	// Events are delivered on a best-effort basis; slow consumers may miss events.
+	Watch(ctx context.Context, pattern K) (<-chan Event[K], error)
+}
+
+// WatchBufferSize is the number of events buffered for each watcher by the drivers of this
+// module. Events are dropped, rather than blocking the cache, once a watcher's buffer is full.
+const WatchBufferSize = 128
+
+// Entry is a key-value pair stored in the cache, along with its remaining time-to-live.
+type Entry[K String] struct {
+	Key   K             // Key is the entry key.
+	Value []byte        // Value is the entry value.
+	TTL   time.Duration // TTL is the remaining time-to-live. Zero means no expiry.
+}
+
+// Scanner is an optional interface implemented by caches that can enumerate their entries.
+type Scanner[K String] interface {
+	// Scan calls fn for each entry whose key matches the given glob-style pattern.
+	// Keys that are deleted or expire while scanning may be skipped. Scanning stops
+	// at the first error returned by fn, and that error is returned.
+	Scan(ctx context.Context, pattern K, fn func(Entry[K]) error) error
+}
+
+// DelKeysOptions are the options for [KeysDeleter.DelKeysWithOptions].
+type DelKeysOptions struct {
+	// DryRun counts the keys that would be deleted without deleting them.
+	DryRun bool
+
+	// MaxKeys is the maximum number of keys to delete. If more keys match the pattern,
+	// an error is returned and no keys are deleted: the matching keys are counted before
+	// any is deleted. Keys created between counting and deleting can still exceed the
+	// limit, in which case deletion stops after MaxKeys keys. Zero means no limit.
+	MaxKeys int64
+}
+
+// KeysDeleter is an optional interface implemented by caches that can delete keys by
+// pattern in bounded batches, and report how many keys were deleted.
+type KeysDeleter[K String] interface {
+	// DelKeysWithOptions removes all keys that match the given glob-style pattern, and
+	// returns the number of keys deleted, or that would be deleted in dry-run mode. On
+	// error, the keys already deleted are counted.
+	DelKeysWithOptions(ctx context.Context, pattern K, opts DelKeysOptions) (int64, error)
+}
+
+// Batcher is an optional interface implemented by caches that can operate on several keys
+// in fewer round trips than one per key.
+type Batcher[K String] interface {
+	// GetMulti returns the values of keys, in the order of keys. The value of a key that
+	// does not exist is nil.
+	GetMulti(ctx context.Context, keys []K) ([][]byte, error)
+
+	// SetMulti stores the entries. Entries with a zero TTL do not expire.
+	SetMulti(ctx context.Context, entries []Entry[K]) error
+
+	// DelMulti removes keys and returns the number of keys that existed.
+	DelMulti(ctx context.Context, keys []K) (int64, error)
+}
+
+// PoolStats are the statistics of a client connection pool.
+type PoolStats struct {
+	Hits       uint32 // Hits is the number of times a free connection was found in the pool.
+	Misses     uint32 // Misses is the number of times a free connection was not found in the pool.
+	Timeouts   uint32 // Timeouts is the number of times waiting for a connection timed out.
+	TotalConns uint32 // TotalConns is the number of connections in the pool.
+	IdleConns  uint32 // IdleConns is the number of idle connections in the pool.
+	StaleConns uint32 // StaleConns is the number of stale connections removed from the pool.
+}
+
+// ServerDiagnostics is the state of a cache server, as reported by [Diagnoser].
+type ServerDiagnostics struct {
+	// Addr is the server address.
+	Addr string
+
+	// Pool is the connection pool of the server, or nil if the driver doesn't expose it.
+	Pool *PoolStats
+
+	// Info maps section names to the fields reported by the server in that section, like
+	// the sections of the Redis INFO command. Values are reported verbatim.
+	Info map[string]map[string]string
+
+	// Err is the error encountered querying the server, if any.
+	Err error
+}
+
+// Diagnostics is a report of the state of the client and servers of a cache.
+type Diagnostics struct {
+	// Pool is the connection pool of the client, aggregated over all servers, or nil if
+	// the driver doesn't expose it.
+	Pool *PoolStats
+
+	// Servers are the reports of the individual servers, sorted by address.
+	Servers []ServerDiagnostics
+}
+
+// Diagnoser is an optional interface implemented by caches that can report client and
+// server statistics, for debugging and monitoring.
+type Diagnoser interface {
+	// Diagnostics queries the servers and returns a report. A server that cannot be
+	// queried is reported with its error, rather than failing the whole report.
+	Diagnostics(ctx context.Context) (*Diagnostics, error)
+}
+
+// ScriptStore is the view of a cache given to the Go implementation of a [Script]. It
+// only gives access to the keys the script is run with: its methods return an error for
+// any other key. The list methods return an error if the value of key is not a list;
+// how a list is stored is up to the cache.
+type ScriptStore interface {
+	// Get returns the value of key, reporting whether it exists.
+	Get(key string) ([]byte, bool, error)
+
+	// Set stores the value of key. A zero TTL means no expiry.
+	Set(key string, value []byte, ttl time.Duration) error
+
+	// Del removes key, reporting whether it existed.
+	Del(key string) (bool, error)
+
+	// TTL returns the remaining time-to-live of key, reporting whether it exists. Zero
+	// means no expiry.
+	TTL(key string) (time.Duration, bool, error)
+
+	// Expire sets the time-to-live of key, reporting whether it exists. Like the Redis
+	// PEXPIRE command, a TTL that is not positive removes the key.
+	Expire(key string, ttl time.Duration) (bool, error)
+
+	// LPush inserts the values at the head of the list of key, one after the other, and
+	// returns the length of the list. Like the Redis LPUSH command, it creates the list
+	// if key does not exist, so the last value ends up first.
+	LPush(key string, values ...[]byte) (int64, error)
+
+	// LRange returns the elements of the list of key from start to stop, inclusive. As
+	// with the Redis LRANGE command, negative indexes count from the end of the list, and
+	// a key that does not exist is an empty list.
+	LRange(key string, start, stop int64) ([][]byte, error)
+
+	// LTrim keeps the elements of the list of key from start to stop, inclusive, with
+	// indexes as in LRange, and removes key if none is left.
+	LTrim(key string, start, stop int64) error
+}
+
+// Script is a named operation run atomically by the cache, with a Lua implementation for
+// Redis and an equivalent Go implementation for in-memory caches.
+//
+// Like Redis does for Lua scripts, the result of either implementation is nil, an int64,
+// a string, or a []interface{} of these. Keys are passed as KEYS to the Lua script, and
+// the arguments, converted to strings, as ARGV.
+type Script struct {
+	// Name is the name the script is registered under.
+	Name string
+
+	// Lua is the source of the Lua implementation.
+	Lua string
+
+	// Func is the Go implementation. It must only access keys through s.
+	Func func(s ScriptStore, keys []string, args []string) (interface{}, error)
+}
+
+// ScriptRunner is an optional interface implemented by caches that can run scripts
+// atomically.
+type ScriptRunner[K String] interface {
+	// RunScript runs the script with the given keys and arguments, and returns its
+	// result. No other operation on the cache interleaves with the script.
+	RunScript(ctx context.Context, script *Script, keys []K, args ...interface{}) (interface{}, error)
+}
@@ -25,6 +25,30 @@ type Options struct {
 	// CloseIsNoop is true if the Close method is a no-op for the cache.
 	// If true, the cache should still be usable after Close is called.
 	CloseIsNoop bool
+
+	// WatchDisabled is true if the cache does not support watching keys.
+	// If true, the Watch method should return [cache.ErrNotSupported].
+	WatchDisabled bool
+
+	// ScanDisabled is true if the cache does not support enumerating entries.
+	// If true, the Scan method should return [cache.ErrNotSupported].
+	ScanDisabled bool
+
+	// Advance, if set, advances the clock the cache measures TTLs with by d, for caches
+	// created with a fake clock such as [github.com/bartventer/gocache/pkg/clock/clocktest.Fake].
+	// The tests of expiry call it instead of sleeping for the TTL. Caches whose server
+	// tells the time, such as Redis, leave it nil.
+	Advance func(d time.Duration)
+}
+
+// wait lets d elapse on the clock of the cache: it advances the clock with opts.Advance
+// if set, and sleeps otherwise.
+func wait(opts Options, d time.Duration) {
+	if opts.Advance != nil {
+		opts.Advance(d)
+		return
+	}
+	time.Sleep(d)
 }
 
 // Harness descibes the functionality test harnesses must provide to run
@@ -56,6 +80,8 @@ func RunConformanceTests[K driver.String](t *testing.T, newHarness HarnessMaker[
 	t.Run("Del", func(t *testing.T) { withCache(t, newHarness, testDel) })
 	t.Run("DelKeys", func(t *testing.T) { withCache(t, newHarness, testDelKeys) })
 	t.Run("Clear", func(t *testing.T) { withCache(t, newHarness, testClear) })
+	t.Run("Watch", func(t *testing.T) { withCache(t, newHarness, testWatch) })
+	t.Run("Scan", func(t *testing.T) { withCache(t, newHarness, testScan) })
 	t.Run("Ping", func(t *testing.T) { withCache(t, newHarness, testPing) })
 	t.Run("Close", func(t *testing.T) { withCache(t, newHarness, testClose) })
 }
@@ -117,8 +143,8 @@ func testSetWithTTL[K driver.String](t *testing.T, c *cache.GenericCache[K], opt
 	require.NoError(t, err)
 	assert.Equal(t, value, string(got))
 
-	// Wait for the key to expire
-	time.Sleep(ttl)
+	// Wait for the key to expire; it's still live at exactly its TTL.
+	wait(opts, ttl+time.Millisecond)
 
 	_, err = c.Get(context.Background(), key)
 	require.Error(t, err)
@@ -253,6 +279,98 @@ func testClear[K driver.String](t *testing.T, c *cache.GenericCache[K], opts Opt
 	assert.False(t, exists)
 }
 
+// testWatch tests the Watch method of the cache.
+func testWatch[K driver.String](t *testing.T, c *cache.GenericCache[K], opts Options) {
+	key := makeKey[K](t)
+	value := "testValue"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if opts.WatchDisabled {
+		_, err := c.Watch(ctx, key+"*")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), cache.ErrNotSupported.Error())
+		return
+	}
+
+	events, err := c.Watch(ctx, key+"*")
+	require.NoError(t, err)
+
+	receive := func() driver.Event[K] {
+		t.Helper()
+		select {
+		case ev, ok := <-events:
+			require.True(t, ok, "events channel closed unexpectedly")
+			return ev
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for event")
+		}
+		return driver.Event[K]{}
+	}
+
+	err = c.Set(context.Background(), key, value)
+	require.NoError(t, err)
+	assert.Equal(t, driver.Event[K]{Type: driver.EventSet, Key: key}, receive())
+
+	err = c.Del(context.Background(), key)
+	require.NoError(t, err)
+	assert.Equal(t, driver.Event[K]{Type: driver.EventDelete, Key: key}, receive())
+
+	// Keys not matching the pattern are not reported
+	other := K("other-" + string(key))
+	err = c.Set(context.Background(), other, value)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		c.Del(context.Background(), other)
+	})
+
+	cancel()
+	for ev := range events {
+		assert.NotEqual(t, other, ev.Key)
+	}
+}
+
+// testScan tests the Scan method of the cache.
+func testScan[K driver.String](t *testing.T, c *cache.GenericCache[K], opts Options) {
+	prefix := makeKey[K](t)
+
+	if opts.ScanDisabled {
+		err := c.Scan(context.Background(), prefix+"*", func(driver.Entry[K]) error { return nil })
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), cache.ErrNotSupported.Error())
+		return
+	}
+
+	persistent, expiring := prefix+":persistent", prefix+":expiring"
+	require.NoError(t, c.Set(context.Background(), persistent, "value1"))
+	require.NoError(t, c.SetWithTTL(context.Background(), expiring, "value2", time.Hour))
+	t.Cleanup(func() {
+		c.Del(context.Background(), persistent)
+		c.Del(context.Background(), expiring)
+	})
+
+	got := make(map[K]driver.Entry[K])
+	err := c.Scan(context.Background(), prefix+":*", func(e driver.Entry[K]) error {
+		got[e.Key] = e
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "value1", string(got[persistent].Value))
+	assert.Equal(t, time.Duration(0), got[persistent].TTL)
+	assert.Equal(t, "value2", string(got[expiring].Value))
+	assert.Greater(t, got[expiring].TTL, time.Duration(0))
+	assert.LessOrEqual(t, got[expiring].TTL, time.Hour)
+
+	// Errors returned by fn stop the scan
+	err = c.Scan(context.Background(), prefix+":*", func(e driver.Entry[K]) error {
+		return assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+}
+
 // testPing tests the Ping method of the cache.
 func testPing[K driver.String](t *testing.T, c *cache.GenericCache[K], opts Options) {
 	err := c.Ping(context.Background())
@@ -147,6 +147,8 @@ func (h *MockHarness[K]) Options() Options {
 	return Options{
 		PatternMatchingDisabled: true,
 		CloseIsNoop:             true,
+		WatchDisabled:           true,
+		ScanDisabled:            true,
 	}
 }
 
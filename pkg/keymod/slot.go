@@ -0,0 +1,59 @@
+package keymod
+
+// Hash slots of Redis Cluster.
+
+import "strings"
+
+// SlotCount is the number of hash slots keys are distributed over in Redis Cluster.
+const SlotCount = 16384
+
+// crc16Table is the lookup table of the CRC-16/XMODEM checksum used by Redis Cluster.
+var crc16Table = func() (table [256]uint16) {
+	for i := range table {
+		crc := uint16(i) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+// crc16 returns the CRC-16/XMODEM checksum of s.
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc = crc<<8 ^ crc16Table[byte(crc>>8)^s[i]]
+	}
+	return crc
+}
+
+// HashTag returns the part of key hashed to compute its slot: the text between the first
+// "{" and the next "}", as added by [Key.TagPrefix] and [Key.TagSuffix], if it's not
+// empty, and the whole key otherwise.
+func HashTag(key string) string {
+	start := strings.IndexByte(key, '{')
+	if start < 0 {
+		return key
+	}
+	end := strings.IndexByte(key[start+1:], '}')
+	if end <= 0 {
+		return key
+	}
+	return key[start+1 : start+1+end]
+}
+
+// Slot returns the Redis Cluster hash slot of key, from 0 to [SlotCount]-1. Keys with
+// the same hash tag share a slot; see [HashTag].
+func Slot(key string) int {
+	return int(crc16(HashTag(key)) % SlotCount)
+}
+
+// Slot returns the Redis Cluster hash slot of the key; see [Slot].
+func (k Key) Slot() int {
+	return Slot(string(k))
+}
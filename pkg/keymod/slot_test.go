@@ -0,0 +1,54 @@
+package keymod
+
+import (
+	"testing"
+)
+
+func TestHashTag(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"mykey", "mykey"},
+		{"{user1000}.following", "user1000"},
+		{"foo{}{bar}", "foo{}{bar}"},
+		{"foo{{bar}}zap", "{bar"},
+		{"foo{bar}{zap}", "bar"},
+		{"foo{bar", "foo{bar"},
+		{string(Key("mykey").TagPrefix("tag")), "tag"},
+		{string(Key("mykey").TagSuffix("tag")), "tag"},
+	}
+	for _, tt := range tests {
+		if got := HashTag(tt.key); got != tt.want {
+			t.Errorf("HashTag(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestSlot(t *testing.T) {
+	if got := crc16("123456789"); got != 0x31C3 {
+		t.Errorf("crc16(123456789) = %#x, want 0x31c3", got)
+	}
+	// Slots reported by CLUSTER KEYSLOT.
+	tests := []struct {
+		key  string
+		want int
+	}{
+		{"", 0},
+		{"foo", 12182},
+		{"bar", 5061},
+		{"{user1000}.following", 3443},
+		{"{user1000}.followers", 3443},
+		{"user1000", 3443},
+		{"foo{{bar}}zap", 4015},
+	}
+	for _, tt := range tests {
+		if got := Slot(tt.key); got != tt.want {
+			t.Errorf("Slot(%q) = %d, want %d", tt.key, got, tt.want)
+		}
+	}
+	k := Key("profile").TagPrefix("user1000")
+	if got := k.Slot(); got != 3443 {
+		t.Errorf("Key(%q).Slot() = %d, want 3443", k, got)
+	}
+}
@@ -0,0 +1,505 @@
+package ramcache
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"hash/maphash"
+	"sync"
+	"time"
+
+	"github.com/bartventer/gocache/internal/glob"
+)
+
+// arenaHeaderSize is the size of the header of an arena entry: the expiry time in Unix
+// nanoseconds (zero for none), the key length and the value length.
+const arenaHeaderSize = 16
+
+// arenaDeleted is the bit of the key length header field that marks a deleted entry.
+const arenaDeleted = 1 << 31
+
+// defaultArenaBytes is the size of the arena when [Options.MaxBytes] is not set.
+const defaultArenaBytes = 64 << 20
+
+// maxArenaShardBytes is the maximum size of the arena of a shard, as offsets are 32 bits.
+const maxArenaShardBytes = 1<<32 - 1
+
+// arenaExpiry is an entry of the expiry heap of an arena shard. It may be stale, if the
+// entry at off was since deleted or overwritten.
+type arenaExpiry struct {
+	expiry int64  // expiry is the expiry time in Unix nanoseconds.
+	hash   uint64 // hash is the key hash.
+	off    uint32 // off is the offset of the entry in the arena.
+}
+
+// arenaExpiryHeap is a min-heap of expiries. It implements [heap.Interface].
+type arenaExpiryHeap []arenaExpiry
+
+func (h arenaExpiryHeap) Len() int           { return len(h) }
+func (h arenaExpiryHeap) Less(i, j int) bool { return h[i].expiry < h[j].expiry }
+func (h arenaExpiryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *arenaExpiryHeap) Push(x any) {
+	*h = append(*h, x.(arenaExpiry)) //nolint:errcheck // only arenaExpiry values are pushed
+}
+
+func (h *arenaExpiryHeap) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
+
+// arenaShard is a segment of an arenaStore. Entries are appended to a fixed size ring
+// buffer; when it's full, the oldest entries are overwritten. The index maps key hashes
+// to entry offsets, and like the expiry heap holds no pointers, so the garbage collector
+// does not scan the entries.
+//
+// The ring holds the entries in [head, tail) if it's not wrapped, and in [head, end)
+// followed by [0, tail) if it is. Deleted and overwritten entries stay in the ring,
+// marked as deleted, until the ring wraps around them.
+type arenaShard struct {
+	mu         sync.RWMutex
+	buf        []byte            // buf is the ring buffer.
+	index      map[uint64]uint32 // index maps key hashes to the offsets of live entries.
+	expiries   arenaExpiryHeap   // expiries orders the entries that have an expiry time.
+	head, tail int               // head is the offset of the oldest entry; tail of the free space.
+	end        int               // end is the end of the entries before the ring wrapped.
+	wrapped    bool              // wrapped reports whether the ring wrapped.
+	entries    int               // entries is the number of entries in the ring, including deleted ones.
+	bytes      int64             // bytes is the accounted size of the live entries.
+	maxEntries int               // maxEntries is the maximum number of live entries; zero means no limit.
+}
+
+func newArenaShard(size, maxEntries int) *arenaShard {
+	return &arenaShard{
+		buf:        make([]byte, size),
+		index:      make(map[uint64]uint32),
+		maxEntries: maxEntries,
+	}
+}
+
+// header returns the fields of the header of the entry at off.
+func (s *arenaShard) header(off int) (expiry int64, keyLen, valueLen int, deleted bool) {
+	h := s.buf[off : off+arenaHeaderSize]
+	expiry = int64(binary.LittleEndian.Uint64(h)) //nolint:gosec // round-trips an int64
+	k := binary.LittleEndian.Uint32(h[8:])
+	return expiry, int(k &^ arenaDeleted), int(binary.LittleEndian.Uint32(h[12:])), k&arenaDeleted != 0
+}
+
+// size returns the size of the entry at off.
+func (s *arenaShard) size(off int) int {
+	_, keyLen, valueLen, _ := s.header(off)
+	return arenaHeaderSize + keyLen + valueLen
+}
+
+// key returns the key of the entry at off. The slice aliases the arena.
+func (s *arenaShard) key(off int) []byte {
+	_, keyLen, _, _ := s.header(off)
+	start := off + arenaHeaderSize
+	return s.buf[start : start+keyLen]
+}
+
+// item returns a copy of the item at off.
+func (s *arenaShard) item(off int) item {
+	expiry, keyLen, valueLen, _ := s.header(off)
+	start := off + arenaHeaderSize + keyLen
+	it := item{Value: make([]byte, valueLen)}
+	copy(it.Value, s.buf[start:start+valueLen])
+	if expiry != 0 {
+		it.Expiry = time.Unix(0, expiry)
+	}
+	return it
+}
+
+// lookup returns the offset of the live entry for key.
+func (s *arenaShard) lookup(h uint64, key string) (int, bool) {
+	off, ok := s.index[h]
+	if !ok || string(s.key(int(off))) != key {
+		return 0, false
+	}
+	return int(off), true
+}
+
+// remove marks the live entry at off, whose key hashes to h, as deleted. s.mu must be held.
+func (s *arenaShard) remove(h uint64, off int) {
+	_, keyLen, valueLen, _ := s.header(off)
+	binary.LittleEndian.PutUint32(s.buf[off+8:], uint32(keyLen)|arenaDeleted) //nolint:gosec // keyLen < 1<<31
+	delete(s.index, h)
+	s.bytes -= int64(keyLen + valueLen)
+}
+
+// evictHead removes the oldest entry from the ring, appending it to evicted if it's live.
+// s.mu must be held.
+func (s *arenaShard) evictHead(seed maphash.Seed, evicted []keyItem) []keyItem {
+	off := s.head
+	if _, _, _, deleted := s.header(off); !deleted {
+		key := string(s.key(off))
+		evicted = append(evicted, keyItem{Key: key, Item: s.item(off)})
+		s.remove(maphash.String(seed, key), off)
+	}
+	s.head += s.size(off)
+	s.entries--
+	switch {
+	case s.entries == 0:
+		s.head, s.tail, s.end, s.wrapped = 0, 0, 0, false
+	case s.wrapped && s.head == s.end:
+		s.head, s.end, s.wrapped = 0, 0, false
+	}
+	return evicted
+}
+
+// alloc returns the offset of n free bytes, evicting the oldest entries to make room and
+// appending the live ones to evicted. n must not exceed the size of the arena. s.mu must
+// be held.
+func (s *arenaShard) alloc(n int, seed maphash.Seed, evicted []keyItem) (int, []keyItem) {
+	for {
+		switch {
+		case !s.wrapped && len(s.buf)-s.tail >= n:
+		case !s.wrapped && s.entries == 0:
+			s.head, s.tail = 0, 0
+			continue
+		case !s.wrapped:
+			// There's no room at the end of the arena: wrap around to its start.
+			s.end, s.tail, s.wrapped = s.tail, 0, true
+			continue
+		case s.head-s.tail < n:
+			evicted = s.evictHead(seed, evicted)
+			continue
+		}
+		off := s.tail
+		s.tail += n
+		s.entries++
+		return off, evicted
+	}
+}
+
+// Get returns a copy of the item for key.
+func (s *arenaShard) Get(h uint64, key string) (item, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.get(h, key)
+}
+
+// get returns a copy of the item for key, like Get. s.mu must be held.
+func (s *arenaShard) get(h uint64, key string) (item, bool) {
+	off, ok := s.lookup(h, key)
+	if !ok {
+		return item{}, false
+	}
+	return s.item(off), true
+}
+
+// Set stores the item and returns the items evicted to make room for it, including one
+// whose key has the same hash.
+func (s *arenaShard) Set(h uint64, seed maphash.Seed, key string, it item) []keyItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set(h, seed, key, it)
+}
+
+// set stores the item and returns the evicted items, like Set. s.mu must be held.
+func (s *arenaShard) set(h uint64, seed maphash.Seed, key string, it item) []keyItem {
+	var evicted []keyItem
+	if off, ok := s.index[h]; ok {
+		if other := string(s.key(int(off))); other != key {
+			evicted = append(evicted, keyItem{Key: other, Item: s.item(int(off))})
+		}
+		s.remove(h, int(off))
+	}
+	off, evicted := s.alloc(arenaHeaderSize+len(key)+len(it.Value), seed, evicted)
+	var expiry int64
+	if !it.Expiry.IsZero() {
+		expiry = it.Expiry.UnixNano()
+	}
+	binary.LittleEndian.PutUint64(s.buf[off:], uint64(expiry))           //nolint:gosec // round-trips an int64
+	binary.LittleEndian.PutUint32(s.buf[off+8:], uint32(len(key)))       //nolint:gosec // bounded by the arena size
+	binary.LittleEndian.PutUint32(s.buf[off+12:], uint32(len(it.Value))) //nolint:gosec // bounded by the arena size
+	copy(s.buf[off+arenaHeaderSize:], key)
+	copy(s.buf[off+arenaHeaderSize+len(key):], it.Value)
+	s.index[h] = uint32(off) //nolint:gosec // offsets are bounded by maxArenaShardBytes
+	s.bytes += itemSize(key, it)
+	if expiry != 0 {
+		s.pushExpiry(arenaExpiry{expiry: expiry, hash: h, off: uint32(off)}) //nolint:gosec // as above
+	}
+	for s.maxEntries > 0 && len(s.index) > s.maxEntries {
+		evicted = s.evictHead(seed, evicted)
+	}
+	return evicted
+}
+
+// pushExpiry adds e to the expiry heap. Stale entries are dropped when the heap grows to
+// more than twice the number of live entries. s.mu must be held.
+func (s *arenaShard) pushExpiry(e arenaExpiry) {
+	heap.Push(&s.expiries, e)
+	if len(s.expiries) <= 2*len(s.index)+1024 {
+		return
+	}
+	live := s.expiries[:0]
+	for _, e := range s.expiries {
+		if s.isLive(e) {
+			live = append(live, e)
+		}
+	}
+	s.expiries = live
+	heap.Init(&s.expiries)
+}
+
+// isLive reports whether the expiry heap entry e refers to a live entry.
+func (s *arenaShard) isLive(e arenaExpiry) bool {
+	off, ok := s.index[e.hash]
+	if !ok || off != e.off {
+		return false
+	}
+	expiry, _, _, _ := s.header(int(off))
+	return expiry == e.expiry
+}
+
+// Delete deletes the item for key and returns it, reporting whether it existed.
+func (s *arenaShard) Delete(h uint64, key string) (item, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.delete(h, key)
+}
+
+// delete deletes the item for key and returns it, like Delete. s.mu must be held.
+func (s *arenaShard) delete(h uint64, key string) (item, bool) {
+	off, ok := s.lookup(h, key)
+	if !ok {
+		return item{}, false
+	}
+	it := s.item(off)
+	s.remove(h, off)
+	return it, true
+}
+
+// DeleteExpired deletes the item for key if it's expired at time now and returns it,
+// reporting whether it did.
+func (s *arenaShard) DeleteExpired(h uint64, key string, now time.Time) (item, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	off, ok := s.lookup(h, key)
+	if !ok {
+		return item{}, false
+	}
+	it := s.item(off)
+	if !it.IsExpired(now) {
+		return item{}, false
+	}
+	s.remove(h, off)
+	return it, true
+}
+
+// DeleteExpiredItems deletes the items that expired before now and returns them,
+// looking at no more than limit entries of the expiry heap. It reports whether it
+// stopped because of the limit.
+func (s *arenaShard) DeleteExpiredItems(now time.Time, limit int) ([]keyItem, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	nowNano := now.UnixNano()
+	var deleted []keyItem
+	for range limit {
+		if len(s.expiries) == 0 || s.expiries[0].expiry >= nowNano {
+			return deleted, false
+		}
+		e := s.expiries[0]
+		heap.Pop(&s.expiries)
+		if s.isLive(e) {
+			off := int(e.off)
+			deleted = append(deleted, keyItem{Key: string(s.key(off)), Item: s.item(off)})
+			s.remove(e.hash, off)
+		}
+	}
+	return deleted, true
+}
+
+// clear removes all items, and returns them if collect is true. s.mu must be held.
+func (s *arenaShard) clear(collect bool) []keyItem {
+	var cleared []keyItem
+	if collect {
+		cleared = s.appendLive(nil, "*")
+	}
+	s.index = make(map[uint64]uint32)
+	s.expiries = nil
+	s.head, s.tail, s.end, s.wrapped = 0, 0, 0, false
+	s.entries = 0
+	s.bytes = 0
+	return cleared
+}
+
+// appendLive appends the live items whose key matches the glob-style pattern to items.
+// s.mu must be held.
+func (s *arenaShard) appendLive(items []keyItem, pattern string) []keyItem {
+	for _, off := range s.index {
+		if key := string(s.key(int(off))); glob.Match(pattern, key) {
+			items = append(items, keyItem{Key: key, Item: s.item(int(off))})
+		}
+	}
+	return items
+}
+
+// arenaStore is a storage that keeps items in fixed size arenas, one per shard, to keep
+// the garbage collector from scanning them. It's selected with [StorageArena].
+//
+// When an arena is full, its oldest entries are evicted first, regardless of use.
+// Deleted and overwritten entries take up space until they are evicted.
+type arenaStore struct {
+	shards []*arenaShard // shards are the segments of the store.
+	mask   uint64        // mask is len(shards)-1.
+	seed   maphash.Seed  // seed is the key hash seed.
+}
+
+var _ storage[[]byte] = new(arenaStore)
+
+// newArenaStore creates a new arena store with n shards, rounded up to a power of two,
+// whose arenas together take up size bytes and hold at most maxEntries items. A zero
+// size selects defaultArenaBytes; a zero maxEntries means no limit on the number of
+// items.
+func newArenaStore(n, maxEntries int, size int64) *arenaStore {
+	n = shardCount(n, maxEntries)
+	if size <= 0 {
+		size = defaultArenaBytes
+	}
+	shardSize := int(min((size+int64(n)-1)/int64(n), maxArenaShardBytes))
+	shardEntries := (maxEntries + n - 1) / n
+	s := &arenaStore{
+		shards: make([]*arenaShard, n),
+		mask:   uint64(n - 1),
+		seed:   maphash.MakeSeed(),
+	}
+	for i := range s.shards {
+		s.shards[i] = newArenaShard(shardSize, shardEntries)
+	}
+	return s
+}
+
+// shard returns the hash of key and the shard holding it.
+func (s *arenaStore) shard(key string) (uint64, *arenaShard) {
+	h := maphash.String(s.seed, key)
+	return h, s.shards[(h>>32)&s.mask]
+}
+
+// shardIndex returns the index of the shard holding key.
+func (s *arenaStore) shardIndex(key string) uint64 {
+	return (maphash.String(s.seed, key) >> 32) & s.mask
+}
+
+func (s *arenaStore) MaxItemBytes() int64 {
+	return int64(len(s.shards[0].buf) - arenaHeaderSize)
+}
+
+func (s *arenaStore) Get(key string) (item, bool) {
+	h, sh := s.shard(key)
+	return sh.Get(h, key)
+}
+
+func (s *arenaStore) Set(key string, it item) []keyItem {
+	h, sh := s.shard(key)
+	return sh.Set(h, s.seed, key, it)
+}
+
+func (s *arenaStore) Delete(key string) (item, bool) {
+	h, sh := s.shard(key)
+	return sh.Delete(h, key)
+}
+
+func (s *arenaStore) DeleteExpired(key string, now time.Time) (item, bool) {
+	h, sh := s.shard(key)
+	return sh.DeleteExpired(h, key, now)
+}
+
+func (s *arenaStore) DeleteExpiredItems(now time.Time) []keyItem {
+	var deleted []keyItem
+	for _, sh := range s.shards {
+		for more := true; more; {
+			var batch []keyItem
+			batch, more = sh.DeleteExpiredItems(now, expiredBatchSize)
+			deleted = append(deleted, batch...)
+		}
+	}
+	return deleted
+}
+
+// Clear removes all items, and returns them if collect is true. All shards are locked
+// together, like for the map store.
+func (s *arenaStore) Clear(collect bool) []keyItem {
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+	}
+	var cleared []keyItem
+	for _, sh := range s.shards {
+		cleared = append(cleared, sh.clear(collect)...)
+		sh.mu.Unlock()
+	}
+	return cleared
+}
+
+func (s *arenaStore) Len() int {
+	var n int
+	for _, sh := range s.shards {
+		sh.mu.RLock()
+		n += len(sh.index)
+		sh.mu.RUnlock()
+	}
+	return n
+}
+
+func (s *arenaStore) Bytes() int64 {
+	var n int64
+	for _, sh := range s.shards {
+		sh.mu.RLock()
+		n += sh.bytes
+		sh.mu.RUnlock()
+	}
+	return n
+}
+
+func (s *arenaStore) KeyItemsMatching(pattern string) []keyItem {
+	var items []keyItem
+	for _, sh := range s.shards {
+		sh.mu.RLock()
+		items = sh.appendLive(items, pattern)
+		sh.mu.RUnlock()
+	}
+	return items
+}
+
+func (s *arenaStore) DeleteMatching(pattern string) []keyItem {
+	var deleted []keyItem
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		start := len(deleted)
+		deleted = sh.appendLive(deleted, pattern)
+		for _, ki := range deleted[start:] {
+			h := maphash.String(s.seed, ki.Key)
+			sh.remove(h, int(sh.index[h]))
+		}
+		sh.mu.Unlock()
+	}
+	return deleted
+}
+
+func (s *arenaStore) Atomically(keys []string, fn func(tx storageTx[[]byte])) {
+	for _, i := range lockOrder(keys, s.shardIndex) {
+		s.shards[i].mu.Lock()
+		defer s.shards[i].mu.Unlock()
+	}
+	fn(arenaTx{s})
+}
+
+// arenaTx is the [storageTx] of an arenaStore, whose shards are locked by Atomically.
+type arenaTx struct{ s *arenaStore }
+
+func (tx arenaTx) Get(key string) (item, bool) {
+	h, sh := tx.s.shard(key)
+	return sh.get(h, key)
+}
+
+func (tx arenaTx) Set(key string, it item) []keyItem {
+	h, sh := tx.s.shard(key)
+	return sh.set(h, tx.s.seed, key, it)
+}
+
+func (tx arenaTx) Delete(key string) (item, bool) {
+	h, sh := tx.s.shard(key)
+	return sh.delete(h, key)
+}
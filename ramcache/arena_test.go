@@ -0,0 +1,239 @@
+package ramcache
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArenaStore(t *testing.T) {
+	s := newArenaStore(4, 0, 1<<20)
+	for i := range 1000 {
+		s.Set("user:"+strconv.Itoa(i), item{Value: []byte("value" + strconv.Itoa(i))})
+	}
+	assert.Equal(t, 1000, s.Len())
+
+	it, ok := s.Get("user:42")
+	require.True(t, ok)
+	assert.Equal(t, "value42", string(it.Value))
+
+	s.Set("user:42", item{Value: []byte("changed")})
+	it, ok = s.Get("user:42")
+	require.True(t, ok)
+	assert.Equal(t, "changed", string(it.Value))
+	assert.Equal(t, 1000, s.Len())
+
+	it, ok = s.Delete("user:42")
+	require.True(t, ok)
+	assert.Equal(t, "changed", string(it.Value))
+	_, ok = s.Get("user:42")
+	assert.False(t, ok)
+	_, ok = s.Delete("user:42")
+	assert.False(t, ok)
+
+	assert.Len(t, s.KeyItemsMatching("user:1??"), 100)
+	assert.Len(t, s.DeleteMatching("user:1*"), 111)
+	assert.Equal(t, 1000-1-111, s.Len())
+
+	cleared := s.Clear(true)
+	assert.Len(t, cleared, 1000-1-111)
+	assert.Equal(t, 0, s.Len())
+	assert.Equal(t, int64(0), s.Bytes())
+}
+
+func TestArenaStore_Wrap(t *testing.T) {
+	// Each entry takes up 16 (header) + 2 (key) + 2 (value) = 20 bytes, so the arena
+	// holds 10 of them.
+	s := newArenaStore(1, 0, 200)
+	var evicted []string
+	for i := range 25 {
+		key := fmt.Sprintf("%02d", i)
+		for _, ki := range s.Set(key, item{Value: []byte(key)}) {
+			evicted = append(evicted, ki.Key)
+		}
+	}
+	want := make([]string, 15)
+	for i := range want {
+		want[i] = fmt.Sprintf("%02d", i)
+	}
+	assert.Equal(t, want, evicted, "expected the oldest entries to be evicted first")
+	assert.Equal(t, 10, s.Len())
+	assert.Equal(t, int64(40), s.Bytes())
+	for i := 15; i < 25; i++ {
+		key := fmt.Sprintf("%02d", i)
+		it, ok := s.Get(key)
+		require.True(t, ok, key)
+		assert.Equal(t, key, string(it.Value))
+	}
+
+	// Deleted entries are skipped when the ring wraps around them.
+	s.Delete("15")
+	evictedKeys := s.Set("xx", item{Value: []byte("xx")})
+	assert.Empty(t, evictedKeys, "expected the deleted entry's space to be reused")
+	evictedKeys = s.Set("yy", item{Value: []byte("yy")})
+	require.Len(t, evictedKeys, 1)
+	assert.Equal(t, "16", evictedKeys[0].Key)
+	assert.Equal(t, "16", string(evictedKeys[0].Item.Value))
+}
+
+func TestArenaStore_MaxEntries(t *testing.T) {
+	s := newArenaStore(1, 3, 1<<10)
+	var evicted []string
+	for _, key := range []string{"a", "b", "c", "d"} {
+		for _, ki := range s.Set(key, item{Value: []byte(key)}) {
+			evicted = append(evicted, ki.Key)
+		}
+	}
+	assert.Equal(t, []string{"a"}, evicted)
+	assert.Equal(t, 3, s.Len())
+}
+
+func TestArenaStore_HashCollision(t *testing.T) {
+	s := newArenaStore(1, 0, 1<<10)
+	sh := s.shards[0]
+	sh.Set(1, s.seed, "a", item{Value: []byte("a")})
+	evicted := sh.Set(1, s.seed, "b", item{Value: []byte("b")})
+	require.Len(t, evicted, 1, "expected the colliding key to be evicted")
+	assert.Equal(t, "a", evicted[0].Key)
+	_, ok := sh.Get(1, "a")
+	assert.False(t, ok)
+	it, ok := sh.Get(1, "b")
+	require.True(t, ok)
+	assert.Equal(t, "b", string(it.Value))
+}
+
+func TestArenaStore_Expiry(t *testing.T) {
+	s := newArenaStore(2, 0, 1<<20)
+	now := time.Now()
+	s.Set("expired1", item{Value: []byte("v"), Expiry: now.Add(-time.Minute)})
+	s.Set("expired2", item{Value: []byte("v"), Expiry: now.Add(-2 * time.Minute)})
+	s.Set("live", item{Value: []byte("v"), Expiry: now.Add(time.Minute)})
+	s.Set("forever", item{Value: []byte("v")})
+	// Overwriting and deleting make the expiry heap entries of the old items stale.
+	s.Set("overwritten", item{Value: []byte("v"), Expiry: now.Add(-time.Minute)})
+	s.Set("overwritten", item{Value: []byte("v")})
+	s.Set("deleted", item{Value: []byte("v"), Expiry: now.Add(-time.Minute)})
+	s.Delete("deleted")
+
+	_, ok := s.DeleteExpired("live", now)
+	assert.False(t, ok)
+	it, ok := s.Get("expired1")
+	require.True(t, ok)
+	assert.WithinDuration(t, now.Add(-time.Minute), it.Expiry, time.Microsecond)
+
+	var keys []string
+	for _, ki := range s.DeleteExpiredItems(now) {
+		keys = append(keys, ki.Key)
+	}
+	sort.Strings(keys)
+	assert.Equal(t, []string{"expired1", "expired2"}, keys)
+	assert.Equal(t, 3, s.Len())
+
+	for i := range 3 * expiredBatchSize {
+		s.Set(strconv.Itoa(i), item{Expiry: now.Add(-time.Minute)})
+	}
+	assert.Len(t, s.DeleteExpiredItems(now), 3*expiredBatchSize)
+}
+
+// TestArenaStore_Random checks the arena store against a map under random operations:
+// every key found must have its latest value, and deleted keys must not be found.
+func TestArenaStore_Random(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2)) //nolint:gosec // a deterministic sequence is wanted
+	s := newArenaStore(2, 0, 4096)
+	want := make(map[string]string)
+	for range 100_000 {
+		key := "key" + strconv.Itoa(r.IntN(200))
+		switch r.IntN(4) {
+		case 0:
+			s.Delete(key)
+			delete(want, key)
+		case 1:
+			it, ok := s.Get(key)
+			if ok {
+				require.Equal(t, want[key], string(it.Value), key)
+			}
+		default:
+			value := strconv.Itoa(r.Int()) + string(make([]byte, r.IntN(64)))
+			for _, ki := range s.Set(key, item{Value: []byte(value)}) {
+				require.Equal(t, want[ki.Key], string(ki.Item.Value), "evicted %s", ki.Key)
+				delete(want, ki.Key)
+			}
+			want[key] = value
+		}
+	}
+	require.Equal(t, len(want), s.Len())
+	var bytes int64
+	for key, value := range want {
+		it, ok := s.Get(key)
+		require.True(t, ok, key)
+		require.Equal(t, value, string(it.Value))
+		bytes += int64(len(key) + len(value))
+	}
+	assert.Equal(t, bytes, s.Bytes())
+}
+
+// newBenchStorage returns a storage engine of the given kind holding n items.
+func newBenchStorage(kind string, n int) storage[[]byte] {
+	var s storage[[]byte]
+	if kind == StorageArena {
+		s = newArenaStore(16, 0, int64(n)*64)
+	} else {
+		s = newShardedStore(16, 0, 0, nil)
+	}
+	value := make([]byte, 32)
+	for i := range n {
+		s.Set("key"+strconv.Itoa(i), item{Value: value})
+	}
+	return s
+}
+
+// BenchmarkStorage_GC measures the duration of a full garbage collection while the cache
+// holds millions of items.
+func BenchmarkStorage_GC(b *testing.B) {
+	for _, kind := range []string{StorageMap, StorageArena} {
+		b.Run(kind, func(b *testing.B) {
+			s := newBenchStorage(kind, 2_000_000)
+			runtime.GC()
+			b.ResetTimer()
+			for range b.N {
+				runtime.GC()
+			}
+			b.StopTimer()
+			var stats runtime.MemStats
+			runtime.ReadMemStats(&stats)
+			b.ReportMetric(float64(stats.PauseNs[(stats.NumGC+255)%256]), "last-pause-ns")
+			runtime.KeepAlive(s)
+		})
+	}
+}
+
+func BenchmarkStorage_Parallel(b *testing.B) {
+	const keys = 1 << 20
+	for _, kind := range []string{StorageMap, StorageArena} {
+		b.Run(kind, func(b *testing.B) {
+			s := newBenchStorage(kind, keys)
+			value := make([]byte, 32)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := rand.IntN(keys) //nolint:gosec // not security sensitive
+				for pb.Next() {
+					key := "key" + strconv.Itoa(i%keys)
+					// 90% reads, 10% writes.
+					if i%10 == 0 {
+						s.Set(key, item{Value: value})
+					} else {
+						s.Get(key)
+					}
+					i += 7919
+				}
+			})
+		})
+	}
+}
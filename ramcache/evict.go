@@ -0,0 +1,50 @@
+package ramcache
+
+import "github.com/bartventer/gocache/pkg/driver"
+
+// EvictionReason is the reason an item left the cache, for [Options.OnEvict].
+type EvictionReason int
+
+const (
+	// ReasonExpired means the item's TTL elapsed. It was removed by the periodic cleanup,
+	// lazily when accessed, or by DelKeys.
+	ReasonExpired EvictionReason = iota + 1
+	// ReasonCapacity means the item was evicted to stay within the size limits.
+	ReasonCapacity
+	// ReasonDeleted means the item was deleted with Del or DelKeys.
+	ReasonDeleted
+	// ReasonCleared means the item was removed by Clear.
+	ReasonCleared
+)
+
+// String returns the name of the reason.
+func (r EvictionReason) String() string {
+	switch r {
+	case ReasonExpired:
+		return "expired"
+	case ReasonCapacity:
+		return "capacity"
+	case ReasonDeleted:
+		return "deleted"
+	case ReasonCleared:
+		return "cleared"
+	default:
+		return "unknown"
+	}
+}
+
+// evicted reports that the item for key left the cache to watchers and to
+// [Options.OnEvict]. It must not be called with a store lock held.
+func (r *ramcache[K]) evicted(key string, it item, reason EvictionReason) {
+	switch reason {
+	case ReasonExpired:
+		r.watchers.Notify(driver.EventExpire, key)
+	case ReasonCapacity, ReasonDeleted:
+		r.watchers.Notify(driver.EventDelete, key)
+	case ReasonCleared:
+		// Clear does not emit events.
+	}
+	if r.opts.OnEvict != nil {
+		r.opts.OnEvict(key, it.Value, reason)
+	}
+}
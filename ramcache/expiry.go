@@ -0,0 +1,99 @@
+package ramcache
+
+import (
+	"container/heap"
+	"time"
+)
+
+// expiryEntry is a key with an expiry time in an expiryIndex.
+type expiryEntry struct {
+	key    string    // key is the cache key.
+	expiry time.Time // expiry is the expiry time of the key's item.
+	index  int       // index is the position of the entry in the heap.
+}
+
+// expiryHeap is a min-heap of entries ordered by expiry time. It implements
+// [heap.Interface].
+type expiryHeap []*expiryEntry
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].expiry.Before(h[j].expiry) }
+
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap) Push(x any) {
+	e := x.(*expiryEntry) //nolint:errcheck // only *expiryEntry values are pushed
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *expiryHeap) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return e
+}
+
+// expiryIndex tracks the expiry times of the keys that have one, so that the keys that
+// expired can be found without looking at the others.
+type expiryIndex struct {
+	heap    expiryHeap              // heap orders the entries by expiry time.
+	entries map[string]*expiryEntry // entries maps keys to their entries.
+}
+
+func newExpiryIndex() *expiryIndex {
+	return &expiryIndex{entries: make(map[string]*expiryEntry)}
+}
+
+// Len returns the number of keys tracked.
+func (x *expiryIndex) Len() int {
+	return len(x.heap)
+}
+
+// Set tracks key with the given expiry time. A zero expiry stops tracking key.
+func (x *expiryIndex) Set(key string, expiry time.Time) {
+	e, exists := x.entries[key]
+	switch {
+	case expiry.IsZero():
+		x.Remove(key)
+	case exists:
+		e.expiry = expiry
+		heap.Fix(&x.heap, e.index)
+	default:
+		e = &expiryEntry{key: key, expiry: expiry}
+		x.entries[key] = e
+		heap.Push(&x.heap, e)
+	}
+}
+
+// Remove stops tracking key.
+func (x *expiryIndex) Remove(key string) {
+	if e, exists := x.entries[key]; exists {
+		heap.Remove(&x.heap, e.index)
+		delete(x.entries, key)
+	}
+}
+
+// PopExpired stops tracking and returns the key that expires first, if it expired
+// before now.
+func (x *expiryIndex) PopExpired(now time.Time) (string, bool) {
+	if len(x.heap) == 0 || !now.After(x.heap[0].expiry) {
+		return "", false
+	}
+	e := x.heap[0]
+	heap.Pop(&x.heap)
+	delete(x.entries, e.key)
+	return e.key, true
+}
+
+// Reset stops tracking all keys.
+func (x *expiryIndex) Reset() {
+	x.heap = nil
+	x.entries = make(map[string]*expiryEntry)
+}
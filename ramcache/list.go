@@ -0,0 +1,75 @@
+package ramcache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// listMagic prefixes the encoding of a list, which is stored as the value of its key.
+const listMagic = "\x00GCLIST"
+
+// errNotList is returned by the list operations of scripts on a value that isn't a list.
+var errNotList = errors.New("value is not a list")
+
+// encodeList encodes the elements of a list: the magic, the number of elements, then
+// each element prefixed by its length, as uvarints.
+func encodeList(list [][]byte) []byte {
+	size := len(listMagic) + binary.MaxVarintLen64
+	for _, e := range list {
+		size += binary.MaxVarintLen64 + len(e)
+	}
+	b := make([]byte, 0, size)
+	b = append(b, listMagic...)
+	b = binary.AppendUvarint(b, uint64(len(list)))
+	for _, e := range list {
+		b = binary.AppendUvarint(b, uint64(len(e)))
+		b = append(b, e...)
+	}
+	return b
+}
+
+// decodeList decodes a list encoded by encodeList. It returns [errNotList] if b is not
+// such an encoding.
+func decodeList(b []byte) ([][]byte, error) {
+	b, ok := bytes.CutPrefix(b, []byte(listMagic))
+	if !ok {
+		return nil, errNotList
+	}
+	n, k := binary.Uvarint(b)
+	if k <= 0 || n > uint64(len(b)) {
+		return nil, errNotList
+	}
+	b = b[k:]
+	list := make([][]byte, n)
+	for i := range list {
+		size, k := binary.Uvarint(b)
+		if k <= 0 || size > uint64(len(b)-k) {
+			return nil, errNotList
+		}
+		list[i] = bytes.Clone(b[k : k+int(size)]) //nolint:gosec // bounded by len(b)
+		b = b[k+int(size):]                       //nolint:gosec // bounded by len(b)
+	}
+	if len(b) != 0 {
+		return nil, errNotList
+	}
+	return list, nil
+}
+
+// listRange returns the bounds of the elements from start to stop, inclusive, of a list
+// of n elements. As with the Redis LRANGE command, negative indexes count from the end
+// of the list, and out of range indexes are clamped. It returns lo == hi if the range is
+// empty.
+func listRange(n, start, stop int64) (lo, hi int64) {
+	if start < 0 {
+		start = max(start+n, 0)
+	}
+	if stop < 0 {
+		stop += n
+	}
+	stop = min(stop, n-1)
+	if start > stop {
+		return 0, 0
+	}
+	return start, stop + 1
+}
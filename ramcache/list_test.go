@@ -0,0 +1,53 @@
+package ramcache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_encodeList(t *testing.T) {
+	for _, list := range [][][]byte{
+		{},
+		{[]byte("a")},
+		{[]byte(""), []byte("b"), []byte(listMagic)},
+	} {
+		got, err := decodeList(encodeList(list))
+		require.NoError(t, err)
+		assert.Equal(t, list, got)
+	}
+}
+
+func Test_decodeList_Invalid(t *testing.T) {
+	valid := encodeList([][]byte{[]byte("a"), []byte("b")})
+	for _, b := range [][]byte{
+		nil,
+		[]byte("value"),
+		[]byte(listMagic),
+		valid[:len(valid)-1],
+		append(valid, 0),
+	} {
+		_, err := decodeList(b)
+		assert.ErrorIs(t, err, errNotList, "decodeList(%q)", b)
+	}
+}
+
+func Test_listRange(t *testing.T) {
+	tests := []struct {
+		n, start, stop int64
+		lo, hi         int64
+	}{
+		{n: 5, start: 0, stop: -1, lo: 0, hi: 5},
+		{n: 5, start: 1, stop: 2, lo: 1, hi: 3},
+		{n: 5, start: -2, stop: -1, lo: 3, hi: 5},
+		{n: 5, start: -10, stop: 10, lo: 0, hi: 5},
+		{n: 5, start: 5, stop: 10, lo: 0, hi: 0},
+		{n: 5, start: 3, stop: 1, lo: 0, hi: 0},
+		{n: 0, start: 0, stop: -1, lo: 0, hi: 0},
+	}
+	for _, tt := range tests {
+		lo, hi := listRange(tt.n, tt.start, tt.stop)
+		assert.Equal(t, [2]int64{tt.lo, tt.hi}, [2]int64{lo, hi}, "listRange(%d, %d, %d)", tt.n, tt.start, tt.stop)
+	}
+}
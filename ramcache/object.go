@@ -0,0 +1,261 @@
+package ramcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/pkg/clock"
+	"github.com/bartventer/gocache/pkg/driver"
+)
+
+// ObjectOptions are the configuration options for an [ObjectCache].
+type ObjectOptions[V any] struct {
+	// CleanupInterval is the interval at which checks for expired items are performed.
+	// If not set, the default is 5 minutes.
+	CleanupInterval time.Duration
+
+	// MaxEntries is the maximum number of items in the cache. When a Set would exceed
+	// it, items are evicted according to Policy.
+	// If not set, the number of items is not limited.
+	MaxEntries int
+
+	// Policy is the eviction policy used when the cache is bounded: [PolicyLRU],
+	// [PolicyLFU], [PolicyARC] or [PolicyTinyLFU]. Unknown names fall back to [PolicyLRU].
+	// If not set, the default is [PolicyLRU].
+	Policy string
+
+	// NewPolicy returns a custom eviction policy, and takes precedence over Policy.
+	// It's called once per shard with the shard's share of MaxEntries.
+	NewPolicy func(capacity int) EvictionPolicy
+
+	// Shards is the number of independently locked segments the cache is split into by
+	// key hash, rounded up to a power of two. See [Options.Shards].
+	// If not set, the default is 1.
+	Shards int
+
+	// Clock tells the time for TTLs, and creates the ticker of the periodic cleanup.
+	// If not set, the system clock is used.
+	Clock clock.Clock
+
+	// OnEvict, if set, is called for every item that leaves the cache other than by
+	// being overwritten, with the reason it left. See [Options.OnEvict].
+	OnEvict func(key string, value V, reason EvictionReason)
+
+	// Clone, if set, is applied to a value before Get returns it, so that callers may
+	// mutate the result without affecting the cached value or other callers. Without it,
+	// Get returns the stored value itself, which callers must treat as read-only.
+	// If not set, values are returned as stored.
+	Clone func(V) V
+}
+
+// policy returns the eviction policy selected by the options for a shard of the given
+// capacity.
+func (o *ObjectOptions[V]) policy(capacity int) EvictionPolicy {
+	if o.NewPolicy != nil {
+		return o.NewPolicy(capacity)
+	}
+	policy, err := newPolicy(o.Policy, capacity)
+	if err != nil {
+		return newLRUPolicy()
+	}
+	return policy
+}
+
+// revise revises the options, ensuring sensible defaults are set.
+func (o *ObjectOptions[V]) revise() {
+	if o.CleanupInterval <= 0 {
+		o.CleanupInterval = 5 * time.Minute
+	}
+	if o.MaxEntries < 0 {
+		o.MaxEntries = 0
+	}
+	if o.Shards <= 0 {
+		o.Shards = 1
+	}
+	if o.Policy == "" {
+		o.Policy = PolicyLRU
+	}
+}
+
+// ObjectCache is an in-memory cache of Go values. Unlike the byte cache returned by
+// [New], values are stored as they are, without being marshaled, so they can't be
+// shared through the [cache.Cache] interface or persisted. Expiry, the periodic cleanup
+// and eviction work as they do in the byte cache.
+//
+// Values passed to Set are stored by reference and must not be mutated afterwards.
+// Set [ObjectOptions.Clone] to protect cached values from callers of Get.
+type ObjectCache[K driver.String, V any] struct {
+	store     *store[V]         // store is the in-memory store.
+	opts      *ObjectOptions[V] // opts is the cache options.
+	clock     clock.Clock       // clock tells the time for expiry.
+	stopCh    chan struct{}     // stopCh is the stop channel.
+	closeOnce sync.Once         // closeOnce ensures that the cache is closed only once.
+}
+
+// NewObjectCache creates a new in-memory cache of values of type V.
+func NewObjectCache[K driver.String, V any](ctx context.Context, opts *ObjectOptions[V]) *ObjectCache[K, V] {
+	if opts == nil {
+		opts = &ObjectOptions[V]{}
+	}
+	opts.revise()
+	c := &ObjectCache[K, V]{
+		store:  newStoreOf[V](opts.Shards, opts.MaxEntries, 0, opts.policy, nil),
+		opts:   opts,
+		clock:  clock.OrReal(opts.Clock),
+		stopCh: make(chan struct{}),
+	}
+	go c.cleanupExpiredItems(c.clock.NewTicker(opts.CleanupInterval))
+	return c
+}
+
+// cleanupExpiredItems removes expired items from the store on every tick of ticker,
+// until the cache is closed.
+func (c *ObjectCache[K, V]) cleanupExpiredItems(ticker clock.Ticker) {
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			c.removeExpiredItems()
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+// removeExpiredItems removes expired items from the store.
+func (c *ObjectCache[K, V]) removeExpiredItems() {
+	for _, ke := range c.store.DeleteExpiredItems(c.clock.Now()) {
+		c.evicted(ke.Key, ke.Item, ReasonExpired)
+	}
+}
+
+// evicted reports that the item for key left the cache to [ObjectOptions.OnEvict].
+// It must not be called with a store lock held.
+func (c *ObjectCache[K, V]) evicted(key string, e entry[V], reason EvictionReason) {
+	if c.opts.OnEvict != nil {
+		c.opts.OnEvict(key, e.Value, reason)
+	}
+}
+
+// errKeyNotFound returns the error for a missing key.
+func errKeyNotFound[K driver.String](key K) error {
+	return gcerrors.NewWithScheme(Scheme, errors.Join(cache.ErrKeyNotFound, fmt.Errorf("key %s not found", key)))
+}
+
+// Get returns the value for key, passed through [ObjectOptions.Clone] if set. It returns
+// an error wrapping [cache.ErrKeyNotFound] if the key doesn't exist or has expired.
+func (c *ObjectCache[K, V]) Get(ctx context.Context, key K) (V, error) {
+	now := c.clock.Now()
+	e, exists := c.store.Get(string(key))
+	if !exists || e.IsExpired(now) {
+		if exists {
+			if e, deleted := c.store.DeleteExpired(string(key), now); deleted {
+				c.evicted(string(key), e, ReasonExpired)
+			}
+		}
+		var zero V
+		return zero, errKeyNotFound(key)
+	}
+	if c.opts.Clone != nil {
+		return c.opts.Clone(e.Value), nil
+	}
+	return e.Value, nil
+}
+
+// Set sets the value for key, without expiry.
+func (c *ObjectCache[K, V]) Set(ctx context.Context, key K, value V) error {
+	c.set(key, value, 0)
+	return nil
+}
+
+// SetWithTTL sets the value for key, expiring after ttl.
+func (c *ObjectCache[K, V]) SetWithTTL(ctx context.Context, key K, value V, ttl time.Duration) error {
+	if err := cache.ValidateTTL(ttl); err != nil {
+		return gcerrors.NewWithScheme(Scheme, fmt.Errorf("invalid expiry duration %q: %w", ttl, err))
+	}
+	c.set(key, value, ttl)
+	return nil
+}
+
+func (c *ObjectCache[K, V]) set(key K, value V, expiry time.Duration) {
+	var expiryTime time.Time
+	if expiry != 0 {
+		expiryTime = c.clock.Now().Add(expiry)
+	}
+	for _, ke := range c.store.Set(string(key), entry[V]{Value: value, Expiry: expiryTime}) {
+		c.evicted(ke.Key, ke.Item, ReasonCapacity)
+	}
+}
+
+// Exists reports whether key exists and has not expired.
+func (c *ObjectCache[K, V]) Exists(ctx context.Context, key K) (bool, error) {
+	now := c.clock.Now()
+	e, exists := c.store.Get(string(key))
+	if exists && e.IsExpired(now) {
+		if e, deleted := c.store.DeleteExpired(string(key), now); deleted {
+			c.evicted(string(key), e, ReasonExpired)
+		}
+		exists = false
+	}
+	return exists, nil
+}
+
+// Count returns the number of unexpired keys matching pattern, which uses the Redis
+// glob-style syntax.
+func (c *ObjectCache[K, V]) Count(ctx context.Context, pattern K) (int64, error) {
+	now := c.clock.Now()
+	var count int64
+	for _, ke := range c.store.KeyItemsMatching(string(pattern)) {
+		if !ke.Item.IsExpired(now) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Del deletes key. It returns an error wrapping [cache.ErrKeyNotFound] if the key
+// doesn't exist.
+func (c *ObjectCache[K, V]) Del(ctx context.Context, key K) error {
+	e, exists := c.store.Delete(string(key))
+	if !exists {
+		return errKeyNotFound(key)
+	}
+	c.evicted(string(key), e, ReasonDeleted)
+	return nil
+}
+
+// DelKeys deletes the keys matching pattern, which uses the Redis glob-style syntax.
+func (c *ObjectCache[K, V]) DelKeys(ctx context.Context, pattern K) error {
+	now := c.clock.Now()
+	for _, ke := range c.store.DeleteMatching(string(pattern)) {
+		if ke.Item.IsExpired(now) {
+			c.evicted(ke.Key, ke.Item, ReasonExpired)
+		} else {
+			c.evicted(ke.Key, ke.Item, ReasonDeleted)
+		}
+	}
+	return nil
+}
+
+// Clear removes all items from the cache.
+func (c *ObjectCache[K, V]) Clear(ctx context.Context) error {
+	for _, ke := range c.store.Clear(c.opts.OnEvict != nil) {
+		c.evicted(ke.Key, ke.Item, ReasonCleared)
+	}
+	return nil
+}
+
+// Close stops the periodic cleanup. The cache remains usable, but expired items are
+// then only removed when accessed.
+func (c *ObjectCache[K, V]) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.stopCh)
+	})
+	return nil
+}
@@ -0,0 +1,136 @@
+package ramcache
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/pkg/clock/clocktest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type objectValue struct {
+	Name string
+	Tags []string
+}
+
+func TestObjectCache(t *testing.T) {
+	ctx := context.Background()
+	c := NewObjectCache[string, *objectValue](ctx, nil)
+	defer c.Close()
+
+	v := &objectValue{Name: "a"}
+	require.NoError(t, c.Set(ctx, "key1", v))
+	got, err := c.Get(ctx, "key1")
+	require.NoError(t, err)
+	assert.Same(t, v, got, "expected the stored value itself without Clone")
+
+	exists, err := c.Exists(ctx, "key1")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	require.NoError(t, c.Set(ctx, "key2", &objectValue{Name: "b"}))
+	require.NoError(t, c.Set(ctx, "other", &objectValue{Name: "c"}))
+	count, err := c.Count(ctx, "key*")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	require.NoError(t, c.Del(ctx, "key1"))
+	_, err = c.Get(ctx, "key1")
+	require.ErrorIs(t, err, cache.ErrKeyNotFound)
+	require.ErrorIs(t, c.Del(ctx, "key1"), cache.ErrKeyNotFound)
+
+	require.NoError(t, c.DelKeys(ctx, "key*"))
+	count, err = c.Count(ctx, "*")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	require.NoError(t, c.Clear(ctx))
+	assert.Equal(t, 0, c.store.Len())
+
+	require.Error(t, c.SetWithTTL(ctx, "key", v, -time.Second))
+}
+
+func TestObjectCache_Clone(t *testing.T) {
+	ctx := context.Background()
+	c := NewObjectCache[string, objectValue](ctx, &ObjectOptions[objectValue]{
+		Clone: func(v objectValue) objectValue {
+			v.Tags = slices.Clone(v.Tags)
+			return v
+		},
+	})
+	defer c.Close()
+
+	require.NoError(t, c.Set(ctx, "key", objectValue{Name: "a", Tags: []string{"x", "y"}}))
+	got, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	got.Tags[0] = "mutated"
+
+	got, err = c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"x", "y"}, got.Tags, "expected the cached value not to be mutated")
+}
+
+func TestObjectCache_TTL(t *testing.T) {
+	ctx := context.Background()
+	clk := clocktest.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	evicted := make(chan string, 1)
+	c := NewObjectCache[string, int](ctx, &ObjectOptions[int]{
+		Clock:           clk,
+		CleanupInterval: time.Minute,
+		OnEvict: func(key string, value int, reason EvictionReason) {
+			if reason == ReasonExpired {
+				evicted <- key
+			}
+		},
+	})
+	defer c.Close()
+
+	require.NoError(t, c.SetWithTTL(ctx, "lazy", 1, 30*time.Second))
+	clk.Advance(29 * time.Second)
+	got, err := c.Get(ctx, "lazy")
+	require.NoError(t, err)
+	assert.Equal(t, 1, got)
+
+	clk.Advance(2 * time.Second)
+	_, err = c.Get(ctx, "lazy")
+	require.ErrorIs(t, err, cache.ErrKeyNotFound, "expected the key to expire lazily")
+	assert.Equal(t, "lazy", <-evicted)
+
+	require.NoError(t, c.SetWithTTL(ctx, "cleanup", 2, 10*time.Second))
+	clk.Advance(29 * time.Second)
+	select {
+	case key := <-evicted:
+		assert.Equal(t, "cleanup", key)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the cleanup")
+	}
+	assert.Equal(t, 0, c.store.Len())
+}
+
+func TestObjectCache_OnEvict(t *testing.T) {
+	ctx := context.Background()
+	var records []evictRecord
+	c := NewObjectCache[string, string](ctx, &ObjectOptions[string]{
+		MaxEntries: 2,
+		OnEvict: func(key string, value string, reason EvictionReason) {
+			records = append(records, evictRecord{Key: key, Value: value, Reason: reason})
+		},
+	})
+	defer c.Close()
+
+	require.NoError(t, c.Set(ctx, "a", "1"))
+	require.NoError(t, c.Set(ctx, "b", "2"))
+	require.NoError(t, c.Set(ctx, "c", "3"))
+	require.NoError(t, c.Del(ctx, "b"))
+	require.NoError(t, c.Clear(ctx))
+
+	assert.Equal(t, []evictRecord{
+		{Key: "a", Value: "1", Reason: ReasonCapacity},
+		{Key: "b", Value: "2", Reason: ReasonDeleted},
+		{Key: "c", Value: "3", Reason: ReasonCleared},
+	}, records)
+}
@@ -1,12 +1,101 @@
 package ramcache
 
-import "time"
+import (
+	"time"
+
+	"github.com/bartventer/gocache/pkg/clock"
+)
 
 // Options are the configuration options for the RAM cache.
 type Options struct {
 	// CleanupInterval is the interval at which checks for expired items are performed.
 	// If not set, the default is 5 minutes.
 	CleanupInterval time.Duration
+
+	// MaxEntries is the maximum number of items in the cache. When a Set would exceed
+	// it, items are evicted according to Policy.
+	// If not set, the number of items is not limited.
+	MaxEntries int
+
+	// MaxBytes is the maximum total size of the items in the cache, where the size of an
+	// item is the length of its key plus the length of its value. When a Set would exceed
+	// it, items are evicted according to Policy. Setting a single item larger than
+	// MaxBytes fails.
+	// In a URL, the size may have a unit suffix, for example "maxbytes=64MiB".
+	// If not set, the size is not limited.
+	MaxBytes int64
+
+	// Policy is the eviction policy used when the cache is bounded: [PolicyLRU],
+	// [PolicyLFU], [PolicyARC] or [PolicyTinyLFU]. [OpenCacheURL] rejects unknown names,
+	// while [New] falls back to [PolicyLRU].
+	// If not set, the default is [PolicyLRU].
+	Policy string
+
+	// NewPolicy returns a custom eviction policy, and takes precedence over Policy.
+	// It's called once per shard; capacity is the shard's share of MaxEntries, or zero
+	// if the cache is bounded by MaxBytes alone.
+	NewPolicy func(capacity int) EvictionPolicy
+
+	// Shards is the number of independently locked segments the cache is split into by
+	// key hash, rounded up to a power of two. More shards reduce lock contention under
+	// concurrent use. MaxEntries and MaxBytes are divided evenly between the shards, so
+	// with more than one shard, eviction approximates the policy per shard rather than
+	// across the whole cache, and an item larger than a shard's share of MaxBytes fails.
+	// If not set, the default is 1.
+	Shards int
+
+	// OnEvict, if set, is called for every item that leaves the cache other than by
+	// being overwritten, with the reason it left. It's called after the cache's locks are
+	// released, so it may call back into the cache, but it's called synchronously by the
+	// operation that removed the item and should return quickly.
+	OnEvict func(key string, value []byte, reason EvictionReason)
+
+	// Storage is the storage engine: [StorageMap] or [StorageArena].
+	//
+	// The map engine keeps each item in a Go map, and supports all eviction policies.
+	//
+	// The arena engine packs keys and values into byte arenas preallocated to MaxBytes,
+	// or 64MiB if MaxBytes is not set, indexed by a map holding no pointers. The garbage
+	// collector does not scan the items, which shortens its pauses when the cache holds
+	// millions of them, at the price of copying values on every Get. The oldest items are
+	// evicted first when an arena is full, and Policy and NewPolicy are ignored. Deleted
+	// and overwritten items take up space until the arena wraps around them.
+	//
+	// [OpenCacheURL] rejects unknown names, while [New] falls back to [StorageMap].
+	// If not set, the default is [StorageMap].
+	Storage string
+
+	// Clock tells the time for TTLs, and creates the tickers of the periodic cleanup
+	// and snapshots. Tests can set a fake clock to control expiry deterministically.
+	// If not set, the system clock is used.
+	Clock clock.Clock
+
+	// SnapshotPath is the file the cache is persisted to, so that it survives restarts.
+	// If the file exists when the cache is created, its entries are loaded, except those
+	// that expired in the meantime. A snapshot is written when the cache is closed, and
+	// every SnapshotInterval. Snapshots are written to a temporary file that is renamed
+	// into place, and use the format of [github.com/bartventer/gocache.GenericCache.Dump].
+	// If not set, the cache is not persisted.
+	SnapshotPath string
+
+	// SnapshotInterval is the interval at which snapshots are written to SnapshotPath.
+	// A failed snapshot is retried at the next interval.
+	// If not set, a snapshot is only written when the cache is closed.
+	SnapshotInterval time.Duration
+
+	// OnSnapshotError, if set, is called with the error of every periodic snapshot that
+	// fails to be written. It's called from the goroutine writing the snapshots. The
+	// error of the snapshot written by Close is returned by Close instead.
+	OnSnapshotError func(err error)
+}
+
+// policy returns the eviction policy selected by the options for a shard of the given
+// capacity.
+func (r *Options) policy(capacity int) (EvictionPolicy, error) {
+	if r.NewPolicy != nil {
+		return r.NewPolicy(capacity), nil
+	}
+	return newPolicy(r.Policy, capacity)
 }
 
 // revise revises the options, ensuring sensible defaults are set.
@@ -14,4 +103,19 @@ func (r *Options) revise() {
 	if r.CleanupInterval <= 0 {
 		r.CleanupInterval = 5 * time.Minute
 	}
+	if r.MaxEntries < 0 {
+		r.MaxEntries = 0
+	}
+	if r.MaxBytes < 0 {
+		r.MaxBytes = 0
+	}
+	if r.Shards <= 0 {
+		r.Shards = 1
+	}
+	if r.Storage == "" {
+		r.Storage = StorageMap
+	}
+	if r.Policy == "" {
+		r.Policy = PolicyLRU
+	}
 }
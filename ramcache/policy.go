@@ -0,0 +1,171 @@
+package ramcache
+
+import "fmt"
+
+// Names of the built-in eviction policies, for [Options.Policy].
+const (
+	PolicyLRU     = "lru"     // PolicyLRU evicts the least recently used key.
+	PolicyLFU     = "lfu"     // PolicyLFU evicts the least frequently used key.
+	PolicyARC     = "arc"     // PolicyARC is the Adaptive Replacement Cache.
+	PolicyTinyLFU = "tinylfu" // PolicyTinyLFU is W-TinyLFU.
+)
+
+// EvictionPolicy decides which keys a bounded cache evicts.
+//
+// The cache reports every key it inserts, reads and removes, and calls Victim while it
+// exceeds its size limits. Methods are called with the cache's lock held, so an
+// implementation need not be safe for concurrent use, and must not call back into the cache.
+type EvictionPolicy interface {
+	// Add records that key was inserted into the cache.
+	Add(key string)
+
+	// Access records a read or overwrite of key, which is in the cache.
+	Access(key string)
+
+	// Remove records that key was removed from the cache other than by eviction,
+	// for example by Del or because it expired.
+	Remove(key string)
+
+	// Victim selects a key to evict and stops tracking it. It may select the key that was
+	// added last, which rejects it. It returns false if no keys are tracked.
+	Victim() (string, bool)
+
+	// Reset stops tracking all keys, when the cache is cleared.
+	Reset()
+}
+
+// defaultPolicyCapacity is the capacity that policies are sized for when the cache is
+// bounded by [Options.MaxBytes] alone.
+const defaultPolicyCapacity = 1 << 16
+
+// newPolicy returns the built-in policy with the given name, sized for capacity keys.
+// A capacity of zero means unknown. An empty name selects [PolicyLRU].
+func newPolicy(name string, capacity int) (EvictionPolicy, error) {
+	switch name {
+	case "", PolicyLRU:
+		return newLRUPolicy(), nil
+	case PolicyLFU:
+		return newLFUPolicy(), nil
+	case PolicyARC:
+		return newARCPolicy(capacity), nil
+	case PolicyTinyLFU:
+		return newTinyLFUPolicy(capacity), nil
+	default:
+		return nil, fmt.Errorf("unknown eviction policy %q", name)
+	}
+}
+
+// node is a key in a keyList.
+type node struct {
+	key        string   // key is the cache key.
+	prev, next *node    // prev and next link the node into its list.
+	list       *keyList // list is the list holding the node, or nil.
+}
+
+// keyList is a doubly linked list of keys, ordered from the front (most recent) to the back.
+type keyList struct {
+	root node // root is the sentinel; root.next is the front and root.prev the back.
+	len  int  // len is the number of nodes in the list.
+}
+
+// newKeyList returns an empty list.
+func newKeyList() *keyList {
+	l := new(keyList)
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	return l
+}
+
+// Len returns the number of nodes in the list.
+func (l *keyList) Len() int {
+	return l.len
+}
+
+// PushFront inserts n, which must not be in a list, at the front of the list.
+func (l *keyList) PushFront(n *node) {
+	n.prev = &l.root
+	n.next = l.root.next
+	n.prev.next = n
+	n.next.prev = n
+	n.list = l
+	l.len++
+}
+
+// Remove removes n from the list.
+func (l *keyList) Remove(n *node) {
+	n.prev.next = n.next
+	n.next.prev = n.prev
+	n.prev, n.next, n.list = nil, nil, nil
+	l.len--
+}
+
+// MoveToFront moves n, which must be in the list, to the front of the list.
+func (l *keyList) MoveToFront(n *node) {
+	if l.root.next == n {
+		return
+	}
+	l.Remove(n)
+	l.PushFront(n)
+}
+
+// Front returns the front node, or nil if the list is empty.
+func (l *keyList) Front() *node {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+// Back returns the back node, or nil if the list is empty.
+func (l *keyList) Back() *node {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// lruPolicy evicts the least recently used key.
+type lruPolicy struct {
+	nodes map[string]*node // nodes maps keys to their nodes.
+	list  *keyList         // list orders the keys by recency of use.
+}
+
+var _ EvictionPolicy = new(lruPolicy)
+
+func newLRUPolicy() *lruPolicy {
+	return &lruPolicy{nodes: make(map[string]*node), list: newKeyList()}
+}
+
+func (p *lruPolicy) Add(key string) {
+	n := &node{key: key}
+	p.nodes[key] = n
+	p.list.PushFront(n)
+}
+
+func (p *lruPolicy) Access(key string) {
+	if n, ok := p.nodes[key]; ok {
+		p.list.MoveToFront(n)
+	}
+}
+
+func (p *lruPolicy) Remove(key string) {
+	if n, ok := p.nodes[key]; ok {
+		p.list.Remove(n)
+		delete(p.nodes, key)
+	}
+}
+
+func (p *lruPolicy) Victim() (string, bool) {
+	n := p.list.Back()
+	if n == nil {
+		return "", false
+	}
+	p.list.Remove(n)
+	delete(p.nodes, n.key)
+	return n.key, true
+}
+
+func (p *lruPolicy) Reset() {
+	p.nodes = make(map[string]*node)
+	p.list = newKeyList()
+}
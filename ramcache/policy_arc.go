@@ -0,0 +1,116 @@
+package ramcache
+
+// arcPolicy is the Adaptive Replacement Cache policy of Megiddo and Modha.
+//
+// Resident keys are split between t1, holding keys seen once recently, and t2, holding
+// keys seen at least twice. The ghost lists b1 and b2 remember keys recently evicted from
+// t1 and t2. A hit in a ghost list adapts the target size of t1, so the policy balances
+// recency and frequency to suit the workload, and a one-off scan cannot flush t2.
+type arcPolicy struct {
+	nodes    map[string]*node // nodes maps resident and ghost keys to their nodes.
+	t1, t2   *keyList         // t1 and t2 are the resident lists.
+	b1, b2   *keyList         // b1 and b2 are the ghost lists.
+	p        int              // p is the target size of t1.
+	capacity int              // capacity is the expected number of resident keys; zero means unknown.
+}
+
+var _ EvictionPolicy = new(arcPolicy)
+
+func newARCPolicy(capacity int) *arcPolicy {
+	p := &arcPolicy{capacity: capacity}
+	p.Reset()
+	return p
+}
+
+// size returns the capacity the lists are balanced against.
+func (p *arcPolicy) size() int {
+	if p.capacity > 0 {
+		return p.capacity
+	}
+	return max(p.t1.Len()+p.t2.Len(), 1)
+}
+
+func (p *arcPolicy) Add(key string) {
+	c := p.size()
+	n, ok := p.nodes[key]
+	switch {
+	case ok && n.list == p.b1:
+		// Recently evicted from t1: favor recency.
+		p.p = min(c, p.p+max(p.b2.Len()/p.b1.Len(), 1))
+		p.b1.Remove(n)
+		p.t2.PushFront(n)
+	case ok && n.list == p.b2:
+		// Recently evicted from t2: favor frequency.
+		p.p = max(0, p.p-max(p.b1.Len()/p.b2.Len(), 1))
+		p.b2.Remove(n)
+		p.t2.PushFront(n)
+	case ok:
+		// Already resident.
+		p.Access(key)
+	default:
+		n = &node{key: key}
+		p.nodes[key] = n
+		p.t1.PushFront(n)
+	}
+	p.trimGhosts()
+}
+
+func (p *arcPolicy) Access(key string) {
+	n, ok := p.nodes[key]
+	if !ok || (n.list != p.t1 && n.list != p.t2) {
+		return
+	}
+	n.list.Remove(n)
+	p.t2.PushFront(n)
+}
+
+func (p *arcPolicy) Remove(key string) {
+	if n, ok := p.nodes[key]; ok {
+		n.list.Remove(n)
+		delete(p.nodes, key)
+	}
+}
+
+func (p *arcPolicy) Victim() (string, bool) {
+	var n *node
+	switch {
+	case p.t1.Len() > 0 && (p.t1.Len() > p.p || p.t2.Len() == 0):
+		n = p.t1.Back()
+		p.t1.Remove(n)
+		p.b1.PushFront(n)
+	case p.t2.Len() > 0:
+		n = p.t2.Back()
+		p.t2.Remove(n)
+		p.b2.PushFront(n)
+	default:
+		return "", false
+	}
+	p.trimGhosts()
+	return n.key, true
+}
+
+// trimGhosts bounds the ghost lists, so that t1+b1 and t1+t2+b1+b2 hold at most c and
+// 2c keys respectively.
+func (p *arcPolicy) trimGhosts() {
+	c := p.size()
+	for p.b1.Len() > 0 && p.t1.Len()+p.b1.Len() > c {
+		p.dropGhost(p.b1)
+	}
+	for p.b2.Len() > 0 && p.t1.Len()+p.t2.Len()+p.b1.Len()+p.b2.Len() > 2*c {
+		p.dropGhost(p.b2)
+	}
+}
+
+// dropGhost forgets the oldest key of a ghost list.
+func (p *arcPolicy) dropGhost(l *keyList) {
+	n := l.Back()
+	l.Remove(n)
+	delete(p.nodes, n.key)
+}
+
+func (p *arcPolicy) Reset() {
+	p.nodes = make(map[string]*node)
+	p.t1, p.t2 = newKeyList(), newKeyList()
+	p.b1, p.b2 = newKeyList(), newKeyList()
+	p.p = 0
+}
@@ -0,0 +1,97 @@
+package ramcache
+
+import "container/heap"
+
+// lfuEntry is a key tracked by the LFU policy.
+type lfuEntry struct {
+	key   string // key is the cache key.
+	freq  uint64 // freq is the number of times the key was added or accessed.
+	seq   uint64 // seq orders entries of equal frequency by recency of use.
+	index int    // index is the position of the entry in the heap.
+}
+
+// lfuHeap is a min-heap of entries ordered by frequency, then by recency of use.
+type lfuHeap []*lfuEntry
+
+func (h lfuHeap) Len() int { return len(h) }
+
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].freq != h[j].freq {
+		return h[i].freq < h[j].freq
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lfuHeap) Push(x any) {
+	e := x.(*lfuEntry) //nolint:errcheck // only *lfuEntry values are pushed
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *lfuHeap) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return e
+}
+
+// lfuPolicy evicts the least frequently used key, breaking ties by evicting the least
+// recently used one.
+type lfuPolicy struct {
+	entries map[string]*lfuEntry // entries maps keys to their heap entries.
+	heap    lfuHeap              // heap orders the entries by frequency.
+	seq     uint64               // seq is the last sequence number assigned.
+}
+
+var _ EvictionPolicy = new(lfuPolicy)
+
+func newLFUPolicy() *lfuPolicy {
+	return &lfuPolicy{entries: make(map[string]*lfuEntry)}
+}
+
+func (p *lfuPolicy) Add(key string) {
+	p.seq++
+	e := &lfuEntry{key: key, freq: 1, seq: p.seq}
+	p.entries[key] = e
+	heap.Push(&p.heap, e)
+}
+
+func (p *lfuPolicy) Access(key string) {
+	e, ok := p.entries[key]
+	if !ok {
+		return
+	}
+	p.seq++
+	e.freq++
+	e.seq = p.seq
+	heap.Fix(&p.heap, e.index)
+}
+
+func (p *lfuPolicy) Remove(key string) {
+	if e, ok := p.entries[key]; ok {
+		heap.Remove(&p.heap, e.index)
+		delete(p.entries, key)
+	}
+}
+
+func (p *lfuPolicy) Victim() (string, bool) {
+	if len(p.heap) == 0 {
+		return "", false
+	}
+	e := p.heap[0]
+	heap.Remove(&p.heap, 0)
+	delete(p.entries, e.key)
+	return e.key, true
+}
+
+func (p *lfuPolicy) Reset() {
+	p.entries = make(map[string]*lfuEntry)
+	p.heap = nil
+}
@@ -0,0 +1,245 @@
+package ramcache
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var policyNames = []string{PolicyLRU, PolicyLFU, PolicyARC, PolicyTinyLFU}
+
+func mustNewPolicy(t testing.TB, name string, capacity int) EvictionPolicy {
+	t.Helper()
+	p, err := newPolicy(name, capacity)
+	require.NoError(t, err)
+	return p
+}
+
+func TestNewPolicy(t *testing.T) {
+	for _, name := range append([]string{""}, policyNames...) {
+		p, err := newPolicy(name, 10)
+		require.NoError(t, err, name)
+		assert.NotNil(t, p, name)
+	}
+	_, err := newPolicy("fifo", 10)
+	require.Error(t, err)
+}
+
+// drain returns the victims of p until it tracks no keys.
+func drain(p EvictionPolicy) []string {
+	var keys []string
+	for {
+		key, ok := p.Victim()
+		if !ok {
+			return keys
+		}
+		keys = append(keys, key)
+	}
+}
+
+func TestEvictionPolicy_Contract(t *testing.T) {
+	for _, name := range policyNames {
+		t.Run(name, func(t *testing.T) {
+			p := mustNewPolicy(t, name, 10)
+			for i := range 10 {
+				p.Add(strconv.Itoa(i))
+			}
+			p.Access("3")
+			p.Access("not tracked")
+			p.Remove("5")
+			p.Remove("not tracked")
+
+			victims := drain(p)
+			sort.Strings(victims)
+			assert.Equal(t, []string{"0", "1", "2", "3", "4", "6", "7", "8", "9"}, victims,
+				"expected every tracked key to be evicted exactly once")
+
+			p.Add("a")
+			p.Reset()
+			assert.Empty(t, drain(p), "expected Reset to stop tracking all keys")
+		})
+	}
+}
+
+func TestLRUPolicy(t *testing.T) {
+	p := newLRUPolicy()
+	p.Add("a")
+	p.Add("b")
+	p.Add("c")
+	p.Access("a")
+	assert.Equal(t, []string{"b", "c", "a"}, drain(p))
+}
+
+func TestLFUPolicy(t *testing.T) {
+	p := newLFUPolicy()
+	p.Add("a")
+	p.Add("b")
+	p.Add("c")
+	p.Access("a")
+	p.Access("a")
+	p.Access("c")
+	// b is the least frequently used; a and c were used equally often after c was
+	// first added, but c was used more recently than b.
+	assert.Equal(t, []string{"b", "c", "a"}, drain(p))
+}
+
+func TestARCPolicy_ScanResistance(t *testing.T) {
+	p := newARCPolicy(4)
+	for _, key := range []string{"a", "b"} {
+		p.Add(key)
+		p.Access(key)
+	}
+	// A scan of keys seen once only displaces other keys seen once.
+	var last string
+	for i := range 10 {
+		p.Add("scan" + strconv.Itoa(i))
+		if i >= 2 {
+			key, ok := p.Victim()
+			require.True(t, ok)
+			assert.NotContains(t, []string{"a", "b"}, key)
+			last = key
+		}
+	}
+	// A key recently evicted from t1 and seen again is promoted into t2.
+	require.Same(t, p.b1, p.nodes[last].list)
+	p.Add(last)
+	assert.Same(t, p.t2, p.nodes[last].list)
+}
+
+func TestCountMinSketch(t *testing.T) {
+	s := newCountMinSketch(64)
+	for range 5 {
+		s.Increment(1)
+	}
+	s.Increment(2)
+	assert.GreaterOrEqual(t, s.Estimate(1), uint8(5))
+	assert.GreaterOrEqual(t, s.Estimate(2), uint8(1))
+	assert.Less(t, s.Estimate(2), s.Estimate(1))
+
+	for range maxSketchCount * 2 {
+		s.Increment(3)
+	}
+	assert.Equal(t, uint8(maxSketchCount), s.Estimate(3), "expected counters to saturate")
+
+	s.Halve()
+	assert.Equal(t, uint8(maxSketchCount/2), s.Estimate(3))
+}
+
+func TestDoorkeeper(t *testing.T) {
+	d := newDoorkeeper(64)
+	assert.False(t, d.Contains(42))
+	assert.False(t, d.Add(42))
+	assert.True(t, d.Contains(42))
+	assert.True(t, d.Add(42))
+	d.Reset()
+	assert.False(t, d.Contains(42))
+}
+
+func TestTinyLFUPolicy_Admission(t *testing.T) {
+	p := newTinyLFUPolicy(100)
+	s := newBoundedStore(100, 0, p)
+	// Build up the frequency of the hot keys.
+	for range 5 {
+		for i := range 100 {
+			key := "hot" + strconv.Itoa(i)
+			if _, ok := s.Get(key); !ok {
+				s.Set(key, item{})
+			}
+		}
+	}
+	// A scan of keys seen once must not displace the hot keys.
+	for i := range 1000 {
+		s.Set("scan"+strconv.Itoa(i), item{})
+	}
+	hot := 0
+	for i := range 100 {
+		if _, ok := s.Get("hot" + strconv.Itoa(i)); ok {
+			hot++
+		}
+	}
+	// The protected segment holds 80% of the main space; LRU would keep none.
+	assert.GreaterOrEqual(t, hot, 75, "expected the hot keys to survive the scan")
+}
+
+// zipfTrace returns n keys drawn from a Zipf distribution over keySpace keys. If scanEvery
+// is positive, a scan of scanLen keys that are never repeated is interleaved every
+// scanEvery keys.
+func zipfTrace(seed int64, n, keySpace int, skew float64, scanEvery, scanLen int) []string {
+	r := rand.New(rand.NewSource(seed)) //nolint:gosec // a deterministic trace is wanted
+	z := rand.NewZipf(r, skew, 1, uint64(keySpace-1))
+	trace := make([]string, 0, n)
+	scanned := 0
+	for len(trace) < n {
+		trace = append(trace, strconv.FormatUint(z.Uint64(), 10))
+		if scanEvery > 0 && len(trace)%scanEvery == 0 {
+			for range scanLen {
+				trace = append(trace, "scan"+strconv.Itoa(scanned))
+				scanned++
+			}
+		}
+	}
+	return trace
+}
+
+// hitRatio replays the trace against a store of the given capacity, setting keys on a
+// miss, and returns the ratio of hits.
+func hitRatio(t testing.TB, policy string, capacity int, trace []string) float64 {
+	t.Helper()
+	s := newBoundedStore(capacity, 0, mustNewPolicy(t, policy, capacity))
+	hits := 0
+	for _, key := range trace {
+		if _, ok := s.Get(key); ok {
+			hits++
+			continue
+		}
+		s.Set(key, item{})
+	}
+	return float64(hits) / float64(len(trace))
+}
+
+func TestEvictionPolicy_HitRatio(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping hit ratio simulation in short mode")
+	}
+	tests := []struct {
+		name  string
+		trace []string
+		// better lists pairs of policies where the first must beat the second.
+		better [][2]string
+	}{
+		{
+			name:  "zipf",
+			trace: zipfTrace(1, 200_000, 100_000, 1.1, 0, 0),
+			better: [][2]string{
+				{PolicyLFU, PolicyLRU},
+				{PolicyARC, PolicyLRU},
+				{PolicyTinyLFU, PolicyLRU},
+			},
+		},
+		{
+			name:  "zipf with scans",
+			trace: zipfTrace(2, 200_000, 100_000, 1.1, 5_000, 2_000),
+			better: [][2]string{
+				{PolicyARC, PolicyLRU},
+				{PolicyTinyLFU, PolicyLRU},
+				{PolicyLFU, PolicyLRU},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ratios := make(map[string]float64, len(policyNames))
+			for _, name := range policyNames {
+				ratios[name] = hitRatio(t, name, 1000, tt.trace)
+				t.Logf("%-8s hit ratio %.4f", name, ratios[name])
+			}
+			for _, pair := range tt.better {
+				assert.Greater(t, ratios[pair[0]], ratios[pair[1]], "expected %s to beat %s", pair[0], pair[1])
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package ramcache
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+// sketchDepth is the number of rows of the count-min sketch.
+const sketchDepth = 4
+
+// maxSketchCount is the saturation value of the sketch counters.
+const maxSketchCount = 15
+
+// countMinSketch estimates key frequencies in a fixed amount of memory. Counters
+// saturate at 15.
+type countMinSketch struct {
+	counters []uint8 // counters holds sketchDepth rows of width counters.
+	mask     uint64  // mask is width-1; width is a power of two.
+}
+
+func newCountMinSketch(capacity int) *countMinSketch {
+	width := uint64(1) << bits.Len64(uint64(max(capacity, 16)-1))
+	return &countMinSketch{
+		counters: make([]uint8, sketchDepth*width),
+		mask:     width - 1,
+	}
+}
+
+// index returns the counter index of hash h in row i.
+func (s *countMinSketch) index(h uint64, i int) uint64 {
+	// Double hashing derives the row hashes from the two halves of h.
+	h1, h2 := h, (h>>32)|1
+	return uint64(i)*(s.mask+1) + ((h1 + uint64(i)*h2) & s.mask)
+}
+
+// Increment increments the counters of h.
+func (s *countMinSketch) Increment(h uint64) {
+	for i := range sketchDepth {
+		if idx := s.index(h, i); s.counters[idx] < maxSketchCount {
+			s.counters[idx]++
+		}
+	}
+}
+
+// Halve halves all counters, so that the estimates favor recent history.
+func (s *countMinSketch) Halve() {
+	for i := range s.counters {
+		s.counters[i] /= 2
+	}
+}
+
+// Estimate returns the estimated frequency of h.
+func (s *countMinSketch) Estimate(h uint64) uint8 {
+	est := uint8(maxSketchCount)
+	for i := range sketchDepth {
+		est = min(est, s.counters[s.index(h, i)])
+	}
+	return est
+}
+
+// doorkeeper is a Bloom filter recording keys seen since the last sketch reset, so that
+// keys seen only once do not take up counters in the sketch.
+type doorkeeper struct {
+	bits []uint64 // bits is the filter.
+	mask uint64   // mask is the number of bits minus one; a power of two.
+}
+
+// newDoorkeeper returns a filter sized for n keys.
+func newDoorkeeper(n int) *doorkeeper {
+	n = 1 << bits.Len64(uint64(max(n, 64)*8-1))
+	return &doorkeeper{bits: make([]uint64, n/64), mask: uint64(n) - 1}
+}
+
+// Add adds h to the filter and reports whether it was already present.
+func (d *doorkeeper) Add(h uint64) bool {
+	present := true
+	for i := range uint64(2) {
+		bit := (h >> (i * 32)) & d.mask
+		if d.bits[bit/64]&(1<<(bit%64)) == 0 {
+			present = false
+			d.bits[bit/64] |= 1 << (bit % 64)
+		}
+	}
+	return present
+}
+
+// Contains reports whether h is in the filter.
+func (d *doorkeeper) Contains(h uint64) bool {
+	for i := range uint64(2) {
+		bit := (h >> (i * 32)) & d.mask
+		if d.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Reset clears the filter.
+func (d *doorkeeper) Reset() {
+	clear(d.bits)
+}
+
+// tinyLFUPolicy is the W-TinyLFU policy of Einziger, Friedman and Manes.
+//
+// New keys enter a small LRU admission window. Keys leaving the window are candidates for
+// the main space, a segmented LRU of probation and protected keys. Once the cache is
+// full, a candidate is only admitted if its estimated frequency beats that of the main
+// space's victim. Key frequencies are tracked by a count-min sketch behind a doorkeeper,
+// so the history of keys that are no longer cached costs a few bits per key. Keys seen
+// once, such as those of a scan, rarely displace frequently used ones.
+type tinyLFUPolicy struct {
+	nodes      map[string]*node // nodes maps keys to their nodes.
+	window     *keyList         // window is the admission window.
+	probation  *keyList         // probation holds main keys not accessed since admission.
+	protected  *keyList         // protected holds main keys accessed since admission.
+	sketch     *countMinSketch  // sketch estimates key frequencies.
+	door       *doorkeeper      // door filters keys seen once.
+	seed       maphash.Seed     // seed is the hash seed.
+	additions  int              // additions is the number of keys recorded since the last reset.
+	sampleSize int              // sampleSize is the number of additions that triggers a reset.
+	capacity   int              // capacity is the expected number of keys; zero means unknown.
+}
+
+var _ EvictionPolicy = new(tinyLFUPolicy)
+
+func newTinyLFUPolicy(capacity int) *tinyLFUPolicy {
+	size := capacity
+	if size <= 0 {
+		size = defaultPolicyCapacity
+	}
+	sampleSize := 10 * size
+	return &tinyLFUPolicy{
+		nodes:      make(map[string]*node),
+		window:     newKeyList(),
+		probation:  newKeyList(),
+		protected:  newKeyList(),
+		sketch:     newCountMinSketch(size),
+		door:       newDoorkeeper(sampleSize),
+		seed:       maphash.MakeSeed(),
+		sampleSize: sampleSize,
+		capacity:   capacity,
+	}
+}
+
+// size returns the capacity the segments are sized against, or zero if it's unknown
+// because the cache was never full.
+func (p *tinyLFUPolicy) size() int {
+	return p.capacity
+}
+
+// windowSize returns the maximum size of the admission window: 1% of the capacity.
+func (p *tinyLFUPolicy) windowSize() int {
+	if p.size() == 0 {
+		return max(len(p.nodes)/100, 1)
+	}
+	return max(p.size()/100, 1)
+}
+
+// protectedSize returns the maximum size of the protected segment: 80% of the main space.
+func (p *tinyLFUPolicy) protectedSize() int {
+	if p.size() == 0 {
+		return max((len(p.nodes)-p.window.Len())*8/10, 1)
+	}
+	return max((p.size()-p.windowSize())*8/10, 1)
+}
+
+// record records an occurrence of key.
+func (p *tinyLFUPolicy) record(key string) {
+	h := maphash.String(p.seed, key)
+	if p.door.Add(h) {
+		p.sketch.Increment(h)
+	}
+	p.additions++
+	if p.additions >= p.sampleSize {
+		p.sketch.Halve()
+		p.door.Reset()
+		p.additions = 0
+	}
+}
+
+// estimate returns the estimated frequency of key.
+func (p *tinyLFUPolicy) estimate(key string) int {
+	h := maphash.String(p.seed, key)
+	est := int(p.sketch.Estimate(h))
+	if p.door.Contains(h) {
+		est++
+	}
+	return est
+}
+
+func (p *tinyLFUPolicy) Add(key string) {
+	p.record(key)
+	n := &node{key: key}
+	p.nodes[key] = n
+	p.window.PushFront(n)
+	// Candidates are admitted freely while the main space has room. Otherwise they stay
+	// at the back of the window until they compete with a victim.
+	mainSize := p.size() - p.windowSize()
+	for p.window.Len() > p.windowSize() && (p.size() == 0 || p.probation.Len()+p.protected.Len() < mainSize) {
+		c := p.window.Back()
+		p.window.Remove(c)
+		p.probation.PushFront(c)
+	}
+}
+
+func (p *tinyLFUPolicy) Access(key string) {
+	p.record(key)
+	n, ok := p.nodes[key]
+	if !ok {
+		return
+	}
+	switch n.list {
+	case p.window, p.protected:
+		n.list.MoveToFront(n)
+	case p.probation:
+		p.probation.Remove(n)
+		p.protected.PushFront(n)
+		for p.protected.Len() > p.protectedSize() {
+			d := p.protected.Back()
+			p.protected.Remove(d)
+			p.probation.PushFront(d)
+		}
+	}
+}
+
+func (p *tinyLFUPolicy) Remove(key string) {
+	if n, ok := p.nodes[key]; ok {
+		n.list.Remove(n)
+		delete(p.nodes, key)
+	}
+}
+
+func (p *tinyLFUPolicy) Victim() (string, bool) {
+	if len(p.nodes) == 0 {
+		return "", false
+	}
+	if p.capacity == 0 {
+		// The cache is full for the first time: learn its capacity.
+		p.capacity = max(len(p.nodes)-1, 1)
+	}
+	victim := p.probation.Back()
+	if victim == nil {
+		victim = p.protected.Back()
+	}
+	candidate := p.window.Back()
+	switch {
+	case victim == nil:
+		victim = candidate
+	case candidate != nil && p.window.Len() > p.windowSize():
+		// The candidate competes with the victim; the less frequently used one is evicted.
+		if p.estimate(candidate.key) > p.estimate(victim.key) {
+			p.window.Remove(candidate)
+			p.probation.PushFront(candidate)
+		} else {
+			victim = candidate
+		}
+	}
+	victim.list.Remove(victim)
+	delete(p.nodes, victim.key)
+	return victim.key, true
+}
+
+func (p *tinyLFUPolicy) Reset() {
+	p.nodes = make(map[string]*node)
+	p.window = newKeyList()
+	p.probation = newKeyList()
+	p.protected = newKeyList()
+}
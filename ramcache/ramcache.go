@@ -1,8 +1,8 @@
 /*
 Package ramcache implements the [driver.Cache] interface using an in-memory map.
 
-It's useful for testing, development, and caching small data sets. It's not recommended
-for production due to lack of data persistence across restarts.
+It's useful for testing, development, and as a bounded local cache (see Bounded Size below).
+Data is not persisted across restarts.
 
 # URL Format
 
@@ -60,11 +60,109 @@ You can create a RAM cache with [New]:
 	    // ... use c with the cache.Cache interface
 	}
 
-# Limitations
+# Bounded Size
 
-Please note that due to the limitations of the RAM Cache, pattern matching
-operations are not supported. This includes the [cache.Cache] Count and DelKeys methods, which will return a
-[cache.ErrPatternMatchingNotSupported] error if called.
+By default the cache grows without limit. Set [Options.MaxEntries] or [Options.MaxBytes]
+to bound it, for example to use it as an L1 cache in a long-running service:
+
+	ramcache://?maxentries=100000&maxbytes=64MiB
+
+When a Set would exceed either limit, items are evicted according to [Options.Policy]:
+
+  - lru (the default) evicts the least recently used item.
+  - lfu evicts the least frequently used item.
+  - arc is the Adaptive Replacement Cache, which balances recency and frequency and
+    resists scans.
+  - tinylfu is W-TinyLFU, which only admits a new item if it's estimated to be used more
+    often than the item it would replace. It resists scans and usually has the best hit
+    ratio for skewed workloads, at the price of sometimes rejecting the item just set.
+
+For example:
+
+	ramcache://?maxentries=100000&policy=tinylfu
+
+Custom policies implement [EvictionPolicy] and are set with [Options.NewPolicy].
+
+The size of an item is the length of its key plus the length of its value; the overhead
+of the cache's own bookkeeping is not counted.
+
+# Sharding
+
+The cache is protected by a single lock by default. Under heavy concurrent use, set
+[Options.Shards] to split it into independently locked segments by key hash:
+
+	ramcache://?shards=16&maxentries=100000
+
+Each shard holds an equal share of MaxEntries and MaxBytes and evicts by its own policy,
+so eviction is approximate across the cache as a whole. Count, DelKeys, Clear and the
+periodic cleanup cover all shards.
+
+# Storage Engines
+
+Items are kept in Go maps by default. With millions of items, scanning them lengthens
+garbage collection. Set [Options.Storage] to [StorageArena] to pack keys and values into
+preallocated byte arenas instead, indexed without pointers:
+
+	ramcache://?storage=arena&maxbytes=1GiB&shards=16
+
+The arenas take up MaxBytes from the start. When they are full the oldest items are
+evicted first, so the eviction policy options don't apply.
+
+# Persistence
+
+Set [Options.SnapshotPath] to keep the cache warm across restarts:
+
+	ramcache://?snapshotpath=/var/lib/app/cache.snapshot&snapshotinterval=1m
+
+The cache is written to the file every [Options.SnapshotInterval] and when it's closed,
+each entry with its remaining TTL. When the cache is created, the snapshot is loaded and
+the entries that expired in the meantime are discarded. Snapshots use the portable dump
+format, so they can also be inspected and loaded with the gocache command-line tool.
+Set [Options.OnSnapshotError] to be told of periodic snapshots that fail to be written.
+
+# Object Mode
+
+To cache Go values without marshaling them, use [NewObjectCache]. It has the same TTLs,
+periodic cleanup, eviction policies and sharding as the byte cache, but isn't a
+[driver.Cache]:
+
+	c := ramcache.NewObjectCache[string, *User](ctx, &ramcache.ObjectOptions[*User]{
+	    MaxEntries: 10000,
+	    Clone:      func(u *User) *User { c := *u; return &c },
+	})
+	err := c.SetWithTTL(ctx, "user:1", user, time.Minute)
+	u, err := c.Get(ctx, "user:1")
+
+Values are stored by reference, so callers share them. Set [ObjectOptions.Clone] to copy
+values on Get when callers may mutate them.
+
+# Watching Keys
+
+The RAM cache implements [driver.Watcher]. Events are emitted when keys are set,
+deleted, or expire (during the periodic cleanup or lazily when an expired key is accessed).
+Items evicted to stay within the size limits are reported as deletions.
+
+To release resources or update metrics when items leave the cache, set [Options.OnEvict].
+It's called with the value of every item that expires, is evicted, deleted or cleared,
+along with an [EvictionReason].
+
+# Scripts
+
+The RAM cache runs the Go implementation of the scripts run with
+[cache.GenericCache.RunScript], with the keys of the script locked for its duration, so
+that code using scripts can be tested without Redis. A list written by a script is stored
+as the value of its key, in an encoding of the RAM cache, which Get returns.
+
+# Pattern Matching
+
+The [cache.Cache] Count and DelKeys methods accept the same glob-style patterns as Redis:
+
+  - * matches any sequence of characters
+  - ? matches any single character
+  - [abc], [a-z] and [^abc] match a single character from (or not from) a set
+  - \ escapes the next character
+
+Expired items are ignored, so both methods behave as they do with the Redis drivers.
 */
 package ramcache
 
@@ -81,6 +179,7 @@ import (
 
 	cache "github.com/bartventer/gocache"
 	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/pkg/clock"
 	"github.com/bartventer/gocache/pkg/driver"
 	"github.com/bartventer/gocache/pkg/keymod"
 )
@@ -95,19 +194,33 @@ func init() { //nolint:gochecknoinits // This is the entry point of the package.
 
 var _ driver.Cache[string] = new(ramcache[string])
 var _ driver.Cache[keymod.Key] = new(ramcache[keymod.Key])
+var _ driver.Watcher[string] = new(ramcache[string])
+var _ driver.Watcher[keymod.Key] = new(ramcache[keymod.Key])
+var _ driver.Scanner[string] = new(ramcache[string])
+var _ driver.Scanner[keymod.Key] = new(ramcache[keymod.Key])
+var _ driver.ScriptRunner[string] = new(ramcache[string])
+var _ driver.ScriptRunner[keymod.Key] = new(ramcache[keymod.Key])
 
 // ramcache is an in-memory implementation of the cache.Cache interface.
 type ramcache[K driver.String] struct {
-	once   sync.Once     // once ensures that the cache is initialized only once.
-	store  *store        // store is the in-memory store.
-	opts   *Options      // options is the cache options.
-	stopCh chan struct{} // stopCh is the stop channel.
+	once       sync.Once       // once ensures that the cache is initialized only once.
+	closeOnce  sync.Once       // closeOnce ensures that the cache is closed only once.
+	store      storage[[]byte] // store is the in-memory store.
+	opts       *Options        // options is the cache options.
+	stopCh     chan struct{}   // stopCh is the stop channel.
+	watchers   *watchers[K]    // watchers are the active key watchers.
+	clock      clock.Clock     // clock tells the time for expiry.
+	initErr    error           // initErr is the error loading the snapshot, if any.
+	snapshotMu sync.Mutex      // snapshotMu serializes writing snapshots.
 }
 
 // New returns a new in-memory cache implementation.
+//
+// If [Options.SnapshotPath] names a snapshot that can't be loaded, the cache holds the
+// entries read before the error. Use [cache.OpenCache] to be told about the error.
 func New[K driver.String](ctx context.Context, opts *Options) *ramcache[K] {
 	r := &ramcache[K]{}
-	r.init(ctx, opts)
+	_ = r.init(ctx, opts)
 	return r
 }
 
@@ -117,31 +230,43 @@ func (r *ramcache[K]) OpenCacheURL(ctx context.Context, u *url.URL) (*cache.Gene
 	if err != nil {
 		return nil, gcerrors.NewWithScheme(Scheme, fmt.Errorf("failed to parse URL: %w", err))
 	}
-	r.init(ctx, &opts)
+	if err := r.init(ctx, &opts); err != nil {
+		return nil, gcerrors.NewWithScheme(Scheme, err)
+	}
 	return cache.NewCache(r), nil
 }
 
-func (r *ramcache[K]) init(_ context.Context, opts *Options) {
+// init initializes the cache once, and returns the error loading the snapshot, if any.
+func (r *ramcache[K]) init(_ context.Context, opts *Options) error {
 	r.once.Do(func() {
-		r.store = newStore()
 		if opts == nil {
 			opts = &Options{}
 		}
 		opts.revise()
 		r.opts = opts
+		r.clock = clock.OrReal(opts.Clock)
+		r.store = newStorage(opts)
+		r.watchers = newWatchers[K]()
 		r.stopCh = make(chan struct{})
-		go r.cleanupExpiredItems()
+		if opts.SnapshotPath != "" {
+			r.initErr = r.loadSnapshot()
+			if opts.SnapshotInterval > 0 {
+				go r.snapshotPeriodically(r.clock.NewTicker(opts.SnapshotInterval))
+			}
+		}
+		go r.cleanupExpiredItems(r.clock.NewTicker(opts.CleanupInterval))
 	})
+	return r.initErr
 }
 
-// cleanupExpiredItems periodically removes expired items from the store.
-func (r *ramcache[K]) cleanupExpiredItems() {
-	ticker := time.NewTicker(r.opts.CleanupInterval)
+// cleanupExpiredItems removes expired items from the store on every tick of ticker,
+// until the cache is closed.
+func (r *ramcache[K]) cleanupExpiredItems(ticker clock.Ticker) {
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-ticker.C():
 			r.removeExpiredItems()
 		case <-r.stopCh:
 			return
@@ -151,27 +276,33 @@ func (r *ramcache[K]) cleanupExpiredItems() {
 
 // removeExpiredItems removes expired items from the store.
 func (r *ramcache[K]) removeExpiredItems() {
-	keyItems := r.store.KeyItemsSortedByExpiry()
-	for _, ki := range keyItems {
-		if ki.Item.IsExpired() {
-			r.store.Delete(ki.Key)
-		} else {
-			// Items are sorted by expiry time, so we can break early
-			break
-		}
+	for _, ki := range r.store.DeleteExpiredItems(r.clock.Now()) {
+		r.evicted(ki.Key, ki.Item, ReasonExpired)
 	}
 }
 
 // Count implements cache.Cache.
+//
+// The pattern uses the Redis glob-style syntax. Expired items are not counted.
 func (r *ramcache[K]) Count(ctx context.Context, pattern K) (int64, error) {
-	return 0, gcerrors.NewWithScheme(Scheme, errors.Join(cache.ErrPatternMatchingNotSupported, fmt.Errorf("Count operation not supported")))
+	now := r.clock.Now()
+	var count int64
+	for _, ki := range r.store.KeyItemsMatching(string(pattern)) {
+		if !ki.Item.IsExpired(now) {
+			count++
+		}
+	}
+	return count, nil
 }
 
 // Exists implements cache.Cache.
 func (r *ramcache[K]) Exists(ctx context.Context, key K) (bool, error) {
+	now := r.clock.Now()
 	item, exists := r.store.Get(string(key))
-	if exists && item.IsExpired() {
-		r.store.Delete(string(key))
+	if exists && item.IsExpired(now) {
+		if item, deleted := r.store.DeleteExpired(string(key), now); deleted {
+			r.evicted(string(key), item, ReasonExpired)
+		}
 		exists = false
 	}
 	return exists, nil
@@ -179,30 +310,48 @@ func (r *ramcache[K]) Exists(ctx context.Context, key K) (bool, error) {
 
 // Del implements cache.Cache.
 func (r *ramcache[K]) Del(ctx context.Context, key K) error {
-	_, exists := r.store.Get(string(key))
+	item, exists := r.store.Delete(string(key))
 	if !exists {
 		return gcerrors.NewWithScheme(Scheme, errors.Join(cache.ErrKeyNotFound, fmt.Errorf("key %s not found", key)))
 	}
-	r.store.Delete(string(key))
+	r.evicted(string(key), item, ReasonDeleted)
 	return nil
 }
 
 // DelKeys implements cache.Cache.
+//
+// The pattern uses the Redis glob-style syntax. Matching expired items are removed too,
+// and reported to watchers as expired rather than deleted.
 func (r *ramcache[K]) DelKeys(ctx context.Context, pattern K) error {
-	return gcerrors.NewWithScheme(Scheme, errors.Join(cache.ErrPatternMatchingNotSupported, fmt.Errorf("pattern %s not supported", pattern)))
+	now := r.clock.Now()
+	for _, ki := range r.store.DeleteMatching(string(pattern)) {
+		if ki.Item.IsExpired(now) {
+			r.evicted(ki.Key, ki.Item, ReasonExpired)
+		} else {
+			r.evicted(ki.Key, ki.Item, ReasonDeleted)
+		}
+	}
+	return nil
 }
 
 // Clear implements cache.Cache.
 func (r *ramcache[K]) Clear(ctx context.Context) error {
-	r.store.Clear()
+	for _, ki := range r.store.Clear(r.opts.OnEvict != nil) {
+		r.evicted(ki.Key, ki.Item, ReasonCleared)
+	}
 	return nil
 }
 
 // Get implements cache.Cache.
 func (r *ramcache[K]) Get(ctx context.Context, key K) ([]byte, error) {
+	now := r.clock.Now()
 	item, exists := r.store.Get(string(key))
-	if !exists || item.IsExpired() {
-		r.store.Delete(string(key))
+	if !exists || item.IsExpired(now) {
+		if exists {
+			if item, deleted := r.store.DeleteExpired(string(key), now); deleted {
+				r.evicted(string(key), item, ReasonExpired)
+			}
+		}
 		return nil, gcerrors.NewWithScheme(Scheme, errors.Join(cache.ErrKeyNotFound, fmt.Errorf("key %s not found", key)))
 	}
 	return item.Value, nil
@@ -260,17 +409,71 @@ func (r *ramcache[K]) set(key K, value interface{}, expiry time.Duration) error
 
 	var expiryTime time.Time
 	if expiry != 0 {
-		expiryTime = time.Now().Add(expiry)
+		expiryTime = r.clock.Now().Add(expiry)
 	}
 
-	r.store.Set(string(key), item{Value: data, Expiry: expiryTime})
+	it := item{Value: data, Expiry: expiryTime}
+	if size, limit := itemSize(string(key), it), r.store.MaxItemBytes(); limit > 0 && size > limit {
+		return gcerrors.NewWithScheme(Scheme, fmt.Errorf("item %s of %d bytes exceeds the limit of %d bytes", key, size, limit))
+	}
+	evicted := r.store.Set(string(key), it)
+	r.watchers.Notify(driver.EventSet, string(key))
+	for _, ki := range evicted {
+		r.evicted(ki.Key, ki.Item, ReasonCapacity)
+	}
 	return nil
 }
 
+// Scan implements driver.Scanner.
+//
+// Entries are read from a snapshot of the matching keys taken when Scan is called.
+// Expired entries are skipped.
+func (r *ramcache[K]) Scan(ctx context.Context, pattern K, fn func(driver.Entry[K]) error) error {
+	now := r.clock.Now()
+	for _, ki := range r.store.KeyItemsMatching(string(pattern)) {
+		if err := ctx.Err(); err != nil {
+			return gcerrors.NewWithScheme(Scheme, err)
+		}
+		var ttl time.Duration
+		if !ki.Item.Expiry.IsZero() {
+			ttl = ki.Item.Expiry.Sub(now)
+			if ttl <= 0 {
+				continue
+			}
+		}
+		if err := fn(driver.Entry[K]{Key: K(ki.Key), Value: ki.Item.Value, TTL: ttl}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Watch implements driver.Watcher.
+//
+// Events are emitted when keys are set, deleted with Del, or removed after their TTL
+// elapsed, either by the periodic cleanup or lazily on access. Clear does not emit
+// events. Delivery never blocks the cache; events are dropped for watchers that do
+// not keep up.
+func (r *ramcache[K]) Watch(ctx context.Context, pattern K) (<-chan driver.Event[K], error) {
+	return r.watchers.Add(ctx, string(pattern)), nil
+}
+
 // Close implements cache.Cache.
+//
+// If [Options.SnapshotPath] is set, Close writes a final snapshot and returns the error
+// writing it, if any.
 func (r *ramcache[K]) Close() error {
-	close(r.stopCh)
-	return nil
+	var err error
+	r.closeOnce.Do(func() {
+		close(r.stopCh)
+		if r.opts.SnapshotPath != "" {
+			if serr := r.writeSnapshot(); serr != nil {
+				err = gcerrors.NewWithScheme(Scheme, serr)
+			}
+		}
+		r.watchers.Close()
+	})
+	return err
 }
 
 // Ping implements cache.Cache.
@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/pkg/clock/clocktest"
 	"github.com/bartventer/gocache/pkg/driver"
 	"github.com/bartventer/gocache/pkg/drivertest"
 	"github.com/stretchr/testify/assert"
@@ -36,7 +40,7 @@ func TestRamcacheCache_New(t *testing.T) {
 func Test_ramcache_removeExpiredItems(t *testing.T) {
 	ctx := context.Background()
 	r := &ramcache[string]{}
-	r.init(ctx, &Options{CleanupInterval: 5 * time.Minute})
+	require.NoError(t, r.init(ctx, &Options{CleanupInterval: 5 * time.Minute}))
 
 	tests := []struct {
 		name     string
@@ -81,6 +85,379 @@ func Test_ramcache_removeExpiredItems(t *testing.T) {
 	}
 }
 
+func Test_ramcache_WatchExpire(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := New[string](ctx, &Options{})
+
+	events, err := r.Watch(ctx, "watch:*")
+	require.NoError(t, err)
+
+	r.store.Set("watch:expired", item{Value: []byte("value"), Expiry: time.Now().Add(-time.Hour)})
+	r.store.Set("other:expired", item{Value: []byte("value"), Expiry: time.Now().Add(-time.Hour)})
+	r.removeExpiredItems()
+
+	select {
+	case ev := <-events:
+		assert.Equal(t, driver.Event[string]{Type: driver.EventExpire, Key: "watch:expired"}, ev)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for expire event")
+	}
+
+	require.NoError(t, r.Close())
+	_, ok := <-events
+	assert.False(t, ok, "expected events channel to be closed")
+}
+
+func Test_ramcache_Clock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clk := clocktest.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	r := New[string](ctx, &Options{Clock: clk, CleanupInterval: time.Minute})
+	defer r.Close()
+
+	require.NoError(t, r.SetWithTTL(ctx, "lazy", "value", 30*time.Second))
+	require.NoError(t, r.SetWithTTL(ctx, "cleanup", "value", 30*time.Second))
+	events, err := r.Watch(ctx, "cleanup")
+	require.NoError(t, err)
+	clk.Advance(29 * time.Second)
+	var ttl time.Duration
+	require.NoError(t, r.Scan(ctx, "lazy", func(e driver.Entry[string]) error {
+		ttl = e.TTL
+		return nil
+	}))
+	assert.Equal(t, time.Second, ttl)
+	_, err = r.Get(ctx, "lazy")
+	require.NoError(t, err)
+
+	clk.Advance(2 * time.Second)
+	_, err = r.Get(ctx, "lazy")
+	require.ErrorIs(t, err, cache.ErrKeyNotFound, "expected the key to expire lazily")
+	assert.Equal(t, 1, r.store.Len(), "expected the cleanup not to have run yet")
+
+	clk.Advance(29 * time.Second)
+	select {
+	case ev := <-events:
+		assert.Equal(t, driver.Event[string]{Type: driver.EventExpire, Key: "cleanup"}, ev)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the cleanup")
+	}
+	assert.Equal(t, 0, r.store.Len())
+}
+
+func Test_ramcache_Bounded(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := New[string](ctx, &Options{MaxEntries: 2, MaxBytes: 32})
+	defer r.Close()
+
+	events, err := r.Watch(ctx, "*")
+	require.NoError(t, err)
+
+	require.NoError(t, r.Set(ctx, "key1", "value1"))
+	require.NoError(t, r.Set(ctx, "key2", "value2"))
+	_, err = r.Get(ctx, "key1")
+	require.NoError(t, err)
+	require.NoError(t, r.Set(ctx, "key3", "value3"))
+
+	_, err = r.Get(ctx, "key2")
+	require.ErrorIs(t, err, cache.ErrKeyNotFound, "expected the least recently used key to be evicted")
+	for _, key := range []string{"key1", "key3"} {
+		exists, err := r.Exists(ctx, key)
+		require.NoError(t, err)
+		assert.True(t, exists, key)
+	}
+
+	want := []driver.Event[string]{
+		{Type: driver.EventSet, Key: "key1"},
+		{Type: driver.EventSet, Key: "key2"},
+		{Type: driver.EventSet, Key: "key3"},
+		{Type: driver.EventDelete, Key: "key2"},
+	}
+	for _, w := range want {
+		select {
+		case ev := <-events:
+			assert.Equal(t, w, ev)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %v", w)
+		}
+	}
+
+	err = r.Set(ctx, "big", strings.Repeat("x", 32))
+	require.Error(t, err, "expected an item larger than MaxBytes to be rejected")
+	exists, err := r.Exists(ctx, "big")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+// evictRecord is a call to Options.OnEvict.
+type evictRecord struct {
+	Key    string
+	Value  string
+	Reason EvictionReason
+}
+
+func Test_ramcache_OnEvict(t *testing.T) {
+	ctx := context.Background()
+	var (
+		mu      sync.Mutex
+		records []evictRecord
+		r       *ramcache[string]
+	)
+	r = New[string](ctx, &Options{
+		MaxEntries: 3,
+		OnEvict: func(key string, value []byte, reason EvictionReason) {
+			// Calling back into the cache must not deadlock.
+			_, _ = r.Exists(ctx, key)
+			mu.Lock()
+			records = append(records, evictRecord{key, string(value), reason})
+			mu.Unlock()
+		},
+	})
+	defer r.Close()
+	expire := func(key string) {
+		r.store.Set(key, item{Value: []byte(key), Expiry: time.Now().Add(-time.Minute)})
+	}
+	take := func() []evictRecord {
+		mu.Lock()
+		defer mu.Unlock()
+		got := records
+		records = nil
+		return got
+	}
+
+	require.NoError(t, r.Set(ctx, "a", "a"))
+	require.NoError(t, r.Set(ctx, "b", "b"))
+	require.NoError(t, r.Set(ctx, "c", "c"))
+	require.NoError(t, r.Set(ctx, "d", "d"))
+	assert.Equal(t, []evictRecord{{"a", "a", ReasonCapacity}}, take())
+
+	require.NoError(t, r.Del(ctx, "b"))
+	assert.Equal(t, []evictRecord{{"b", "b", ReasonDeleted}}, take())
+
+	expire("get")
+	_, err := r.Get(ctx, "get")
+	require.ErrorIs(t, err, cache.ErrKeyNotFound)
+	expire("exists")
+	exists, err := r.Exists(ctx, "exists")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	expire("cleanup")
+	r.removeExpiredItems()
+	assert.Equal(t, []evictRecord{
+		{"get", "get", ReasonExpired},
+		{"exists", "exists", ReasonExpired},
+		{"cleanup", "cleanup", ReasonExpired},
+	}, take())
+
+	expire("x:expired")
+	require.NoError(t, r.Set(ctx, "x:live", "x:live"))
+	assert.Equal(t, []evictRecord{{"c", "c", ReasonCapacity}}, take())
+	require.NoError(t, r.DelKeys(ctx, "x:*"))
+	assert.ElementsMatch(t, []evictRecord{
+		{"x:expired", "x:expired", ReasonExpired},
+		{"x:live", "x:live", ReasonDeleted},
+	}, take())
+
+	require.NoError(t, r.Set(ctx, "c", "c"))
+	require.NoError(t, r.Clear(ctx))
+	assert.ElementsMatch(t, []evictRecord{
+		{"c", "c", ReasonCleared},
+		{"d", "d", ReasonCleared},
+	}, take())
+}
+
+// fifoPolicy is a custom eviction policy that evicts keys in insertion order.
+type fifoPolicy struct {
+	keys []string
+}
+
+func (p *fifoPolicy) Add(key string) { p.keys = append(p.keys, key) }
+func (p *fifoPolicy) Access(string)  {}
+func (p *fifoPolicy) Remove(key string) {
+	for i, k := range p.keys {
+		if k == key {
+			p.keys = append(p.keys[:i], p.keys[i+1:]...)
+			return
+		}
+	}
+}
+func (p *fifoPolicy) Reset() { p.keys = nil }
+func (p *fifoPolicy) Victim() (string, bool) {
+	if len(p.keys) == 0 {
+		return "", false
+	}
+	key := p.keys[0]
+	p.keys = p.keys[1:]
+	return key, true
+}
+
+func Test_ramcache_NewPolicy(t *testing.T) {
+	ctx := context.Background()
+	var capacity int
+	r := New[string](ctx, &Options{
+		MaxEntries: 2,
+		NewPolicy: func(c int) EvictionPolicy {
+			capacity = c
+			return &fifoPolicy{}
+		},
+	})
+	defer r.Close()
+	assert.Equal(t, 2, capacity)
+
+	require.NoError(t, r.Set(ctx, "key1", "value1"))
+	require.NoError(t, r.Set(ctx, "key2", "value2"))
+	_, err := r.Get(ctx, "key1")
+	require.NoError(t, err)
+	require.NoError(t, r.Set(ctx, "key3", "value3"))
+
+	// Unlike LRU, FIFO evicts key1 even though it was read last.
+	_, err = r.Get(ctx, "key1")
+	require.ErrorIs(t, err, cache.ErrKeyNotFound)
+}
+
+func Test_ramcache_PatternMatching(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := New[string](ctx, &Options{})
+	defer r.Close()
+
+	for _, key := range []string{"user:1", "user:2", "user:10", "user:*", "order:1"} {
+		require.NoError(t, r.Set(ctx, key, "value"))
+	}
+	r.store.Set("user:expired", item{Value: []byte("value"), Expiry: time.Now().Add(-time.Hour)})
+
+	tests := []struct {
+		pattern string
+		want    int64
+	}{
+		{"*", 5},
+		{"user:*", 4},
+		{"user:?", 3},
+		{"user:[12]", 2},
+		{"user:[^1]", 2},
+		{`user:\*`, 1},
+		{"order:*", 1},
+		{"missing:*", 0},
+	}
+	for _, tt := range tests {
+		count, err := r.Count(ctx, tt.pattern)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, count, tt.pattern)
+	}
+
+	events, err := r.Watch(ctx, "*")
+	require.NoError(t, err)
+	require.NoError(t, r.DelKeys(ctx, "user:*"))
+	count, err := r.Count(ctx, "*")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	_, exists := r.store.Get("user:expired")
+	assert.False(t, exists, "expected matching expired items to be removed")
+
+	got := map[driver.EventType]int{}
+	for range 5 {
+		select {
+		case ev := <-events:
+			got[ev.Type]++
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for events")
+		}
+	}
+	assert.Equal(t, map[driver.EventType]int{driver.EventDelete: 4, driver.EventExpire: 1}, got)
+}
+
+func Test_ramcache_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+	clk := clocktest.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	r := New[string](ctx, &Options{Clock: clk, SnapshotPath: path})
+	require.NoError(t, r.Set(ctx, "persistent", "value"))
+	require.NoError(t, r.SetWithTTL(ctx, "long", "value", time.Hour))
+	require.NoError(t, r.SetWithTTL(ctx, "short", "value", time.Minute))
+	require.NoError(t, r.Close())
+	_, err := os.Stat(path)
+	require.NoError(t, err, "expected Close to write a snapshot")
+
+	clk.Advance(2 * time.Minute)
+	r = New[string](ctx, &Options{Clock: clk, SnapshotPath: path})
+	defer r.Close()
+	value, err := r.Get(ctx, "persistent")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+	var ttl time.Duration
+	require.NoError(t, r.Scan(ctx, "long", func(e driver.Entry[string]) error {
+		ttl = e.TTL
+		return nil
+	}))
+	assert.Equal(t, 58*time.Minute, ttl, "expected the remaining TTL to be kept")
+	exists, err := r.Exists(ctx, "short")
+	require.NoError(t, err)
+	assert.False(t, exists, "expected entries that expired since the snapshot to be discarded")
+}
+
+func Test_ramcache_SnapshotError(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "missing", "cache.snapshot")
+	clk := clocktest.NewFake(time.Now())
+	errs := make(chan error, 1)
+
+	r := New[string](ctx, &Options{
+		Clock:            clk,
+		SnapshotPath:     path,
+		SnapshotInterval: time.Minute,
+		OnSnapshotError:  func(err error) { errs <- err },
+	})
+	defer r.Close()
+	require.Eventually(t, func() bool { return clk.Tickers() == 2 }, time.Second, time.Millisecond)
+	clk.Advance(time.Minute)
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "failed to create snapshot")
+	case <-time.After(time.Second):
+		t.Fatal("expected the failed snapshot to be reported")
+	}
+}
+
+func Test_ramcache_SnapshotInterval(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+
+	clk := clocktest.NewFake(time.Now())
+	r := New[string](ctx, &Options{Clock: clk, SnapshotPath: path, SnapshotInterval: time.Minute})
+	defer r.Close()
+	require.NoError(t, r.Set(ctx, "key", "value"))
+	_, err := os.Stat(path)
+	require.ErrorIs(t, err, os.ErrNotExist, "expected no snapshot before the interval elapsed")
+	clk.Advance(time.Minute)
+	assert.Eventually(t, func() bool {
+		f, err := os.Open(path)
+		if err != nil {
+			return false
+		}
+		defer f.Close()
+		c := New[string](ctx, &Options{})
+		return cache.NewCache(c).Restore(ctx, f, nil) == nil && c.store.Len() == 1
+	}, time.Second, 10*time.Millisecond, "expected a periodic snapshot holding the key")
+
+	require.NoError(t, r.Close())
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.False(t, strings.Contains(e.Name(), ".tmp"), "expected no temporary files, got %s", e.Name())
+	}
+}
+
+func Test_ramcache_SnapshotInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+	require.NoError(t, os.WriteFile(path, []byte("not a snapshot"), 0o600))
+
+	r := &ramcache[string]{}
+	_, err := r.OpenCacheURL(context.Background(), &url.URL{Scheme: Scheme, RawQuery: url.Values{"snapshotpath": {path}}.Encode()})
+	require.ErrorIs(t, err, cache.ErrInvalidDump)
+}
+
 func TestSetWithTTL_InvalidExpiry(t *testing.T) {
 	ctx := context.Background()
 	r := New[string](ctx, &Options{})
@@ -211,14 +588,15 @@ func (s *Stringer) String() string {
 	return "stringer"
 }
 
-func setupCache[K driver.String](t *testing.T) *ramcache[K] {
+func setupCache[K driver.String](t *testing.T, opts *Options) *ramcache[K] {
 	t.Helper()
-	r := New[K](context.Background(), &Options{})
+	r := New[K](context.Background(), opts)
 	return r
 }
 
 type harness[K driver.String] struct {
 	cache *ramcache[K]
+	clock *clocktest.Fake
 }
 
 func (h *harness[K]) MakeCache(ctx context.Context) (driver.Cache[K], error) {
@@ -229,18 +607,36 @@ func (h *harness[K]) Close() {}
 
 func (h *harness[K]) Options() drivertest.Options {
 	return drivertest.Options{
-		PatternMatchingDisabled: true, // Ramcache does not support pattern matching
+		PatternMatchingDisabled: false,
 		CloseIsNoop:             true, // Cache can still be used after closing
+		WatchDisabled:           false,
+		ScanDisabled:            false,
+		Advance:                 h.clock.Advance,
 	}
 }
 
 func newHarness[K driver.String](ctx context.Context, t *testing.T) (drivertest.Harness[K], error) {
-	cache := setupCache[K](t)
+	clk := clocktest.NewFake(time.Now())
+	cache := setupCache[K](t, &Options{Clock: clk})
 	return &harness[K]{
 		cache: cache,
+		clock: clk,
 	}, nil
 }
 
 func TestConformance(t *testing.T) {
 	drivertest.RunConformanceTests(t, newHarness[string])
 }
+
+func newArenaHarness[K driver.String](ctx context.Context, t *testing.T) (drivertest.Harness[K], error) {
+	clk := clocktest.NewFake(time.Now())
+	cache := setupCache[K](t, &Options{Clock: clk, Storage: StorageArena, MaxBytes: 1 << 20})
+	return &harness[K]{
+		cache: cache,
+		clock: clk,
+	}, nil
+}
+
+func TestConformance_Arena(t *testing.T) {
+	drivertest.RunConformanceTests(t, newArenaHarness[string])
+}
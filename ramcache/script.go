@@ -0,0 +1,275 @@
+package ramcache
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/pkg/driver"
+)
+
+// RunScript implements driver.ScriptRunner.
+//
+// The Go implementation of the script is run with the shards holding keys locked, so
+// other operations on keys wait for it to return. Arguments are converted to strings
+// as Redis clients do.
+func (r *ramcache[K]) RunScript(ctx context.Context, script *driver.Script, keys []K, args ...interface{}) (interface{}, error) {
+	if script.Func == nil {
+		return nil, gcerrors.NewWithScheme(Scheme, errors.Join(cache.ErrNotSupported, fmt.Errorf("script %s has no Go implementation", script.Name)))
+	}
+	sargs, err := scriptArgs(args)
+	if err != nil {
+		return nil, gcerrors.NewWithScheme(Scheme, fmt.Errorf("error running script %s: %w", script.Name, err))
+	}
+	skeys := make([]string, len(keys))
+	declared := make(map[string]struct{}, len(keys))
+	for i, key := range keys {
+		skeys[i] = string(key)
+		declared[string(key)] = struct{}{}
+	}
+	s := &scriptStore[K]{r: r, now: r.clock.Now(), keys: declared}
+	var result interface{}
+	r.store.Atomically(skeys, func(tx storageTx[[]byte]) {
+		s.tx = tx
+		result, err = script.Func(s, skeys, sargs)
+	})
+	// Watchers and eviction callbacks are told once the keys are unlocked.
+	for _, fn := range s.after {
+		fn()
+	}
+	if err != nil {
+		return nil, gcerrors.NewWithScheme(Scheme, fmt.Errorf("error running script %s: %w", script.Name, err))
+	}
+	return result, nil
+}
+
+// scriptStore is the [driver.ScriptStore] given to a script run by a ramcache.
+type scriptStore[K driver.String] struct {
+	r     *ramcache[K]
+	tx    storageTx[[]byte]   // tx accesses the locked keys.
+	now   time.Time           // now is the time the script started, which it runs at.
+	keys  map[string]struct{} // keys are the keys the script was run with.
+	after []func()            // after are the notifications to send once the keys are unlocked.
+}
+
+var _ driver.ScriptStore = new(scriptStore[string])
+
+// check returns an error if the script was not run with key, as its shard may not be
+// locked.
+func (s *scriptStore[K]) check(key string) error {
+	if _, ok := s.keys[key]; !ok {
+		return fmt.Errorf("key %s was not declared", key)
+	}
+	return nil
+}
+
+// evicted queues the notifications for an item removed from the store.
+func (s *scriptStore[K]) evicted(key string, it item, reason EvictionReason) {
+	s.after = append(s.after, func() { s.r.evicted(key, it, reason) })
+}
+
+// get returns the item for key, removing it if it's expired.
+func (s *scriptStore[K]) get(key string) (item, bool, error) {
+	if err := s.check(key); err != nil {
+		return item{}, false, err
+	}
+	it, exists := s.tx.Get(key)
+	if exists && it.IsExpired(s.now) {
+		s.tx.Delete(key)
+		s.evicted(key, it, ReasonExpired)
+		return item{}, false, nil
+	}
+	return it, exists, nil
+}
+
+// set stores the item for key.
+func (s *scriptStore[K]) set(key string, it item) {
+	for _, ki := range s.tx.Set(key, it) {
+		s.evicted(ki.Key, ki.Item, ReasonCapacity)
+	}
+}
+
+func (s *scriptStore[K]) Get(key string) ([]byte, bool, error) {
+	it, exists, err := s.get(key)
+	return it.Value, exists, err
+}
+
+func (s *scriptStore[K]) Set(key string, value []byte, ttl time.Duration) error {
+	if err := s.check(key); err != nil {
+		return err
+	}
+	it := item{Value: value}
+	if ttl != 0 {
+		if err := cache.ValidateTTL(ttl); err != nil {
+			return fmt.Errorf("invalid expiry duration %q: %w", ttl, err)
+		}
+		it.Expiry = s.now.Add(ttl)
+	}
+	return s.write(key, it)
+}
+
+// write stores the item for key as written by the script, which watchers are told of.
+func (s *scriptStore[K]) write(key string, it item) error {
+	if size, limit := itemSize(key, it), s.r.store.MaxItemBytes(); limit > 0 && size > limit {
+		return fmt.Errorf("item %s of %d bytes exceeds the limit of %d bytes", key, size, limit)
+	}
+	s.set(key, it)
+	s.after = append(s.after, func() { s.r.watchers.Notify(driver.EventSet, key) })
+	return nil
+}
+
+func (s *scriptStore[K]) Del(key string) (bool, error) {
+	it, exists, err := s.get(key)
+	if err != nil || !exists {
+		return false, err
+	}
+	s.tx.Delete(key)
+	s.evicted(key, it, ReasonDeleted)
+	return true, nil
+}
+
+func (s *scriptStore[K]) TTL(key string) (time.Duration, bool, error) {
+	it, exists, err := s.get(key)
+	if err != nil || !exists || it.Expiry.IsZero() {
+		return 0, exists, err
+	}
+	return it.Expiry.Sub(s.now), true, nil
+}
+
+func (s *scriptStore[K]) Expire(key string, ttl time.Duration) (bool, error) {
+	it, exists, err := s.get(key)
+	if err != nil || !exists {
+		return false, err
+	}
+	if ttl <= 0 {
+		s.tx.Delete(key)
+		s.evicted(key, it, ReasonDeleted)
+		return true, nil
+	}
+	it.Expiry = s.now.Add(ttl)
+	s.set(key, it)
+	return true, nil
+}
+
+// list returns the item for key and the list it holds, which is empty if key does not
+// exist.
+func (s *scriptStore[K]) list(key string) (item, [][]byte, error) {
+	it, exists, err := s.get(key)
+	if err != nil || !exists {
+		return it, nil, err
+	}
+	list, err := decodeList(it.Value)
+	if err != nil {
+		return it, nil, fmt.Errorf("key %s: %w", key, err)
+	}
+	return it, list, nil
+}
+
+// LPush inserts the values at the head of the list of key. A ramcache stores a list as
+// the value of its key, in an encoding of its own, which Get returns. Like the Redis
+// LPUSH command, the time-to-live of an existing list is kept.
+func (s *scriptStore[K]) LPush(key string, values ...[]byte) (int64, error) {
+	if len(values) == 0 {
+		return 0, fmt.Errorf("no values to push to key %s", key)
+	}
+	it, list, err := s.list(key)
+	if err != nil {
+		return 0, err
+	}
+	pushed := make([][]byte, 0, len(values)+len(list))
+	for i := len(values) - 1; i >= 0; i-- {
+		pushed = append(pushed, values[i])
+	}
+	pushed = append(pushed, list...)
+	it.Value = encodeList(pushed)
+	return int64(len(pushed)), s.write(key, it)
+}
+
+func (s *scriptStore[K]) LRange(key string, start, stop int64) ([][]byte, error) {
+	_, list, err := s.list(key)
+	if err != nil {
+		return nil, err
+	}
+	lo, hi := listRange(int64(len(list)), start, stop)
+	return list[lo:hi], nil
+}
+
+func (s *scriptStore[K]) LTrim(key string, start, stop int64) error {
+	it, list, err := s.list(key)
+	if err != nil || list == nil {
+		return err
+	}
+	lo, hi := listRange(int64(len(list)), start, stop)
+	if lo == hi {
+		s.tx.Delete(key)
+		s.evicted(key, it, ReasonDeleted)
+		return nil
+	}
+	if lo == 0 && hi == int64(len(list)) {
+		return nil
+	}
+	it.Value = encodeList(list[lo:hi])
+	return s.write(key, it)
+}
+
+// scriptArgs converts the arguments of a script to strings, as the go-redis client does
+// for Redis.
+func scriptArgs(args []interface{}) ([]string, error) {
+	sargs := make([]string, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case nil:
+		case string:
+			sargs[i] = v
+		case []byte:
+			sargs[i] = string(v)
+		case int:
+			sargs[i] = strconv.FormatInt(int64(v), 10)
+		case int8:
+			sargs[i] = strconv.FormatInt(int64(v), 10)
+		case int16:
+			sargs[i] = strconv.FormatInt(int64(v), 10)
+		case int32:
+			sargs[i] = strconv.FormatInt(int64(v), 10)
+		case int64:
+			sargs[i] = strconv.FormatInt(v, 10)
+		case uint:
+			sargs[i] = strconv.FormatUint(uint64(v), 10)
+		case uint8:
+			sargs[i] = strconv.FormatUint(uint64(v), 10)
+		case uint16:
+			sargs[i] = strconv.FormatUint(uint64(v), 10)
+		case uint32:
+			sargs[i] = strconv.FormatUint(uint64(v), 10)
+		case uint64:
+			sargs[i] = strconv.FormatUint(v, 10)
+		case float32:
+			sargs[i] = strconv.FormatFloat(float64(v), 'f', -1, 64)
+		case float64:
+			sargs[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			sargs[i] = "0"
+			if v {
+				sargs[i] = "1"
+			}
+		case time.Time:
+			sargs[i] = v.Format(time.RFC3339Nano)
+		case time.Duration:
+			sargs[i] = strconv.FormatInt(v.Nanoseconds(), 10)
+		case encoding.BinaryMarshaler:
+			b, err := v.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			sargs[i] = string(b)
+		default:
+			return nil, fmt.Errorf("can't convert argument of type %T (implement encoding.BinaryMarshaler)", v)
+		}
+	}
+	return sargs, nil
+}
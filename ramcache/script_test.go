@@ -0,0 +1,180 @@
+package ramcache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/pkg/clock/clocktest"
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// incrScript increments the integer value of its key by its argument.
+var incrScript = &driver.Script{
+	Name: "test:incr",
+	Func: func(s driver.ScriptStore, keys []string, args []string) (interface{}, error) {
+		v, _, err := s.Get(keys[0])
+		if err != nil {
+			return nil, err
+		}
+		n, _ := strconv.ParseInt(string(v), 10, 64)
+		delta, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		n += delta
+		return n, s.Set(keys[0], []byte(strconv.FormatInt(n, 10)), 0)
+	},
+}
+
+func Test_ramcache_RunScript(t *testing.T) {
+	for _, storage := range []string{StorageMap, StorageArena} {
+		t.Run(storage, func(t *testing.T) {
+			ctx := context.Background()
+			clk := clocktest.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			r := New[string](ctx, &Options{Storage: storage, Shards: 4, Clock: clk})
+			defer r.Close()
+			c := cache.NewCache[string](r)
+
+			require.NoError(t, c.SetWithTTL(ctx, "lock", "owner1", time.Second))
+			got, err := c.RunScript(ctx, cache.ScriptGetEx, []string{"lock"}, time.Minute.Milliseconds())
+			require.NoError(t, err)
+			assert.Equal(t, "owner1", got)
+			clk.Advance(30 * time.Second)
+			_, err = c.Get(ctx, "lock")
+			require.NoError(t, err, "expected the TTL to be extended")
+
+			got, err = c.RunScript(ctx, cache.ScriptDelIfEqual, []string{"lock"}, "owner2")
+			require.NoError(t, err)
+			assert.Equal(t, int64(0), got)
+			got, err = c.RunScript(ctx, cache.ScriptDelIfEqual, []string{"lock"}, "owner1")
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), got)
+			_, err = c.Get(ctx, "lock")
+			require.ErrorIs(t, err, cache.ErrKeyNotFound)
+
+			// Expired keys are not seen by scripts.
+			require.NoError(t, c.SetWithTTL(ctx, "expired", "value", time.Second))
+			clk.Advance(2 * time.Second)
+			got, err = c.RunScript(ctx, cache.ScriptGetEx, []string{"expired"}, 1000)
+			require.NoError(t, err)
+			assert.Nil(t, got)
+
+			// Like PEXPIRE, a time-to-live that is not positive removes the key.
+			require.NoError(t, c.Set(ctx, "temp", "value"))
+			got, err = c.RunScript(ctx, cache.ScriptGetEx, []string{"temp"}, 0)
+			require.NoError(t, err)
+			assert.Equal(t, "value", got)
+			_, err = c.Get(ctx, "temp")
+			require.ErrorIs(t, err, cache.ErrKeyNotFound)
+		})
+	}
+}
+
+func Test_ramcache_RunScript_Atomic(t *testing.T) {
+	for _, storage := range []string{StorageMap, StorageArena} {
+		t.Run(storage, func(t *testing.T) {
+			ctx := context.Background()
+			r := New[string](ctx, &Options{Storage: storage, Shards: 4})
+			defer r.Close()
+			events, err := r.Watch(ctx, "counter")
+			require.NoError(t, err)
+
+			var wg sync.WaitGroup
+			for range 10 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range 100 {
+						_, err := r.RunScript(ctx, incrScript, []string{"counter", "other"}, 1)
+						assert.NoError(t, err)
+					}
+				}()
+			}
+			wg.Wait()
+			got, err := r.Get(ctx, "counter")
+			require.NoError(t, err)
+			assert.Equal(t, "1000", string(got))
+			ev := <-events
+			assert.Equal(t, driver.Event[string]{Type: driver.EventSet, Key: "counter"}, ev)
+		})
+	}
+}
+
+func Test_ramcache_RunScript_UndeclaredKey(t *testing.T) {
+	ctx := context.Background()
+	r := New[string](ctx, nil)
+	defer r.Close()
+	_, err := r.RunScript(ctx, incrScript, []string{"counter"}, 1)
+	require.NoError(t, err)
+	script := &driver.Script{
+		Name: "test:undeclared",
+		Func: func(s driver.ScriptStore, _ []string, _ []string) (interface{}, error) {
+			_, err := s.Del("counter")
+			return nil, err
+		},
+	}
+	_, err = r.RunScript(ctx, script, []string{"key"})
+	require.Error(t, err)
+	// The shards are unlocked after the error, and the key is left untouched.
+	require.NoError(t, r.Set(ctx, "key", "value"))
+	got, err := r.Get(ctx, "counter")
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(got))
+}
+
+func Test_ramcache_RunScript_NoFunc(t *testing.T) {
+	ctx := context.Background()
+	r := New[string](ctx, nil)
+	defer r.Close()
+	_, err := r.RunScript(ctx, &driver.Script{Name: "lua", Lua: "return 1"}, nil)
+	require.ErrorIs(t, err, cache.ErrNotSupported)
+}
+
+func Test_scriptArgs(t *testing.T) {
+	got, err := scriptArgs([]interface{}{nil, "s", []byte("b"), 42, int64(-1), uint8(7), 1.5, true, false, 2 * time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "s", "b", "42", "-1", "7", "1.5", "1", "0", "2000000"}, got)
+
+	_, err = scriptArgs([]interface{}{struct{}{}})
+	require.Error(t, err)
+}
+
+func Test_ramcache_RunScript_CappedPush(t *testing.T) {
+	for _, storage := range []string{StorageMap, StorageArena} {
+		t.Run(storage, func(t *testing.T) {
+			ctx := context.Background()
+			clk := clocktest.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			r := New[string](ctx, &Options{Storage: storage, Clock: clk})
+			defer r.Close()
+			c := cache.NewCache[string](r)
+
+			got, err := c.RunScript(ctx, cache.ScriptCappedPush, []string{"log"}, 3, "a", "b")
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), got)
+			_, err = c.RunScript(ctx, cache.ScriptGetEx, []string{"log"}, time.Minute.Milliseconds())
+			require.NoError(t, err)
+			got, err = c.RunScript(ctx, cache.ScriptCappedPush, []string{"log"}, 3, "c", "d")
+			require.NoError(t, err)
+			assert.Equal(t, int64(3), got)
+
+			v, err := r.Get(ctx, "log")
+			require.NoError(t, err)
+			list, err := decodeList(v)
+			require.NoError(t, err)
+			assert.Equal(t, [][]byte{[]byte("d"), []byte("c"), []byte("b")}, list)
+			clk.Advance(2 * time.Minute)
+			_, err = r.Get(ctx, "log")
+			require.ErrorIs(t, err, cache.ErrKeyNotFound, "expected the TTL of the list to be kept")
+
+			require.NoError(t, r.Set(ctx, "string", "value"))
+			_, err = c.RunScript(ctx, cache.ScriptCappedPush, []string{"string"}, 3, "a")
+			require.ErrorIs(t, err, errNotList)
+		})
+	}
+}
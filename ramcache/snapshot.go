@@ -0,0 +1,82 @@
+package ramcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/pkg/clock"
+)
+
+// loadSnapshot loads the snapshot at r.opts.SnapshotPath into the store, discarding
+// entries that expired since it was written. A missing snapshot is not an error.
+func (r *ramcache[K]) loadSnapshot() error {
+	f, err := os.Open(r.opts.SnapshotPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer f.Close()
+	err = cache.NewCache(r).Restore(context.Background(), f, &cache.RestoreOptions{Replace: true, AdjustTTL: true, Clock: r.clock})
+	if err != nil {
+		return fmt.Errorf("failed to load snapshot %s: %w", r.opts.SnapshotPath, err)
+	}
+	return nil
+}
+
+// writeSnapshot writes all items to r.opts.SnapshotPath. The snapshot is written to a
+// temporary file in the same directory and renamed into place, so that a crash never
+// leaves a partial snapshot behind.
+func (r *ramcache[K]) writeSnapshot() (err error) {
+	r.snapshotMu.Lock()
+	defer r.snapshotMu.Unlock()
+
+	path := r.opts.SnapshotPath
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	if err := cache.NewCache(r).DumpWithOptions(context.Background(), f, "*", &cache.DumpOptions{Clock: r.clock}); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// snapshotPeriodically writes a snapshot on every tick of ticker until the cache is
+// closed. A failed snapshot is reported to [Options.OnSnapshotError], and retried at the
+// next tick.
+func (r *ramcache[K]) snapshotPeriodically(ticker clock.Ticker) {
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			if err := r.writeSnapshot(); err != nil && r.opts.OnSnapshotError != nil {
+				r.opts.OnSnapshotError(err)
+			}
+		case <-r.stopCh:
+			return
+		}
+	}
+}
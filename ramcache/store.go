@@ -1,93 +1,583 @@
 package ramcache
 
 import (
+	"fmt"
+	"hash/maphash"
+	"math/bits"
 	"slices"
 	"sync"
 	"time"
+
+	"github.com/bartventer/gocache/internal/glob"
 )
 
-// item is a cache item.
-type item struct {
-	Value  []byte    // Value is the item value.
+// entry is a cache item holding a value of type V.
+type entry[V any] struct {
+	Value  V         // Value is the item value.
 	Expiry time.Time // Expiry is the item expiry time. Zero means no expiry.
 }
 
-// Compare compares the item with another item.
-func (i item) Compare(other item) int {
+// item is a cache item holding bytes.
+type item = entry[[]byte]
+
+// IsExpired returns true if the item is expired at time now.
+func (i entry[V]) IsExpired(now time.Time) bool {
 	if i.Expiry.IsZero() {
-		if other.Expiry.IsZero() {
-			return 0
+		return false
+	}
+	return now.After(i.Expiry)
+}
+
+// itemSize returns the number of bytes accounted for an item: the size of its key and value.
+func itemSize(key string, it item) int64 {
+	return int64(len(key)) + bytesSize(it.Value)
+}
+
+// bytesSize returns the size of a byte value.
+func bytesSize(v []byte) int64 {
+	return int64(len(v))
+}
+
+// shard is an independently locked segment of a store.
+//
+// If the shard is bounded, the keys chosen by the eviction policy are evicted when a Set
+// would exceed maxEntries or maxBytes.
+type shard[V any] struct {
+	mu         sync.RWMutex
+	items      map[string]entry[V]
+	policy     EvictionPolicy // policy chooses the keys to evict; nil if the store is unbounded.
+	expiries   *expiryIndex   // expiries tracks the items that have an expiry time.
+	bytes      int64          // bytes is the accounted size of all items.
+	maxEntries int            // maxEntries is the maximum number of items; zero means no limit.
+	maxBytes   int64          // maxBytes is the maximum accounted size; zero means no limit.
+	sizeOf     func(V) int64  // sizeOf returns the accounted size of a value; if nil, values count for nothing.
+}
+
+// newShard creates a new shard holding at most maxEntries items and maxBytes bytes,
+// evicting the keys chosen by policy. A zero limit means no limit; if both are zero,
+// policy is not used. A nil policy defaults to LRU. sizeOf returns the accounted size
+// of a value.
+func newShard[V any](maxEntries int, maxBytes int64, policy EvictionPolicy, sizeOf func(V) int64) *shard[V] {
+	s := &shard[V]{
+		items:      make(map[string]entry[V]),
+		sizeOf:     sizeOf,
+		expiries:   newExpiryIndex(),
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+	}
+	if maxEntries > 0 || maxBytes > 0 {
+		s.policy = policy
+		if s.policy == nil {
+			s.policy = newLRUPolicy()
 		}
-		return 1
 	}
-	if other.Expiry.IsZero() {
-		return -1
+	return s
+}
+
+// Get returns the item for key, recording the access with the eviction policy.
+func (s *shard[V]) Get(key string) (entry[V], bool) {
+	if s.policy == nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		it, exists := s.items[key]
+		return it, exists
 	}
-	return i.Expiry.Compare(other.Expiry)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.get(key)
 }
 
-// IsExpired returns true if the item is expired.
-func (i item) IsExpired() bool {
-	if i.Expiry.IsZero() {
-		return false
+// get returns the item for key, recording the access with the eviction policy, if any.
+// s.mu must be held for writing.
+func (s *shard[V]) get(key string) (entry[V], bool) {
+	it, exists := s.items[key]
+	if exists && s.policy != nil {
+		s.policy.Access(key)
 	}
-	return time.Now().After(i.Expiry)
+	return it, exists
 }
 
-// store is an in-memory store for cache items.
-type store struct {
-	mu    sync.RWMutex
-	items map[string]item
+// Set stores the item and returns the items evicted to stay within the limits, in the
+// order they were evicted. Depending on the policy, the item itself may be evicted.
+func (s *shard[V]) Set(key string, it entry[V]) []keyEntry[V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.set(key, it)
 }
 
-// newStore creates a new store.
-func newStore() *store {
-	return &store{
-		items: make(map[string]item),
+// set stores the item and returns the evicted items, like Set. s.mu must be held.
+func (s *shard[V]) set(key string, it entry[V]) []keyEntry[V] {
+	old, exists := s.items[key]
+	s.items[key] = it
+	s.bytes += s.size(key, it)
+	if exists {
+		s.bytes -= s.size(key, old)
+	}
+	s.expiries.Set(key, it.Expiry)
+	if s.policy == nil {
+		return nil
 	}
+	if exists {
+		s.policy.Access(key)
+	} else {
+		s.policy.Add(key)
+	}
+	return s.evict()
 }
 
-func (s *store) Get(key string) (item, bool) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	item, exists := s.items[key]
-	return item, exists
+// evict removes the keys chosen by the policy until the shard is within its limits.
+// s.mu must be held.
+func (s *shard[V]) evict() []keyEntry[V] {
+	var evicted []keyEntry[V]
+	for (s.maxEntries > 0 && len(s.items) > s.maxEntries) || (s.maxBytes > 0 && s.bytes > s.maxBytes) {
+		key, ok := s.policy.Victim()
+		if !ok {
+			break
+		}
+		it, exists := s.items[key]
+		if !exists {
+			continue
+		}
+		delete(s.items, key)
+		s.bytes -= s.size(key, it)
+		s.expiries.Remove(key)
+		evicted = append(evicted, keyEntry[V]{Key: key, Item: it})
+	}
+	return evicted
 }
 
-func (s *store) Set(key string, item item) {
+// Delete deletes the item for key and returns it, reporting whether it existed.
+func (s *shard[V]) Delete(key string) (entry[V], bool) {
 	s.mu.Lock()
-	s.items[key] = item
-	s.mu.Unlock()
+	defer s.mu.Unlock()
+	return s.delete(key)
 }
 
-func (s *store) Delete(key string) {
+// delete deletes the item for key and returns it, like Delete. s.mu must be held.
+func (s *shard[V]) delete(key string) (entry[V], bool) {
+	it, exists := s.items[key]
+	if exists {
+		s.remove(key, it)
+	}
+	return it, exists
+}
+
+// DeleteExpired deletes the item for key if it's expired at time now and returns it,
+// reporting whether it did. Unlike a Get followed by a Delete, it cannot delete an item
+// set in between.
+func (s *shard[V]) DeleteExpired(key string, now time.Time) (entry[V], bool) {
 	s.mu.Lock()
-	delete(s.items, key)
-	s.mu.Unlock()
+	defer s.mu.Unlock()
+	it, exists := s.items[key]
+	if !exists || !it.IsExpired(now) {
+		return entry[V]{}, false
+	}
+	s.remove(key, it)
+	return it, true
 }
 
-func (s *store) Clear() {
+// DeleteExpiredItems deletes up to limit items that expired before now and returns them.
+func (s *shard[V]) DeleteExpiredItems(now time.Time, limit int) []keyEntry[V] {
 	s.mu.Lock()
-	s.items = make(map[string]item)
-	s.mu.Unlock()
+	defer s.mu.Unlock()
+	var deleted []keyEntry[V]
+	for len(deleted) < limit {
+		key, ok := s.expiries.PopExpired(now)
+		if !ok {
+			break
+		}
+		it := s.items[key]
+		s.remove(key, it)
+		deleted = append(deleted, keyEntry[V]{Key: key, Item: it})
+	}
+	return deleted
 }
 
-// keyItem is a struct that contains a key and an item.
-type keyItem struct {
-	Key  string // Key is the item key.
-	Item item   // Item is the item.
+// remove removes the item for key. s.mu must be held.
+func (s *shard[V]) remove(key string, it entry[V]) {
+	delete(s.items, key)
+	s.bytes -= s.size(key, it)
+	s.expiries.Remove(key)
+	if s.policy != nil {
+		s.policy.Remove(key)
+	}
+}
+
+// clear removes all items and returns them. s.mu must be held.
+func (s *shard[V]) clear() map[string]entry[V] {
+	items := s.items
+	s.items = make(map[string]entry[V])
+	s.bytes = 0
+	s.expiries.Reset()
+	if s.policy != nil {
+		s.policy.Reset()
+	}
+	return items
 }
 
-// KeyItemsSortedByExpiry returns all key items sorted by expiry time (closest to expiry first).
-func (s *store) KeyItemsSortedByExpiry() []keyItem {
+// size returns the accounted size of an item: the size of its key and value.
+func (s *shard[V]) size(key string, it entry[V]) int64 {
+	if s.sizeOf == nil {
+		return int64(len(key))
+	}
+	return int64(len(key)) + s.sizeOf(it.Value)
+}
+
+// Len returns the number of items in the shard.
+func (s *shard[V]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.items)
+}
+
+// Bytes returns the accounted size of the items in the shard.
+func (s *shard[V]) Bytes() int64 {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	items := make([]keyItem, 0, len(s.items))
-	for key, item := range s.items {
-		items = append(items, keyItem{Key: key, Item: item})
+	return s.bytes
+}
+
+// keyEntry is a struct that contains a key and an item.
+type keyEntry[V any] struct {
+	Key  string   // Key is the item key.
+	Item entry[V] // Item is the item.
+}
+
+// keyItem is a struct that contains a key and an item holding bytes.
+type keyItem = keyEntry[[]byte]
+
+// appendMatching appends the key items whose key matches the glob-style pattern to items.
+func (s *shard[V]) appendMatching(items []keyEntry[V], pattern string) []keyEntry[V] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for key, it := range s.items {
+		if glob.Match(pattern, key) {
+			items = append(items, keyEntry[V]{Key: key, Item: it})
+		}
+	}
+	return items
+}
+
+// deleteMatching deletes the items whose key matches the glob-style pattern and appends
+// them to deleted.
+func (s *shard[V]) deleteMatching(deleted []keyEntry[V], pattern string) []keyEntry[V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, it := range s.items {
+		if glob.Match(pattern, key) {
+			s.remove(key, it)
+			deleted = append(deleted, keyEntry[V]{Key: key, Item: it})
+		}
+	}
+	return deleted
+}
+
+// storage is a storage engine for cache items holding values of type V.
+type storage[V any] interface {
+	// Get returns the item for key.
+	Get(key string) (entry[V], bool)
+
+	// Set stores the item and returns the items evicted to stay within the limits, in
+	// the order they were evicted.
+	Set(key string, it entry[V]) []keyEntry[V]
+
+	// Delete deletes the item for key and returns it, reporting whether it existed.
+	Delete(key string) (entry[V], bool)
+
+	// DeleteExpired deletes the item for key if it's expired at time now and returns it,
+	// reporting whether it did.
+	DeleteExpired(key string, now time.Time) (entry[V], bool)
+
+	// DeleteExpiredItems deletes all items that expired before now and returns them.
+	DeleteExpiredItems(now time.Time) []keyEntry[V]
+
+	// Clear removes all items, and returns them if collect is true.
+	Clear(collect bool) []keyEntry[V]
+
+	// Len returns the number of items.
+	Len() int
+
+	// Bytes returns the accounted size of the items.
+	Bytes() int64
+
+	// MaxItemBytes returns the size of the largest item that can be stored, or zero if
+	// the size is not limited.
+	MaxItemBytes() int64
+
+	// KeyItemsMatching returns all key items whose key matches the glob-style pattern.
+	KeyItemsMatching(pattern string) []keyEntry[V]
+
+	// DeleteMatching deletes all items whose key matches the glob-style pattern and
+	// returns them.
+	DeleteMatching(pattern string) []keyEntry[V]
+
+	// Atomically calls fn with the items of keys locked, so that no other operation
+	// interleaves with the ones fn makes through tx, which must only access keys.
+	Atomically(keys []string, fn func(tx storageTx[V]))
+}
+
+// storageTx gives access to the items locked by [storage.Atomically].
+type storageTx[V any] interface {
+	// Get returns the item for key.
+	Get(key string) (entry[V], bool)
+
+	// Set stores the item and returns the items evicted to stay within the limits, in
+	// the order they were evicted.
+	Set(key string, it entry[V]) []keyEntry[V]
+
+	// Delete deletes the item for key and returns it, reporting whether it existed.
+	Delete(key string) (entry[V], bool)
+}
+
+// lockOrder returns the distinct shard indexes of keys, in ascending order, which is the
+// order shards are locked in to avoid deadlocks. index returns the shard index of a key.
+func lockOrder(keys []string, index func(key string) uint64) []uint64 {
+	indexes := make([]uint64, 0, len(keys))
+	for _, key := range keys {
+		indexes = append(indexes, index(key))
+	}
+	slices.Sort(indexes)
+	return slices.Compact(indexes)
+}
+
+// Names of the storage engines, for [Options.Storage].
+const (
+	StorageMap   = "map"   // StorageMap stores items in a map.
+	StorageArena = "arena" // StorageArena packs items into preallocated arenas.
+)
+
+// newStorage returns the storage engine selected by opts, falling back to the map
+// store for unknown names.
+func newStorage(opts *Options) storage[[]byte] {
+	if opts.Storage == StorageArena {
+		return newArenaStore(opts.Shards, opts.MaxEntries, opts.MaxBytes)
 	}
-	slices.SortFunc(items, func(a, b keyItem) int {
-		return a.Item.Compare(b.Item)
+	return newShardedStore(opts.Shards, opts.MaxEntries, opts.MaxBytes, func(capacity int) EvictionPolicy {
+		policy, err := opts.policy(capacity)
+		if err != nil {
+			return newLRUPolicy()
+		}
+		return policy
 	})
+}
+
+// validateStorage returns an error if name is not the name of a storage engine.
+func validateStorage(name string) error {
+	switch name {
+	case "", StorageMap, StorageArena:
+		return nil
+	default:
+		return fmt.Errorf("unknown storage engine %q", name)
+	}
+}
+
+// shardCount returns the number of shards for a store asked for n shards holding at most
+// maxEntries items: n rounded up to a power of two, reduced if needed so that every
+// shard may hold at least one item.
+func shardCount(n, maxEntries int) int {
+	n = max(n, 1)
+	if maxEntries > 0 {
+		n = min(n, maxEntries)
+	}
+	n = 1 << bits.Len(uint(n-1))
+	if maxEntries > 0 && n > maxEntries {
+		n >>= 1
+	}
+	return n
+}
+
+// store is an in-memory store for cache items, and the default storage engine.
+//
+// Keys are spread by hash over a power of two number of shards, each with its own lock,
+// so that operations on different keys rarely contend. The size limits are divided
+// evenly between the shards, each evicting by its own policy.
+type store[V any] struct {
+	shards []*shard[V]  // shards are the segments of the store.
+	mask   uint64       // mask is len(shards)-1.
+	seed   maphash.Seed // seed is the hash seed used to pick a shard.
+}
+
+var _ storage[[]byte] = new(store[[]byte])
+
+// newStore creates a new unbounded store of bytes with a single shard.
+func newStore() *store[[]byte] {
+	return newShardedStore(1, 0, 0, nil)
+}
+
+// newBoundedStore creates a new store of bytes with a single shard holding at most maxEntries
+// items and maxBytes bytes, evicting the keys chosen by policy. A zero limit means no
+// limit; if both are zero, policy is not used. A nil policy defaults to LRU.
+func newBoundedStore(maxEntries int, maxBytes int64, policy EvictionPolicy) *store[[]byte] {
+	return newShardedStore(1, maxEntries, maxBytes, func(int) EvictionPolicy { return policy })
+}
+
+// newShardedStore creates a new store of bytes with n shards; see newStoreOf.
+func newShardedStore(n, maxEntries int, maxBytes int64, newPolicy func(capacity int) EvictionPolicy) *store[[]byte] {
+	return newStoreOf(n, maxEntries, maxBytes, newPolicy, bytesSize)
+}
+
+// newStoreOf creates a new store with n shards, rounded up to a power of two, that
+// together hold at most maxEntries items and maxBytes bytes, where sizeOf returns the
+// accounted size of a value. newPolicy returns the eviction policy of a shard given its
+// share of maxEntries; if nil, shards use LRU.
+//
+// The number of shards is reduced if needed so that every shard may hold at least one
+// item.
+func newStoreOf[V any](n, maxEntries int, maxBytes int64, newPolicy func(capacity int) EvictionPolicy, sizeOf func(V) int64) *store[V] {
+	n = shardCount(n, maxEntries)
+	s := &store[V]{
+		shards: make([]*shard[V], n),
+		mask:   uint64(n - 1),
+		seed:   maphash.MakeSeed(),
+	}
+	shardEntries := (maxEntries + n - 1) / n
+	shardBytes := (maxBytes + int64(n) - 1) / int64(n)
+	for i := range s.shards {
+		var policy EvictionPolicy
+		if newPolicy != nil && (maxEntries > 0 || maxBytes > 0) {
+			policy = newPolicy(shardEntries)
+		}
+		s.shards[i] = newShard(shardEntries, shardBytes, policy, sizeOf)
+	}
+	return s
+}
+
+// shard returns the shard holding key.
+func (s *store[V]) shard(key string) *shard[V] {
+	return s.shards[s.shardIndex(key)]
+}
+
+// shardIndex returns the index of the shard holding key.
+func (s *store[V]) shardIndex(key string) uint64 {
+	if s.mask == 0 {
+		return 0
+	}
+	return maphash.String(s.seed, key) & s.mask
+}
+
+// MaxItemBytes returns the size of the largest item the store can hold, or zero if
+// the size is not limited.
+func (s *store[V]) MaxItemBytes() int64 {
+	return s.shards[0].maxBytes
+}
+
+// Get returns the item for key, recording the access with the eviction policy.
+func (s *store[V]) Get(key string) (entry[V], bool) {
+	return s.shard(key).Get(key)
+}
+
+// Set stores the item and returns the items evicted to stay within the limits, in the
+// order they were evicted. Depending on the policy, the item itself may be evicted.
+func (s *store[V]) Set(key string, it entry[V]) []keyEntry[V] {
+	return s.shard(key).Set(key, it)
+}
+
+// Delete deletes the item for key and returns it, reporting whether it existed.
+func (s *store[V]) Delete(key string) (entry[V], bool) {
+	return s.shard(key).Delete(key)
+}
+
+// DeleteExpired deletes the item for key if it's expired at time now and returns it,
+// reporting whether it did.
+func (s *store[V]) DeleteExpired(key string, now time.Time) (entry[V], bool) {
+	return s.shard(key).DeleteExpired(key, now)
+}
+
+// Clear removes all items, and returns them if collect is true. All shards are locked
+// together, so that no item set concurrently survives in one shard while another is
+// cleared after it.
+func (s *store[V]) Clear(collect bool) []keyEntry[V] {
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+	}
+	var cleared []keyEntry[V]
+	for _, sh := range s.shards {
+		for key, it := range sh.clear() {
+			if collect {
+				cleared = append(cleared, keyEntry[V]{Key: key, Item: it})
+			}
+		}
+		sh.mu.Unlock()
+	}
+	return cleared
+}
+
+// Len returns the number of items in the store.
+func (s *store[V]) Len() int {
+	var n int
+	for _, sh := range s.shards {
+		n += sh.Len()
+	}
+	return n
+}
+
+// Bytes returns the accounted size of the items in the store.
+func (s *store[V]) Bytes() int64 {
+	var n int64
+	for _, sh := range s.shards {
+		n += sh.Bytes()
+	}
+	return n
+}
+
+// expiredBatchSize is the maximum number of expired items removed from a shard while
+// holding its lock, so that removing many expired items does not stall other operations.
+const expiredBatchSize = 1024
+
+// DeleteExpiredItems deletes all items that expired before now and returns them. The cost
+// is proportional to the number of expired items, not to the size of the store.
+func (s *store[V]) DeleteExpiredItems(now time.Time) []keyEntry[V] {
+	var deleted []keyEntry[V]
+	for _, sh := range s.shards {
+		for {
+			batch := sh.DeleteExpiredItems(now, expiredBatchSize)
+			deleted = append(deleted, batch...)
+			if len(batch) < expiredBatchSize {
+				break
+			}
+		}
+	}
+	return deleted
+}
+
+// KeyItemsMatching returns all key items whose key matches the glob-style pattern.
+func (s *store[V]) KeyItemsMatching(pattern string) []keyEntry[V] {
+	var items []keyEntry[V]
+	for _, sh := range s.shards {
+		items = sh.appendMatching(items, pattern)
+	}
 	return items
 }
+
+// DeleteMatching deletes all items whose key matches the glob-style pattern and returns them.
+func (s *store[V]) DeleteMatching(pattern string) []keyEntry[V] {
+	var deleted []keyEntry[V]
+	for _, sh := range s.shards {
+		deleted = sh.deleteMatching(deleted, pattern)
+	}
+	return deleted
+}
+
+// Atomically calls fn with the shards holding keys locked.
+func (s *store[V]) Atomically(keys []string, fn func(tx storageTx[V])) {
+	for _, i := range lockOrder(keys, s.shardIndex) {
+		s.shards[i].mu.Lock()
+		defer s.shards[i].mu.Unlock()
+	}
+	fn(storeTx[V]{s})
+}
+
+// storeTx is the [storageTx] of a store, whose shards are locked by Atomically.
+type storeTx[V any] struct{ s *store[V] }
+
+func (tx storeTx[V]) Get(key string) (entry[V], bool) {
+	return tx.s.shard(key).get(key)
+}
+
+func (tx storeTx[V]) Set(key string, it entry[V]) []keyEntry[V] {
+	return tx.s.shard(key).set(key, it)
+}
+
+func (tx storeTx[V]) Delete(key string) (entry[V], bool) {
+	return tx.s.shard(key).delete(key)
+}
@@ -1,6 +1,9 @@
 package ramcache
 
 import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -9,8 +12,8 @@ func TestIsExpired(t *testing.T) {
 	s := newStore()
 	s.Set("key1", item{Value: []byte("value1"), Expiry: time.Now().Add(-10 * time.Minute)})
 	item, exists := s.Get("key1")
-	if !exists || !item.IsExpired() {
-		t.Errorf("IsExpired failed. Expected true, got %v", item.IsExpired())
+	if !exists || !item.IsExpired(time.Now()) {
+		t.Errorf("IsExpired failed. Expected true, got %v", item.IsExpired(time.Now()))
 	}
 }
 
@@ -46,7 +49,7 @@ func TestClear(t *testing.T) {
 	s := newStore()
 	s.Set("key1", item{Value: []byte("value1"), Expiry: time.Now().Add(10 * time.Minute)})
 	s.Set("key2", item{Value: []byte("value2"), Expiry: time.Now().Add(20 * time.Minute)})
-	s.Clear()
+	s.Clear(false)
 	_, exists1 := s.Get("key1")
 	_, exists2 := s.Get("key2")
 	if exists1 || exists2 {
@@ -54,13 +57,254 @@ func TestClear(t *testing.T) {
 	}
 }
 
-func TestKeyItemsSortedByExpiry(t *testing.T) {
+func TestDeleteExpiredItems(t *testing.T) {
 	s := newStore()
-	s.Set("key1", item{Value: []byte("value1"), Expiry: time.Now().Add(20 * time.Minute)})
-	s.Set("key2", item{Value: []byte("value2"), Expiry: time.Now().Add(10 * time.Minute)})
-	s.Set("key3", item{Value: []byte("value3"), Expiry: time.Time{}})
-	items := s.KeyItemsSortedByExpiry()
-	if len(items) != 3 || items[0].Key != "key2" || items[1].Key != "key1" || items[2].Key != "key3" {
-		t.Errorf("KeyItemsSortedByExpiry failed. Expected [key2, key1, key3], got [%v, %v, %v]", items[0].Key, items[1].Key, items[2].Key)
+	now := time.Now()
+	s.Set("key1", item{Value: []byte("value1"), Expiry: now.Add(-10 * time.Minute)})
+	s.Set("key2", item{Value: []byte("value2"), Expiry: now.Add(-20 * time.Minute)})
+	s.Set("key3", item{Value: []byte("value3"), Expiry: now.Add(10 * time.Minute)})
+	s.Set("key4", item{Value: []byte("value4")})
+	// Overwriting an item replaces its expiry time.
+	s.Set("key5", item{Value: []byte("value5"), Expiry: now.Add(-time.Minute)})
+	s.Set("key5", item{Value: []byte("value5")})
+	s.Set("key6", item{Value: []byte("value6"), Expiry: now.Add(-time.Minute)})
+	s.Delete("key6")
+
+	items := s.DeleteExpiredItems(now)
+	if len(items) != 2 || items[0].Key != "key2" || items[1].Key != "key1" {
+		t.Fatalf("DeleteExpiredItems failed. Expected [key2, key1], got %v", items)
+	}
+	if s.Len() != 3 {
+		t.Errorf("DeleteExpiredItems failed. Expected 3 items, got %d", s.Len())
+	}
+	if items := s.DeleteExpiredItems(now.Add(time.Hour)); len(items) != 1 || items[0].Key != "key3" {
+		t.Errorf("DeleteExpiredItems failed. Expected [key3], got %v", items)
+	}
+	s.Clear(false)
+	if n := s.shards[0].expiries.Len(); n != 0 {
+		t.Errorf("Clear failed. Expected no tracked expiries, got %d", n)
+	}
+
+	// Expired items are removed in batches.
+	for i := range 3 * expiredBatchSize {
+		s.Set(strconv.Itoa(i), item{Expiry: now.Add(-time.Minute)})
+	}
+	if got := len(s.DeleteExpiredItems(now)); got != 3*expiredBatchSize {
+		t.Errorf("DeleteExpiredItems failed. Expected %d items, got %d", 3*expiredBatchSize, got)
+	}
+}
+
+func TestBoundedStore_MaxEntries(t *testing.T) {
+	s := newBoundedStore(2, 0, nil)
+	s.Set("key1", item{Value: []byte("value1")})
+	s.Set("key2", item{Value: []byte("value2")})
+	// Reading key1 makes key2 the least recently used.
+	if _, exists := s.Get("key1"); !exists {
+		t.Fatal("Get failed. Expected key1 to exist")
+	}
+	evicted := s.Set("key3", item{Value: []byte("value3")})
+	if len(evicted) != 1 || evicted[0].Key != "key2" {
+		t.Fatalf("Set failed. Expected key2 to be evicted, got %v", evicted)
+	}
+	if _, exists := s.Get("key2"); exists {
+		t.Error("Set failed. Expected key2 to be evicted")
+	}
+	if s.Len() != 2 {
+		t.Errorf("Len failed. Expected 2, got %d", s.Len())
+	}
+}
+
+func TestBoundedStore_MaxBytes(t *testing.T) {
+	// Each item accounts for 4 (key) + 6 (value) = 10 bytes.
+	s := newBoundedStore(0, 25, nil)
+	s.Set("key1", item{Value: []byte("value1")})
+	s.Set("key2", item{Value: []byte("value2")})
+	if s.Bytes() != 20 {
+		t.Fatalf("Bytes failed. Expected 20, got %d", s.Bytes())
+	}
+
+	// Overwriting accounts for the size difference.
+	if evicted := s.Set("key2", item{Value: []byte("v2")}); len(evicted) != 0 {
+		t.Fatalf("Set failed. Expected no evictions, got %v", evicted)
+	}
+	if s.Bytes() != 16 {
+		t.Fatalf("Bytes failed. Expected 16, got %d", s.Bytes())
+	}
+
+	evicted := s.Set("key3", item{Value: []byte("value3")})
+	if len(evicted) != 1 || evicted[0].Key != "key1" {
+		t.Fatalf("Set failed. Expected key1 to be evicted, got %v", evicted)
+	}
+	if s.Bytes() != 16 {
+		t.Errorf("Bytes failed. Expected 16, got %d", s.Bytes())
+	}
+
+	s.Delete("key2")
+	if s.Bytes() != 10 || s.Len() != 1 {
+		t.Errorf("Delete failed. Expected 10 bytes in 1 item, got %d bytes in %d items", s.Bytes(), s.Len())
+	}
+	s.Clear(false)
+	if s.Bytes() != 0 || s.Len() != 0 {
+		t.Errorf("Clear failed. Expected an empty store, got %d bytes in %d items", s.Bytes(), s.Len())
+	}
+}
+
+func TestStore_Unbounded(t *testing.T) {
+	s := newStore()
+	for i := range 1000 {
+		if evicted := s.Set(strconv.Itoa(i), item{Value: []byte("value")}); len(evicted) != 0 {
+			t.Fatalf("Set failed. Expected no evictions, got %v", evicted)
+		}
+	}
+	if s.Len() != 1000 {
+		t.Errorf("Len failed. Expected 1000, got %d", s.Len())
+	}
+}
+
+func TestDeleteExpired(t *testing.T) {
+	s := newStore()
+	s.Set("expired", item{Value: []byte("value"), Expiry: time.Now().Add(-time.Minute)})
+	s.Set("live", item{Value: []byte("value"), Expiry: time.Now().Add(time.Minute)})
+	if it, deleted := s.DeleteExpired("expired", time.Now()); !deleted || string(it.Value) != "value" {
+		t.Error("DeleteExpired failed. Expected the expired item to be deleted")
+	}
+	if _, deleted := s.DeleteExpired("live", time.Now()); deleted {
+		t.Error("DeleteExpired failed. Expected only expired items to be deleted")
+	}
+	if _, deleted := s.DeleteExpired("missing", time.Now()); deleted {
+		t.Error("DeleteExpired failed. Expected only expired items to be deleted")
+	}
+	if _, exists := s.Get("live"); !exists {
+		t.Error("DeleteExpired failed. Expected live to exist")
+	}
+}
+
+func TestShardedStore_ShardCount(t *testing.T) {
+	tests := []struct {
+		shards, maxEntries, want int
+	}{
+		{0, 0, 1},
+		{1, 0, 1},
+		{3, 0, 4},
+		{16, 0, 16},
+		{16, 10, 8},
+		{16, 1, 1},
+	}
+	for _, tt := range tests {
+		s := newShardedStore(tt.shards, tt.maxEntries, 0, nil)
+		if len(s.shards) != tt.want {
+			t.Errorf("newShardedStore(%d, %d) has %d shards, expected %d", tt.shards, tt.maxEntries, len(s.shards), tt.want)
+		}
+	}
+}
+
+func TestShardedStore(t *testing.T) {
+	s := newShardedStore(8, 0, 0, nil)
+	now := time.Now()
+	for i := range 1000 {
+		s.Set("user:"+strconv.Itoa(i), item{Value: []byte("v")})
+		s.Set("order:"+strconv.Itoa(i), item{Value: []byte("v"), Expiry: now.Add(time.Duration(i+1) * time.Minute)})
+	}
+	for _, sh := range s.shards {
+		if sh.Len() == 0 {
+			t.Fatal("Set failed. Expected the keys to be spread over all shards")
+		}
+	}
+	if s.Len() != 2000 {
+		t.Errorf("Len failed. Expected 2000, got %d", s.Len())
+	}
+	if got := len(s.KeyItemsMatching("user:*")); got != 1000 {
+		t.Errorf("KeyItemsMatching failed. Expected 1000, got %d", got)
+	}
+	if got := len(s.DeleteExpiredItems(now.Add(600*time.Minute + time.Second))); got != 600 {
+		t.Errorf("DeleteExpiredItems failed. Expected 600, got %d", got)
+	}
+	if got := len(s.DeleteMatching("order:*")); got != 400 {
+		t.Errorf("DeleteMatching failed. Expected 400, got %d", got)
+	}
+	if s.Len() != 1000 {
+		t.Errorf("DeleteMatching failed. Expected 1000 items, got %d", s.Len())
+	}
+	s.Clear(false)
+	if s.Len() != 0 || s.Bytes() != 0 {
+		t.Errorf("Clear failed. Expected an empty store, got %d bytes in %d items", s.Bytes(), s.Len())
+	}
+}
+
+func TestShardedStore_Bounded(t *testing.T) {
+	s := newShardedStore(4, 100, 0, nil)
+	for i := range 1000 {
+		s.Set(strconv.Itoa(i), item{Value: []byte("value")})
+	}
+	if s.Len() > 100 {
+		t.Errorf("Set failed. Expected at most 100 items, got %d", s.Len())
+	}
+	for _, sh := range s.shards {
+		if sh.maxEntries != 25 || sh.policy == nil {
+			t.Errorf("newShardedStore failed. Expected each shard to hold 25 items with its own policy, got %d", sh.maxEntries)
+		}
+	}
+}
+
+func BenchmarkStore_Parallel(b *testing.B) {
+	const keys = 1 << 16
+	keyNames := make([]string, keys)
+	for i := range keyNames {
+		keyNames[i] = "key" + strconv.Itoa(i)
+	}
+	for _, bounded := range []bool{false, true} {
+		for _, shards := range []int{1, 4, 16, 64} {
+			name := fmt.Sprintf("bounded=%t/shards=%d", bounded, shards)
+			b.Run(name, func(b *testing.B) {
+				maxEntries := 0
+				if bounded {
+					maxEntries = keys / 2
+				}
+				s := newShardedStore(shards, maxEntries, 0, nil)
+				for _, key := range keyNames {
+					s.Set(key, item{Value: []byte("value")})
+				}
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					// Goroutines start at different keys, so they don't move in lockstep.
+					i := rand.IntN(keys) //nolint:gosec // not security sensitive
+					for pb.Next() {
+						key := keyNames[(i*7919)%keys]
+						// 90% reads, 10% writes.
+						if i%10 == 0 {
+							s.Set(key, item{Value: []byte("value")})
+						} else {
+							s.Get(key)
+						}
+						i++
+					}
+				})
+			})
+		}
+	}
+}
+
+func BenchmarkStore_DeleteExpiredItems(b *testing.B) {
+	for _, size := range []int{1_000_000, 4_000_000} {
+		for _, expired := range []int{0, 1000} {
+			b.Run(fmt.Sprintf("entries=%d/expired=%d", size, expired), func(b *testing.B) {
+				s := newShardedStore(16, 0, 0, nil)
+				now := time.Now()
+				for i := range size {
+					s.Set("key"+strconv.Itoa(i), item{Value: []byte("value"), Expiry: now.Add(time.Hour)})
+				}
+				b.ResetTimer()
+				for range b.N {
+					b.StopTimer()
+					for i := range expired {
+						s.Set("expired"+strconv.Itoa(i), item{Value: []byte("value"), Expiry: now.Add(-time.Hour)})
+					}
+					b.StartTimer()
+					if got := len(s.DeleteExpiredItems(now)); got != expired {
+						b.Fatalf("expected %d expired items, got %d", expired, got)
+					}
+				}
+			})
+		}
 	}
 }
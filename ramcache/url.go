@@ -4,35 +4,50 @@ import (
 	"net/url"
 
 	"github.com/bartventer/gocache/internal/urlparser"
+	"github.com/mitchellh/mapstructure"
 )
 
 // paramKeyBlacklist is a list of keys that should not be set on the Options.
 var paramKeyBlacklist = map[string]struct{}{
-	// placeholder for future options
+	"newpolicy":       {},
+	"onevict":         {},
+	"onsnapshoterror": {},
+	"clock":           {},
 }
 
 // optionsFromURL parses a [url.URL] into [Options].
 //
 // The URL should have the following format:
 //
-//	ramcache://?defaultttl=5m
+//	ramcache://?cleanupinterval=5m
 //
-// All ramcache client options can be set as query parameters, except for the following:
-//   - DefaultTTL
+// All [Options] can be set as query parameters, except for NewPolicy, OnEvict,
+// OnSnapshotError and Clock.
+// Byte sizes may have a unit suffix.
 //
 // Example:
 //
-//	ramcache://?defaultttl=5m
+//	ramcache://?cleanupinterval=5m&maxentries=10000&maxbytes=64MiB&policy=tinylfu
 //
-// This will return a Options with the DefaultTTL set to 5 minutes.
+// This will return an Options with the CleanupInterval set to 5 minutes, MaxEntries set
+// to 10000, MaxBytes set to 64MiB and the W-TinyLFU eviction policy.
 func optionsFromURL(u *url.URL) (Options, error) {
 	var opts Options
 
 	// Parse the query parameters into a map
-	parser := urlparser.New()
+	parser := urlparser.New(
+		mapstructure.StringToTimeDurationHookFunc(),
+		urlparser.StringToByteSizeHookFunc(),
+	)
 	if err := parser.OptionsFromURL(u, &opts, paramKeyBlacklist); err != nil {
 		return Options{}, err
 	}
+	if _, err := newPolicy(opts.Policy, 0); err != nil {
+		return Options{}, err
+	}
+	if err := validateStorage(opts.Storage); err != nil {
+		return Options{}, err
+	}
 
 	return opts, nil
 }
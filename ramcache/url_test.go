@@ -42,6 +42,103 @@ func Test_optionsFromURL(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "parses size limits",
+			args: args{
+				u:              mustParseURL("ramcache://?maxentries=1000&maxbytes=64MiB"),
+				paramOverrides: map[string]string{},
+			},
+			want: Options{
+				MaxEntries: 1000,
+				MaxBytes:   64 << 20,
+			},
+			wantErr: false,
+		},
+		{
+			name: "parses size limits without units",
+			args: args{
+				u:              mustParseURL("ramcache://?maxbytes=4096"),
+				paramOverrides: map[string]string{},
+			},
+			want: Options{
+				MaxBytes: 4096,
+			},
+			wantErr: false,
+		},
+		{
+			name: "parses eviction policy",
+			args: args{
+				u:              mustParseURL("ramcache://?maxentries=1000&policy=tinylfu"),
+				paramOverrides: map[string]string{},
+			},
+			want: Options{
+				MaxEntries: 1000,
+				Policy:     PolicyTinyLFU,
+			},
+			wantErr: false,
+		},
+		{
+			name: "parses shards",
+			args: args{
+				u:              mustParseURL("ramcache://?shards=16"),
+				paramOverrides: map[string]string{},
+			},
+			want: Options{
+				Shards: 16,
+			},
+			wantErr: false,
+		},
+		{
+			name: "parses snapshot options",
+			args: args{
+				u:              mustParseURL("ramcache://?snapshotpath=/var/lib/cache.snapshot&snapshotinterval=30s"),
+				paramOverrides: map[string]string{},
+			},
+			want: Options{
+				SnapshotPath:     "/var/lib/cache.snapshot",
+				SnapshotInterval: 30 * time.Second,
+			},
+			wantErr: false,
+		},
+		{
+			name: "parses storage engine",
+			args: args{
+				u:              mustParseURL("ramcache://?storage=arena&maxbytes=1GiB"),
+				paramOverrides: map[string]string{},
+			},
+			want: Options{
+				Storage:  StorageArena,
+				MaxBytes: 1 << 30,
+			},
+			wantErr: false,
+		},
+		{
+			name: "returns error for unknown storage engine",
+			args: args{
+				u:              mustParseURL("ramcache://?storage=disk"),
+				paramOverrides: map[string]string{},
+			},
+			want:    Options{},
+			wantErr: true,
+		},
+		{
+			name: "returns error for unknown eviction policy",
+			args: args{
+				u:              mustParseURL("ramcache://?maxentries=1000&policy=fifo"),
+				paramOverrides: map[string]string{},
+			},
+			want:    Options{},
+			wantErr: true,
+		},
+		{
+			name: "returns error for invalid byte size",
+			args: args{
+				u:              mustParseURL("ramcache://?maxbytes=64XB"),
+				paramOverrides: map[string]string{},
+			},
+			want:    Options{},
+			wantErr: true,
+		},
 		{
 			name: "returns error for invalid parameters",
 			args: args{
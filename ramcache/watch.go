@@ -0,0 +1,80 @@
+package ramcache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bartventer/gocache/internal/glob"
+	"github.com/bartventer/gocache/pkg/driver"
+)
+
+// watcher is a subscription to events for keys matching a pattern.
+type watcher[K driver.String] struct {
+	pattern string               // pattern is the glob-style key pattern.
+	events  chan driver.Event[K] // events is the channel events are delivered on.
+	stop    func() bool          // stop unregisters the context callback.
+}
+
+// watchers tracks the active watchers of a cache.
+type watchers[K driver.String] struct {
+	mu   sync.RWMutex
+	subs map[*watcher[K]]struct{}
+}
+
+// newWatchers creates a new set of watchers.
+func newWatchers[K driver.String]() *watchers[K] {
+	return &watchers[K]{
+		subs: make(map[*watcher[K]]struct{}),
+	}
+}
+
+// Add registers a watcher for the pattern. The watcher is removed, and its channel
+// closed, when ctx is done.
+func (w *watchers[K]) Add(ctx context.Context, pattern string) <-chan driver.Event[K] {
+	sub := &watcher[K]{
+		pattern: pattern,
+		events:  make(chan driver.Event[K], driver.WatchBufferSize),
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subs[sub] = struct{}{}
+	sub.stop = context.AfterFunc(ctx, func() { w.remove(sub) })
+	return sub.events
+}
+
+// remove unregisters the watcher and closes its channel.
+func (w *watchers[K]) remove(sub *watcher[K]) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.subs[sub]; ok {
+		delete(w.subs, sub)
+		close(sub.events)
+	}
+}
+
+// Notify delivers an event to all watchers whose pattern matches the key.
+// Delivery never blocks; events are dropped for watchers whose buffer is full.
+func (w *watchers[K]) Notify(typ driver.EventType, key string) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for sub := range w.subs {
+		if !glob.Match(sub.pattern, key) {
+			continue
+		}
+		select {
+		case sub.events <- driver.Event[K]{Type: typ, Key: K(key)}:
+		default:
+		}
+	}
+}
+
+// Close removes all watchers and closes their channels.
+func (w *watchers[K]) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for sub := range w.subs {
+		sub.stop()
+		delete(w.subs, sub)
+		close(sub.events)
+	}
+}
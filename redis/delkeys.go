@@ -0,0 +1,27 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/internal/keyspace"
+	"github.com/bartventer/gocache/pkg/driver"
+)
+
+// DelKeysWithOptions implements [driver.KeysDeleter].
+//
+// The matching keys are deleted with UNLINK, which frees their memory in the background,
+// in batches of at most [Config.DelKeysBatchSize] keys as they are scanned, so that
+// neither the process nor the server holds all of them at once. Servers without UNLINK
+// fall back to DEL. With [driver.DelKeysOptions.MaxKeys], the keys are counted before
+// any is deleted.
+func (r *redisCache[K]) DelKeysWithOptions(ctx context.Context, pattern K, opts driver.DelKeysOptions) (int64, error) {
+	n, err := r.deleter.DeleteAll(ctx, keyspace.Single(r.client), string(pattern), opts)
+	if r.near != nil && !opts.DryRun {
+		r.near.InvalidateMatching(string(pattern))
+	}
+	if err != nil {
+		return n, gcerrors.NewWithScheme(Scheme, err)
+	}
+	return n, nil
+}
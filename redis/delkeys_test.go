@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setKeys sets n keys with the given prefix.
+func setKeys(t *testing.T, r *redisCache[string], prefix string, n int) {
+	t.Helper()
+	for i := range n {
+		require.NoError(t, r.Set(context.Background(), prefix+strconv.Itoa(i), "value"))
+	}
+}
+
+func TestDelKeysWithOptions(t *testing.T) {
+	ctx := context.Background()
+	r, server := openFakeCache(t, "delkeysbatchsize=10&countlimit=7", 0)
+	setKeys(t, r, "user:", 25)
+	setKeys(t, r, "session:", 5)
+
+	n, err := r.DelKeysWithOptions(ctx, "user:*", driver.DelKeysOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(25), n)
+	assert.Equal(t, 3, server.callCount("unlink"), "expected the keys to be deleted in batches")
+	assert.Zero(t, server.callCount("del"))
+
+	count, err := r.Count(ctx, "*")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+}
+
+func TestDelKeysWithOptions_DryRun(t *testing.T) {
+	ctx := context.Background()
+	r, server := openFakeCache(t, "", 0)
+	setKeys(t, r, "user:", 12)
+
+	n, err := r.DelKeysWithOptions(ctx, "user:*", driver.DelKeysOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), n)
+	assert.Zero(t, server.callCount("unlink"))
+
+	count, err := r.Count(ctx, "user:*")
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), count)
+}
+
+func TestDelKeysWithOptions_MaxKeys(t *testing.T) {
+	ctx := context.Background()
+	r, _ := openFakeCache(t, "delkeysbatchsize=4", 0)
+	setKeys(t, r, "user:", 10)
+
+	n, err := r.DelKeysWithOptions(ctx, "user:*", driver.DelKeysOptions{MaxKeys: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), n)
+
+	setKeys(t, r, "user:", 10)
+	n, err = r.DelKeysWithOptions(ctx, "user:*", driver.DelKeysOptions{MaxKeys: 6})
+	require.ErrorIs(t, err, cache.ErrMaxKeysExceeded)
+	assert.Equal(t, int64(0), n)
+	count, err := r.Count(ctx, "user:*")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), count)
+
+	n, err = r.DelKeysWithOptions(ctx, "user:*", driver.DelKeysOptions{MaxKeys: 2, DryRun: true})
+	require.ErrorIs(t, err, cache.ErrMaxKeysExceeded)
+	assert.Equal(t, int64(0), n)
+}
+
+func TestDelKeysWithOptions_Del(t *testing.T) {
+	ctx := context.Background()
+	r, server := openFakeCache(t, "delkeysbatchsize=5", 0)
+	server.setLegacy(true)
+	setKeys(t, r, "user:", 12)
+
+	require.NoError(t, r.DelKeys(ctx, "user:*"))
+	assert.Equal(t, 1, server.callCount("unlink"), "expected UNLINK to be tried once")
+	assert.Equal(t, 3, server.callCount("del"))
+	count, err := r.Count(ctx, "user:*")
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
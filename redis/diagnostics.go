@@ -0,0 +1,25 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bartventer/gocache/internal/redisinfo"
+	"github.com/bartventer/gocache/pkg/driver"
+)
+
+// Diagnostics implements driver.Diagnoser.
+//
+// The server is reported with the sections of the INFO command returned by default,
+// which include memory, stats and keyspace.
+func (r *redisCache[K]) Diagnostics(ctx context.Context) (*driver.Diagnostics, error) {
+	pool := redisinfo.PoolStats(r.client.PoolStats())
+	server := driver.ServerDiagnostics{Addr: r.client.Options().Addr, Pool: pool}
+	info, err := r.client.Info(ctx).Result()
+	if err != nil {
+		server.Err = fmt.Errorf("error getting server info: %w", err)
+	} else {
+		server.Info = redisinfo.Parse(info)
+	}
+	return &driver.Diagnostics{Pool: pool, Servers: []driver.ServerDiagnostics{server}}, nil
+}
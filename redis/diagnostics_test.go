@@ -0,0 +1,34 @@
+package redis
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnostics(t *testing.T) {
+	path, _ := listenFake(t)
+
+	ctx := context.Background()
+	r := &redisCache[string]{}
+	c, err := r.OpenCacheURL(ctx, &url.URL{Scheme: SchemeUnix, Path: path})
+	require.NoError(t, err)
+	defer c.Close()
+	require.NoError(t, c.Set(ctx, "a", "1"))
+	require.NoError(t, c.Set(ctx, "b", "2"))
+
+	d, err := c.Diagnostics(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, d.Pool)
+	assert.Positive(t, d.Pool.TotalConns)
+	assert.Positive(t, d.Pool.Hits)
+	require.Len(t, d.Servers, 1)
+	server := d.Servers[0]
+	require.NoError(t, server.Err)
+	assert.Equal(t, path, server.Addr)
+	assert.Equal(t, "7.2.0", server.Info["server"]["redis_version"])
+	assert.Equal(t, "keys=2,expires=0,avg_ttl=0", server.Info["keyspace"]["db0"])
+}
@@ -0,0 +1,235 @@
+package redis
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/bartventer/gocache/internal/glob"
+	"github.com/bartventer/gocache/pkg/clock"
+	"github.com/redis/go-redis/v9"
+)
+
+// nearEntry is a value held by the near cache.
+type nearEntry struct {
+	key    string    // key is the cache key.
+	value  []byte    // value is the cached value.
+	expiry time.Time // expiry is when the key expires on the server. Zero means no expiry.
+}
+
+// nearCache is a bounded local copy of the values read from Redis, kept consistent with
+// the server by client-side caching in its RESP3 mode: reads that miss the near cache are
+// made by a dedicated client whose connections have CLIENT TRACKING enabled, and the
+// server pushes invalidation messages on the connection that read the key.
+//
+// go-redis reads push messages as if they were replies, so each connection of the reading
+// client is wrapped in a [trackingConn], which applies the invalidation messages and
+// passes the replies on. If a connection is closed, the invalidations of the keys read on
+// it are lost, so the near cache is cleared.
+type nearCache struct {
+	reader   *redis.Client // reader is the tracking client.
+	prefixes []string      // prefixes are the tracked key prefixes in broadcasting mode.
+	size     int           // size is the maximum number of entries.
+	clock    clock.Clock   // clock tells the time entries expire against.
+
+	mu      sync.Mutex               // mu guards the fields below.
+	entries map[string]*list.Element // entries maps keys to elements of lru.
+	lru     *list.List               // lru holds the entries, most recently used first.
+	pending map[string]uint64        // pending maps keys being read to their read token.
+	token   uint64                   // token is the last read token handed out.
+}
+
+// newNearCache creates a near cache of at most size entries for the server of opts,
+// whose entries expire against clk. With prefixes, tracking uses broadcasting mode and
+// only keys starting with one of them are cached.
+func newNearCache(opts *redis.Options, size int, prefixes []string, clk clock.Clock) *nearCache {
+	nc := &nearCache{
+		prefixes: prefixes,
+		size:     size,
+		clock:    clk,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		pending:  make(map[string]uint64),
+	}
+	readerOpts := *opts
+	dial := opts.Dialer
+	if dial == nil {
+		dial = redis.NewDialer(&readerOpts)
+	}
+	readerOpts.Protocol = 3
+	readerOpts.Dialer = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return newTrackingConn(conn, nc), nil
+	}
+	readerOpts.OnConnect = func(ctx context.Context, cn *redis.Conn) error {
+		if opts.OnConnect != nil {
+			if err := opts.OnConnect(ctx, cn); err != nil {
+				return err
+			}
+		}
+		cmd := redis.NewStatusCmd(ctx, trackingArgs(prefixes)...)
+		_ = cn.Process(ctx, cmd)
+		return cmd.Err()
+	}
+	nc.reader = redis.NewClient(&readerOpts)
+	return nc
+}
+
+// trackingArgs returns the CLIENT TRACKING command enabling tracking on a connection, in
+// broadcasting mode if prefixes are given.
+func trackingArgs(prefixes []string) []interface{} {
+	args := []interface{}{"client", "tracking", "on"}
+	if len(prefixes) > 0 {
+		args = append(args, "bcast")
+		for _, p := range prefixes {
+			args = append(args, "prefix", p)
+		}
+	}
+	return args
+}
+
+// invalidatePush applies an invalidation message pushed by the server. The message has a
+// null list of keys when the database is flushed, which clears the near cache.
+func (nc *nearCache) invalidatePush(msg []interface{}) {
+	if len(msg) != 2 || msg[0] != "invalidate" {
+		return
+	}
+	keys, ok := msg[1].([]interface{})
+	if !ok {
+		nc.clear()
+		return
+	}
+	for _, key := range keys {
+		if key, ok := key.(string); ok {
+			nc.Invalidate(key)
+		}
+	}
+}
+
+// cacheable reports whether values of key may be cached.
+func (nc *nearCache) cacheable(key string) bool {
+	if len(nc.prefixes) == 0 {
+		return true
+	}
+	for _, p := range nc.prefixes {
+		if len(key) >= len(p) && key[:len(p)] == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the value of key from the near cache, or reads it with get and caches it.
+// get is called with the tracking client, or nil if key is not cacheable.
+func (nc *nearCache) Get(ctx context.Context, key string, get func(ctx context.Context, c *redis.Client, key string) ([]byte, time.Duration, error)) ([]byte, error) {
+	if !nc.cacheable(key) {
+		value, _, err := get(ctx, nil, key)
+		return value, err
+	}
+	nc.mu.Lock()
+	if e, ok := nc.entries[key]; ok {
+		entry := e.Value.(*nearEntry) //nolint:errcheck // The list only holds *nearEntry.
+		if entry.expiry.IsZero() || nc.clock.Now().Before(entry.expiry) {
+			nc.lru.MoveToFront(e)
+			nc.mu.Unlock()
+			// The caller may modify the value, so it gets its own copy.
+			return bytes.Clone(entry.value), nil
+		}
+		nc.removeLocked(e)
+	}
+	nc.token++
+	token := nc.token
+	nc.pending[key] = token
+	nc.mu.Unlock()
+
+	value, ttl, err := get(ctx, nc.reader, key)
+
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	if nc.pending[key] != token {
+		// The key was invalidated while it was read.
+		return value, err
+	}
+	delete(nc.pending, key)
+	if err != nil {
+		return value, err
+	}
+	if e, ok := nc.entries[key]; ok {
+		nc.removeLocked(e)
+	}
+	entry := &nearEntry{key: key, value: bytes.Clone(value)}
+	if ttl > 0 {
+		entry.expiry = nc.clock.Now().Add(ttl)
+	}
+	nc.entries[key] = nc.lru.PushFront(entry)
+	for nc.lru.Len() > nc.size {
+		nc.removeLocked(nc.lru.Back())
+	}
+	return value, err
+}
+
+// Invalidate removes key from the near cache.
+func (nc *nearCache) Invalidate(key string) {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	delete(nc.pending, key)
+	if e, ok := nc.entries[key]; ok {
+		nc.removeLocked(e)
+	}
+}
+
+// InvalidateMatching removes the keys matching the glob-style pattern from the near cache.
+func (nc *nearCache) InvalidateMatching(pattern string) {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	for key := range nc.pending {
+		if glob.Match(pattern, key) {
+			delete(nc.pending, key)
+		}
+	}
+	for key, e := range nc.entries {
+		if glob.Match(pattern, key) {
+			nc.removeLocked(e)
+		}
+	}
+}
+
+// Len returns the number of entries in the near cache.
+func (nc *nearCache) Len() int {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	return nc.lru.Len()
+}
+
+// clear removes all entries from the near cache.
+func (nc *nearCache) clear() {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	nc.clearLocked()
+}
+
+// clearLocked removes all entries from the near cache. nc.mu must be held.
+func (nc *nearCache) clearLocked() {
+	clear(nc.entries)
+	clear(nc.pending)
+	nc.lru.Init()
+}
+
+// removeLocked removes the entry of e from the near cache. nc.mu must be held.
+func (nc *nearCache) removeLocked(e *list.Element) {
+	entry := nc.lru.Remove(e).(*nearEntry) //nolint:errcheck // The list only holds *nearEntry.
+	delete(nc.entries, entry.key)
+}
+
+// Close closes the tracking client and clears the near cache.
+func (nc *nearCache) Close() error {
+	err := nc.reader.Close()
+	nc.clear()
+	return err
+}
@@ -0,0 +1,148 @@
+package redis
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/bartventer/gocache/pkg/clock/clocktest"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupNearCache creates a Redis cache with the near cache enabled and the given query
+// parameters, connected to a fake server. It also returns a client of the same server that
+// bypasses the near cache.
+func setupNearCache(t *testing.T, query string) (*redisCache[string], *fakeServer, *redis.Client) {
+	t.Helper()
+	path, server := listenFake(t)
+
+	r := &redisCache[string]{}
+	_, err := r.OpenCacheURL(context.Background(), &url.URL{Scheme: SchemeUnix, Path: path, RawQuery: "nearcache=true&" + query})
+	require.NoError(t, err)
+	t.Cleanup(func() { r.Close() })
+
+	other := redis.NewClient(&redis.Options{Network: "unix", Addr: path})
+	t.Cleanup(func() { other.Close() })
+	return r, server, other
+}
+
+// requireValue requires the value of key read through r to be want.
+func requireValue(t *testing.T, r *redisCache[string], key, want string) {
+	t.Helper()
+	got, err := r.Get(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, want, string(got))
+}
+
+func TestNearCache(t *testing.T) {
+	ctx := context.Background()
+	r, _, other := setupNearCache(t, "")
+
+	require.NoError(t, r.Set(ctx, "key", "v1"))
+	requireValue(t, r, "key", "v1")
+	assert.Equal(t, 1, r.near.Len())
+	requireValue(t, r, "key", "v1")
+
+	// A write by another client invalidates the near cache.
+	require.NoError(t, other.Set(ctx, "key", "v2", 0).Err())
+	require.Eventually(t, func() bool { return r.near.Len() == 0 }, time.Second, time.Millisecond)
+	requireValue(t, r, "key", "v2")
+
+	// Writes through the cache invalidate it immediately.
+	require.NoError(t, r.Set(ctx, "key", "v3"))
+	requireValue(t, r, "key", "v3")
+	require.NoError(t, r.Del(ctx, "key"))
+	_, err := r.Get(ctx, "key")
+	require.ErrorIs(t, err, redis.Nil)
+
+	// Flushing the database clears the near cache.
+	require.NoError(t, r.Set(ctx, "key", "v4"))
+	requireValue(t, r, "key", "v4")
+	require.NoError(t, other.FlushDB(ctx).Err())
+	require.Eventually(t, func() bool { return r.near.Len() == 0 }, time.Second, time.Millisecond)
+}
+
+func TestNearCache_Copy(t *testing.T) {
+	ctx := context.Background()
+	r, _, _ := setupNearCache(t, "")
+
+	require.NoError(t, r.Set(ctx, "key", "value"))
+	got, err := r.Get(ctx, "key")
+	require.NoError(t, err)
+	got[0] = 'X'
+	got, err = r.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Equal(t, 1, r.near.Len())
+	got[0] = 'Y'
+	requireValue(t, r, "key", "value")
+}
+
+func TestNearCache_Size(t *testing.T) {
+	ctx := context.Background()
+	r, _, _ := setupNearCache(t, "nearcachesize=2")
+
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, r.Set(ctx, key, key))
+		requireValue(t, r, key, key)
+	}
+	assert.Equal(t, 2, r.near.Len())
+}
+
+func TestNearCache_Broadcast(t *testing.T) {
+	ctx := context.Background()
+	r, _, other := setupNearCache(t, "nearcacheprefixes=user:,session:")
+
+	require.NoError(t, r.Set(ctx, "user:1", "v1"))
+	require.NoError(t, r.Set(ctx, "other", "v1"))
+	requireValue(t, r, "user:1", "v1")
+	requireValue(t, r, "other", "v1")
+	assert.Equal(t, 1, r.near.Len(), "expected only keys with a tracked prefix to be cached")
+
+	require.NoError(t, other.Set(ctx, "user:1", "v2", 0).Err())
+	require.Eventually(t, func() bool { return r.near.Len() == 0 }, time.Second, time.Millisecond)
+	requireValue(t, r, "user:1", "v2")
+}
+
+func TestNearCache_Reconnect(t *testing.T) {
+	ctx := context.Background()
+	r, server, other := setupNearCache(t, "")
+
+	require.NoError(t, r.Set(ctx, "key", "v1"))
+	requireValue(t, r, "key", "v1")
+
+	// Losing a tracking connection clears the near cache, which is filled again through a
+	// new connection.
+	server.killTracking()
+	require.Eventually(t, func() bool { return r.near.Len() == 0 }, time.Second, time.Millisecond)
+	requireValue(t, r, "key", "v1")
+	assert.Equal(t, 1, r.near.Len())
+	require.NoError(t, other.Set(ctx, "key", "v2", 0).Err())
+	require.Eventually(t, func() bool { return r.near.Len() == 0 }, time.Second, time.Millisecond)
+	requireValue(t, r, "key", "v2")
+}
+
+func TestNearCache_Expiry(t *testing.T) {
+	ctx := context.Background()
+	path, server := listenFake(t)
+	clk := clocktest.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	r := New[string](ctx, &Options{
+		Config:       &Config{NearCache: true, Clock: clk},
+		RedisOptions: RedisOptions{Network: "unix", Addr: path},
+	})
+	t.Cleanup(func() { r.Close() })
+
+	require.NoError(t, r.SetWithTTL(ctx, "key", "value", time.Minute))
+	requireValue(t, r, "key", "value")
+	gets := server.callCount("get")
+	clk.Advance(30 * time.Second)
+	requireValue(t, r, "key", "value")
+	assert.Equal(t, gets, server.callCount("get"), "expected the value to be read from the near cache")
+
+	// Once the TTL elapses, the value is read from the server again.
+	clk.Advance(31 * time.Second)
+	requireValue(t, r, "key", "value")
+	assert.Equal(t, gets+1, server.callCount("get"))
+}
@@ -3,12 +3,27 @@ package redis
 // Options for the Redis cache.
 
 import (
+	"time"
+
+	"github.com/bartventer/gocache/pkg/clock"
 	"github.com/redis/go-redis/v9"
 )
 
 const (
 	// DefaultCountLimit is the default value for the [Config.CountLimit] option.
 	DefaultCountLimit = 10
+
+	// DefaultDelKeysBatchSize is the default value for the [Config.DelKeysBatchSize] option.
+	DefaultDelKeysBatchSize = 1000
+
+	// DefaultNearCacheSize is the default value for the [Config.NearCacheSize] option.
+	DefaultNearCacheSize = 10000
+
+	// DefaultAutoPipelineWindow is the default value for the [Config.AutoPipelineWindow] option.
+	DefaultAutoPipelineWindow = 100 * time.Microsecond
+
+	// DefaultAutoPipelineMaxSize is the default value for the [Config.AutoPipelineMaxSize] option.
+	DefaultAutoPipelineMaxSize = 100
 )
 
 type (
@@ -21,6 +36,59 @@ type (
 		//
 		// [redis scan]: https://redis.io/docs/latest/commands/scan/
 		CountLimit int64
+
+		// DelKeysBatchSize is the maximum number of keys deleted by each command of DelKeys,
+		// which deletes the matching keys in batches as they are scanned.
+		// The default value is 1000.
+		DelKeysBatchSize int
+
+		// NotifyKeyspaceEvents enables the keyspace notifications required by Watch on the
+		// server, by adding the "Kg$xe" flags to the notify-keyspace-events configuration.
+		// Leave it disabled if notifications are configured on the server, or if the CONFIG
+		// command is not available.
+		//
+		// Refer to [redis keyspace notifications] for more information.
+		//
+		// [redis keyspace notifications]: https://redis.io/docs/latest/develop/use/keyspace-notifications/
+		NotifyKeyspaceEvents bool
+
+		// NearCache keeps a local copy of the values read with Get, invalidated with Redis
+		// [client-side caching] when the keys change on the server. It requires Redis 6 or
+		// later, and reads the keys with a dedicated client using RESP3.
+		//
+		// [client-side caching]: https://redis.io/docs/latest/develop/reference/client-side-caching/
+		NearCache bool
+
+		// NearCacheSize is the maximum number of values held by the near cache; the least
+		// recently used values are evicted first.
+		// The default value is 10000.
+		NearCacheSize int
+
+		// NearCachePrefixes switches the near cache to broadcasting mode: the server tracks
+		// the keys starting with one of the prefixes, rather than the keys read by this
+		// client, and only those keys are cached. It uses no server memory per key, at the
+		// price of invalidations for keys that were not read.
+		NearCachePrefixes []string
+
+		// Clock tells the time against which the near cache expires the values of keys with
+		// a TTL.
+		// If not set, the system clock is used.
+		Clock clock.Clock
+
+		// AutoPipeline sends the commands of concurrent Get, Set, SetWithTTL, Exists and Del
+		// calls in shared pipelines, one round trip each, which raises the throughput of
+		// many concurrent callers at the price of up to AutoPipelineWindow of added latency.
+		// Queued commands are not canceled with the context of their call.
+		AutoPipeline bool
+
+		// AutoPipelineWindow is how long the first command of a pipeline waits for others.
+		// The default value is 100µs.
+		AutoPipelineWindow time.Duration
+
+		// AutoPipelineMaxSize is the maximum number of commands in a pipeline; a full
+		// pipeline is sent without waiting for the window to elapse.
+		// The default value is 100.
+		AutoPipelineMaxSize int
 	}
 
 	// RedisOptions is an alias for the [redis.Options] type.
@@ -38,4 +106,16 @@ func (c *Config) revise() {
 	if c.CountLimit <= 0 {
 		c.CountLimit = DefaultCountLimit
 	}
+	if c.DelKeysBatchSize <= 0 {
+		c.DelKeysBatchSize = DefaultDelKeysBatchSize
+	}
+	if c.NearCacheSize <= 0 {
+		c.NearCacheSize = DefaultNearCacheSize
+	}
+	if c.AutoPipelineWindow <= 0 {
+		c.AutoPipelineWindow = DefaultAutoPipelineWindow
+	}
+	if c.AutoPipelineMaxSize <= 0 {
+		c.AutoPipelineMaxSize = DefaultAutoPipelineMaxSize
+	}
 }
@@ -0,0 +1,118 @@
+package redis
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openFakeCache opens a Redis cache with the given query parameters, connected to a fake
+// server with the given round trip latency.
+func openFakeCache(tb testing.TB, query string, latency time.Duration) (*redisCache[string], *fakeServer) {
+	tb.Helper()
+	path, server := listenFake(tb)
+	server.setLatency(latency)
+	r := &redisCache[string]{}
+	if _, err := r.OpenCacheURL(context.Background(), &url.URL{Scheme: SchemeUnix, Path: path, RawQuery: query}); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { r.Close() })
+	return r, server
+}
+
+func TestAutoPipeline(t *testing.T) {
+	ctx := context.Background()
+	r, _ := openFakeCache(t, "autopipeline=true&autopipelinewindow=5ms&autopipelinemaxsize=16", 0)
+	require.NotNil(t, r.pipe)
+
+	var wg sync.WaitGroup
+	for i := range 64 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key, value := "key"+strconv.Itoa(i), "value"+strconv.Itoa(i)
+			assert.NoError(t, r.SetWithTTL(ctx, key, value, time.Minute))
+			got, err := r.Get(ctx, key)
+			assert.NoError(t, err)
+			assert.Equal(t, value, string(got))
+			ok, err := r.Exists(ctx, key)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			assert.NoError(t, r.Del(ctx, key))
+			_, err = r.Get(ctx, key)
+			assert.ErrorIs(t, err, cache.ErrKeyNotFound)
+			assert.ErrorIs(t, r.Del(ctx, key), cache.ErrKeyNotFound)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestAutoPipeline_Canceled(t *testing.T) {
+	r, _ := openFakeCache(t, "autopipeline=true", 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := r.Get(ctx, "key")
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, r.Set(ctx, "key", "value"), context.Canceled)
+}
+
+func TestAutoPipeline_Waiting(t *testing.T) {
+	r, _ := openFakeCache(t, "autopipeline=true&autopipelinewindow=1h", 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := r.Get(ctx, "key")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, r.Close())
+	_, err = r.Get(context.Background(), "key")
+	require.Error(t, err)
+}
+
+// benchmarkParallel runs op b.N times over many goroutines, with the given query
+// parameters, against a fake server with a round trip latency of 200µs.
+func benchmarkParallel(b *testing.B, query string, op func(ctx context.Context, r *redisCache[string], key string) error) {
+	ctx := context.Background()
+	r, _ := openFakeCache(b, query, 200*time.Microsecond)
+	for i := range 1000 {
+		if err := r.Set(ctx, "key"+strconv.Itoa(i), "value"); err != nil {
+			b.Fatal(err)
+		}
+	}
+	var n atomic.Int64
+	b.SetParallelism(64)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			key := "key" + strconv.FormatInt(n.Add(1)%1000, 10)
+			if err := op(ctx, r, key); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkGet(b *testing.B) {
+	get := func(ctx context.Context, r *redisCache[string], key string) error {
+		_, err := r.Get(ctx, key)
+		return err
+	}
+	b.Run("PerCall", func(b *testing.B) { benchmarkParallel(b, "", get) })
+	b.Run("AutoPipeline", func(b *testing.B) { benchmarkParallel(b, "autopipeline=true", get) })
+}
+
+func BenchmarkSet(b *testing.B) {
+	set := func(ctx context.Context, r *redisCache[string], key string) error {
+		return r.Set(ctx, key, "value")
+	}
+	b.Run("PerCall", func(b *testing.B) { benchmarkParallel(b, "", set) })
+	b.Run("AutoPipeline", func(b *testing.B) { benchmarkParallel(b, "autopipeline=true", set) })
+}
@@ -44,6 +44,29 @@ func TestRedisCache_New(t *testing.T) {
 	assert.NotNil(t, r.client)
 }
 
+func TestRedisCache_Unix(t *testing.T) {
+	path, _ := listenFake(t)
+
+	ctx := context.Background()
+	r := &redisCache[string]{}
+	c, err := r.OpenCacheURL(ctx, &url.URL{Scheme: SchemeUnix, Path: path, RawQuery: "db=1"})
+	require.NoError(t, err)
+	defer c.Close()
+	assert.Equal(t, "unix", r.client.Options().Network)
+
+	require.NoError(t, c.Ping(ctx))
+	require.NoError(t, c.Set(ctx, "key", "value"))
+	got, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), got)
+
+	// The value was set in database 1.
+	other := redis.NewClient(&redis.Options{Network: "unix", Addr: path})
+	defer other.Close()
+	_, err = other.Get(ctx, "key").Result()
+	require.ErrorIs(t, err, redis.Nil)
+}
+
 // setupCache creates a new Redis cache with a test container.
 func setupCache[K driver.String](t *testing.T) *redisCache[K] {
 	t.Helper()
@@ -95,7 +118,9 @@ func setupCache[K driver.String](t *testing.T) *redisCache[K] {
 	if err != nil {
 		t.Fatalf("Failed to ping Redis container: %v", err)
 	}
-	return &redisCache[K]{client: client, config: &Config{CountLimit: 100}}
+	config := &Config{CountLimit: 100, NotifyKeyspaceEvents: true}
+	config.revise()
+	return &redisCache[K]{client: client, config: config}
 }
 
 type harness[K driver.String] struct {
@@ -0,0 +1,27 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/pkg/driver"
+)
+
+// RunScript implements driver.ScriptRunner.
+func (r *redisCache[K]) RunScript(ctx context.Context, script *driver.Script, keys []K, args ...interface{}) (interface{}, error) {
+	skeys := make([]string, len(keys))
+	for i, key := range keys {
+		skeys[i] = string(key)
+	}
+	result, err := r.scripts.Run(ctx, r.client, script, skeys, args)
+	if r.near != nil {
+		// The script may have changed any of its keys.
+		for _, key := range skeys {
+			r.near.Invalidate(key)
+		}
+	}
+	if err != nil {
+		return nil, gcerrors.NewWithScheme(Scheme, err)
+	}
+	return result, nil
+}
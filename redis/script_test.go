@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echoScript is run by the fake server, which replies with its first argument.
+var echoScript = &driver.Script{Name: "test:echo", Lua: "return ARGV[1]"}
+
+func TestRunScript_Fallback(t *testing.T) {
+	path, server := listenFake(t)
+
+	ctx := context.Background()
+	r := &redisCache[string]{}
+	_, err := r.OpenCacheURL(ctx, &url.URL{Scheme: SchemeUnix, Path: path})
+	require.NoError(t, err)
+	defer r.Close()
+
+	// The script is sent with EVAL when the server doesn't have it yet.
+	got, err := r.RunScript(ctx, echoScript, []string{"key"}, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", got)
+	assert.Equal(t, 1, server.callCount("evalsha"))
+	assert.Equal(t, 1, server.callCount("eval"))
+
+	got, err = r.RunScript(ctx, echoScript, []string{"key"})
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	assert.Equal(t, 2, server.callCount("evalsha"))
+	assert.Equal(t, 1, server.callCount("eval"))
+
+	server.flushScripts()
+	got, err = r.RunScript(ctx, echoScript, nil, "again")
+	require.NoError(t, err)
+	assert.Equal(t, "again", got)
+	assert.Equal(t, 2, server.callCount("eval"))
+}
+
+func TestRunScript_NoLua(t *testing.T) {
+	r := New[string](context.Background(), &Options{RedisOptions: RedisOptions{Addr: defaultAddr}})
+	defer r.Close()
+	_, err := r.RunScript(context.Background(), &driver.Script{Name: "go"}, nil)
+	require.ErrorIs(t, err, cache.ErrNotSupported)
+}
+
+func TestRunScript(t *testing.T) {
+	ctx := context.Background()
+	r := setupCache[string](t)
+	c := cache.NewCache[string](r)
+
+	require.NoError(t, c.SetWithTTL(ctx, "lock", "owner1", time.Second))
+	got, err := c.RunScript(ctx, cache.ScriptGetEx, []string{"lock"}, time.Minute.Milliseconds())
+	require.NoError(t, err)
+	assert.Equal(t, "owner1", got)
+	ttl, err := r.client.PTTL(ctx, "lock").Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, 50*time.Second)
+
+	got, err = c.RunScript(ctx, cache.ScriptDelIfEqual, []string{"lock"}, "owner2")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), got)
+	got, err = c.RunScript(ctx, cache.ScriptDelIfEqual, []string{"lock"}, "owner1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), got)
+
+	got, err = c.RunScript(ctx, cache.ScriptGetEx, []string{"lock"}, 1000)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	got, err = c.RunScript(ctx, cache.ScriptCappedPush, []string{"log"}, 3, "a", "b")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), got)
+	got, err = c.RunScript(ctx, cache.ScriptCappedPush, []string{"log"}, 3, "c", "d")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), got)
+	list, err := r.client.LRange(ctx, "log", 0, -1).Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"d", "c", "b"}, list)
+	_, err = c.RunScript(ctx, cache.ScriptCappedPush, []string{"log"}, 0, "e")
+	require.Error(t, err)
+}
@@ -0,0 +1,432 @@
+package redis
+
+import (
+	"bufio"
+	"crypto/sha1" //nolint:gosec // Redis identifies scripts by their SHA1 digest.
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bartventer/gocache/internal/glob"
+)
+
+// readCommand reads a command sent by a client as a RESP array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	readLine := func(prefix byte) (int, error) {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return 0, err
+		}
+		line = strings.TrimSuffix(line, "\r\n")
+		if len(line) == 0 || line[0] != prefix {
+			return 0, fmt.Errorf("unexpected line %q", line)
+		}
+		return strconv.Atoi(line[1:])
+	}
+	n, err := readLine('*')
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		size, err := readLine('$')
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+// fakeConn is the state of a connection to a fakeServer.
+type fakeConn struct {
+	id       int64               // id is the client ID.
+	conn     net.Conn            // conn is the client connection.
+	db       string              // db is the selected database.
+	mu       sync.Mutex          // mu serializes writes to w.
+	w        *bufio.Writer       // w writes replies to the client.
+	resp3    bool                // resp3 reports whether the client switched to RESP3 with HELLO.
+	tracking bool                // tracking reports whether the client enabled tracking.
+	prefixes []string            // prefixes are the tracked key prefixes in broadcasting mode.
+	bcast    bool                // bcast reports whether tracking uses broadcasting mode.
+	tracked  map[string]struct{} // tracked are the keys read while tracking, outside broadcasting mode.
+	latency  time.Duration       // latency delays each flush of replies.
+}
+
+// reply writes a raw reply to the client.
+func (c *fakeConn) reply(s string) error {
+	return c.write(s, true)
+}
+
+// write writes a raw reply to the client, flushed if flush is true.
+func (c *fakeConn) write(s string, flush bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.w.WriteString(s)
+	if !flush {
+		return nil
+	}
+	if c.latency > 0 {
+		time.Sleep(c.latency)
+	}
+	return c.w.Flush()
+}
+
+// fakeServer serves a minimal subset of the RESP2 and RESP3 protocols: the hello, get,
+// set, del, unlink, exists, scan, pttl, info, eval, evalsha, select, flushdb, ping,
+// client id and client tracking commands, with each connection's database kept apart.
+// Invalidation messages are pushed to tracking clients using RESP3. Other commands fail.
+// It stands in for a Redis server in tests.
+type fakeServer struct {
+	mu     sync.Mutex
+	data   map[string]string   // data maps db:key to values.
+	ttls   map[string]int64    // ttls maps db:key to the TTL in milliseconds keys were set with; keys never expire.
+	conns  map[int64]*fakeConn // conns are the open connections by client ID.
+	nextID int64               // nextID is the last client ID handed out.
+	delay  time.Duration       // delay is the latency of new connections.
+	calls  map[string]int      // calls counts the commands executed by name.
+	scans  []string            // scans holds the last key returned by each scan, indexed by cursor-1.
+	legacy bool                // legacy rejects the commands added in Redis 4.0, like UNLINK.
+	loaded map[string]bool     // loaded holds the SHA1 digests of the scripts run with EVAL.
+}
+
+// listenFake serves a fakeServer on a unix domain socket until the test ends, and
+// returns the socket path.
+func listenFake(tb testing.TB) (string, *fakeServer) {
+	tb.Helper()
+	dir, err := os.MkdirTemp("", "redis")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "redis.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { l.Close() })
+	return path, serveRedis(tb, l)
+}
+
+// serveRedis serves a fakeServer on l until l is closed.
+func serveRedis(tb testing.TB, l net.Listener) *fakeServer {
+	tb.Helper()
+	s := &fakeServer{data: make(map[string]string), ttls: make(map[string]int64), conns: make(map[int64]*fakeConn), calls: make(map[string]int), loaded: make(map[string]bool)}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+	return s
+}
+
+// handle serves a connection.
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+	s.mu.Lock()
+	s.nextID++
+	c := &fakeConn{id: s.nextID, conn: conn, db: "0", w: bufio.NewWriter(conn), tracked: make(map[string]struct{}), latency: s.delay}
+	s.conns[c.id] = c
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c.id)
+		s.mu.Unlock()
+	}()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		// Replies to pipelined commands are flushed together, like Redis does.
+		if err := c.write(s.exec(c, args), r.Buffered() == 0); err != nil {
+			return
+		}
+	}
+}
+
+// exec executes a command for c and returns the raw reply.
+func (s *fakeServer) exec(c *fakeConn, args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name := strings.ToLower(args[0])
+	s.calls[name]++
+	if s.legacy && name == "unlink" {
+		return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+	}
+	switch name {
+	case "ping":
+		return "+PONG\r\n"
+	case "hello":
+		c.resp3 = len(args) > 1 && args[1] == "3"
+		if !c.resp3 {
+			return "*2\r\n$5\r\nproto\r\n:2\r\n"
+		}
+		return "%1\r\n$5\r\nproto\r\n:3\r\n"
+	case "info":
+		return s.info()
+	case "eval", "evalsha":
+		return s.eval(name, args)
+	case "select":
+		c.db = args[1]
+		return "+OK\r\n"
+	case "set":
+		s.data[c.db+":"+args[1]] = args[2]
+		delete(s.ttls, c.db+":"+args[1])
+		if len(args) == 5 {
+			ttl, _ := strconv.ParseInt(args[4], 10, 64)
+			if strings.EqualFold(args[3], "ex") {
+				ttl *= 1000
+			}
+			s.ttls[c.db+":"+args[1]] = ttl
+		}
+		s.invalidate(args[1])
+		return "+OK\r\n"
+	case "del", "unlink":
+		n := 0
+		for _, key := range args[1:] {
+			if _, ok := s.data[c.db+":"+key]; ok {
+				delete(s.data, c.db+":"+key)
+				s.invalidate(key)
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	case "get":
+		if c.tracking && !c.bcast {
+			c.tracked[args[1]] = struct{}{}
+		}
+		v, ok := s.data[c.db+":"+args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+	case "scan":
+		return s.scan(c, args)
+	case "exists":
+		n := 0
+		for _, key := range args[1:] {
+			if _, ok := s.data[c.db+":"+key]; ok {
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	case "pttl":
+		if _, ok := s.data[c.db+":"+args[1]]; !ok {
+			return ":-2\r\n"
+		}
+		if ttl, ok := s.ttls[c.db+":"+args[1]]; ok {
+			return fmt.Sprintf(":%d\r\n", ttl)
+		}
+		return ":-1\r\n"
+	case "flushdb":
+		for key := range s.data {
+			if strings.HasPrefix(key, c.db+":") {
+				delete(s.data, key)
+			}
+		}
+		for _, other := range s.conns {
+			if other.tracking {
+				clear(other.tracked)
+				s.push(other, "_\r\n")
+			}
+		}
+		return "+OK\r\n"
+	case "client":
+		switch strings.ToLower(args[1]) {
+		case "id":
+			return fmt.Sprintf(":%d\r\n", c.id)
+		case "tracking":
+			c.tracking = strings.EqualFold(args[2], "on")
+			for i := 3; i < len(args); i++ {
+				switch strings.ToLower(args[i]) {
+				case "bcast":
+					c.bcast = true
+				case "prefix":
+					i++
+					c.prefixes = append(c.prefixes, args[i])
+				}
+			}
+			return "+OK\r\n"
+		}
+	}
+	return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+}
+
+// scan executes a SCAN command for c, returning the keys of its database in sorted
+// order. Like Redis, keys that exist for the whole scan are returned even if others are
+// deleted meanwhile. s.mu must be held.
+func (s *fakeServer) scan(c *fakeConn, args []string) string {
+	cursor, _ := strconv.Atoi(args[1])
+	var last string
+	if cursor > 0 && cursor <= len(s.scans) {
+		last = s.scans[cursor-1]
+	}
+	pattern, count := "*", 10
+	for i := 2; i+1 < len(args); i += 2 {
+		switch strings.ToLower(args[i]) {
+		case "match":
+			pattern = args[i+1]
+		case "count":
+			count, _ = strconv.Atoi(args[i+1])
+		}
+	}
+	var keys []string
+	for key := range s.data {
+		if k, ok := strings.CutPrefix(key, c.db+":"); ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	i := sort.SearchStrings(keys, last)
+	if i < len(keys) && keys[i] == last && cursor > 0 {
+		i++
+	}
+	var page []string
+	for ; i < len(keys) && count > 0; i, count = i+1, count-1 {
+		if glob.Match(pattern, keys[i]) {
+			page = append(page, keys[i])
+		}
+	}
+	next := "0"
+	if i < len(keys) {
+		s.scans = append(s.scans, keys[i-1])
+		next = strconv.Itoa(len(s.scans))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "*2\r\n$%d\r\n%s\r\n*%d\r\n", len(next), next, len(page))
+	for _, key := range page {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(key), key)
+	}
+	return b.String()
+}
+
+// info executes an INFO command, returning the server and keyspace sections. s.mu must be
+// held.
+func (s *fakeServer) info() string {
+	keys := make(map[string]int)
+	for key := range s.data {
+		db, _, _ := strings.Cut(key, ":")
+		keys[db]++
+	}
+	dbs := make([]string, 0, len(keys))
+	for db := range keys {
+		dbs = append(dbs, db)
+	}
+	sort.Strings(dbs)
+	var b strings.Builder
+	b.WriteString("# Server\r\nredis_version:7.2.0\r\n\r\n# Keyspace\r\n")
+	for _, db := range dbs {
+		fmt.Fprintf(&b, "db%s:keys=%d,expires=0,avg_ttl=0\r\n", db, keys[db])
+	}
+	return fmt.Sprintf("$%d\r\n%s\r\n", b.Len(), b.String())
+}
+
+// eval executes an EVAL or EVALSHA command. Scripts are not run; the reply is the first
+// argument after the keys, or nil if there is none. s.mu must be held.
+func (s *fakeServer) eval(name string, args []string) string {
+	digest := args[1]
+	if name == "eval" {
+		digest = fmt.Sprintf("%x", sha1.Sum([]byte(args[1])))
+		s.loaded[digest] = true
+	} else if !s.loaded[digest] {
+		return "-NOSCRIPT No matching script. Please use EVAL.\r\n"
+	}
+	numKeys, _ := strconv.Atoi(args[2])
+	if len(args) <= 3+numKeys {
+		return "$-1\r\n"
+	}
+	v := args[3+numKeys]
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+}
+
+// flushScripts makes the server forget the scripts run with EVAL, as a restart does.
+func (s *fakeServer) flushScripts() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.loaded)
+}
+
+// invalidate sends an invalidation message for key to the clients tracking it. s.mu
+// must be held.
+func (s *fakeServer) invalidate(key string) {
+	for _, c := range s.conns {
+		if !c.tracking {
+			continue
+		}
+		if c.bcast {
+			matched := false
+			for _, p := range c.prefixes {
+				matched = matched || strings.HasPrefix(key, p)
+			}
+			if !matched {
+				continue
+			}
+		} else {
+			if _, ok := c.tracked[key]; !ok {
+				continue
+			}
+			delete(c.tracked, key)
+		}
+		s.push(c, fmt.Sprintf("*1\r\n$%d\r\n%s\r\n", len(key), key))
+	}
+}
+
+// push pushes an invalidation message with the given raw list of keys to c, if it uses
+// RESP3. s.mu must be held.
+func (s *fakeServer) push(c *fakeConn, keys string) {
+	if !c.resp3 {
+		return
+	}
+	go c.reply(">2\r\n$10\r\ninvalidate\r\n" + keys)
+}
+
+// setLatency sets the time each round trip on new connections takes, to simulate a
+// remote server.
+func (s *fakeServer) setLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = d
+}
+
+// setLegacy makes the server reject the commands added in Redis 4.0, like UNLINK.
+func (s *fakeServer) setLegacy(legacy bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.legacy = legacy
+}
+
+// callCount returns the number of times the named command was executed.
+func (s *fakeServer) callCount(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[name]
+}
+
+// killTracking closes the connections of the clients that enabled tracking.
+func (s *fakeServer) killTracking() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.conns {
+		if c.tracking {
+			c.conn.Close()
+		}
+	}
+}
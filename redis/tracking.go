@@ -0,0 +1,139 @@
+package redis
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// trackingConn is a connection of the near cache's reading client. It reads the server's
+// replies continuously, so that invalidation messages are applied as soon as they're
+// pushed, even while the connection is idle, and passes the other replies on to go-redis
+// through a pipe.
+type trackingConn struct {
+	net.Conn          // Conn is the connection to the server, which go-redis writes to.
+	r        net.Conn // r is the end of the pipe go-redis reads the replies from.
+	w        net.Conn // w is the end of the pipe the replies are written to.
+	once     sync.Once
+}
+
+// newTrackingConn wraps conn, and starts reading it for nc until it's closed.
+func newTrackingConn(conn net.Conn, nc *nearCache) *trackingConn {
+	r, w := net.Pipe()
+	tc := &trackingConn{Conn: conn, r: r, w: w}
+	go tc.receive(nc)
+	return tc
+}
+
+// receive reads the frames sent by the server until the connection fails, applying push
+// messages to nc and writing the replies to the pipe. The invalidations of the keys read
+// on the connection are lost with it, so nc is cleared once it's done.
+func (tc *trackingConn) receive(nc *nearCache) {
+	defer nc.clear()
+	defer tc.Close()
+	br := bufio.NewReader(tc.Conn)
+	var raw bytes.Buffer
+	for {
+		raw.Reset()
+		v, err := readFrame(br, &raw)
+		if err != nil {
+			return
+		}
+		if msg, ok := v.(pushFrame); ok {
+			nc.invalidatePush(msg)
+			continue
+		}
+		if _, err := tc.w.Write(raw.Bytes()); err != nil {
+			return
+		}
+	}
+}
+
+func (tc *trackingConn) Read(b []byte) (int, error) {
+	return tc.r.Read(b)
+}
+
+func (tc *trackingConn) SetDeadline(t time.Time) error {
+	return errors.Join(tc.Conn.SetWriteDeadline(t), tc.r.SetReadDeadline(t))
+}
+
+func (tc *trackingConn) SetReadDeadline(t time.Time) error {
+	return tc.r.SetReadDeadline(t)
+}
+
+func (tc *trackingConn) Close() error {
+	var err error
+	tc.once.Do(func() {
+		err = errors.Join(tc.Conn.Close(), tc.r.Close(), tc.w.Close())
+	})
+	return err
+}
+
+// pushFrame is the elements of a RESP3 push message.
+type pushFrame []interface{}
+
+// readFrame reads a RESP3 frame from r, appending its raw bytes to raw. Strings, blobs,
+// numbers and other simple types are decoded as strings, nulls as nil, aggregates as
+// []interface{}, with the keys and values of maps alternating, and push messages as a
+// pushFrame.
+func readFrame(r *bufio.Reader, raw *bytes.Buffer) (interface{}, error) {
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	raw.Write(line)
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed frame %q", line)
+	}
+	typ, body := line[0], string(line[1:len(line)-2])
+	switch typ {
+	case '_':
+		return nil, nil
+	case '$', '!', '=':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("malformed frame %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		start := raw.Len()
+		if _, err := io.CopyN(raw, r, int64(n)+2); err != nil {
+			return nil, err
+		}
+		return string(raw.Bytes()[start : start+n]), nil
+	case '*', '~', '%', '|', '>':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("malformed frame %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		if typ == '%' || typ == '|' {
+			n *= 2
+		}
+		elems := make([]interface{}, n)
+		for i := range elems {
+			if elems[i], err = readFrame(r, raw); err != nil {
+				return nil, err
+			}
+		}
+		switch typ {
+		case '|':
+			// Attributes precede the value they describe.
+			return readFrame(r, raw)
+		case '>':
+			return pushFrame(elems), nil
+		}
+		return elems, nil
+	default:
+		return body, nil
+	}
+}
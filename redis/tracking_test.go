@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_readFrame(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame string
+		want  interface{}
+	}{
+		{name: "simple", frame: "+OK\r\n", want: "OK"},
+		{name: "error", frame: "-ERR boom\r\n", want: "ERR boom"},
+		{name: "integer", frame: ":42\r\n", want: "42"},
+		{name: "blob", frame: "$5\r\nhe\r\no\r\n", want: "he\r\no"},
+		{name: "null", frame: "_\r\n", want: nil},
+		{name: "resp2 null", frame: "$-1\r\n", want: nil},
+		{name: "array", frame: "*2\r\n$1\r\na\r\n*1\r\n:1\r\n", want: []interface{}{"a", []interface{}{"1"}}},
+		{name: "map", frame: "%1\r\n$5\r\nproto\r\n:3\r\n", want: []interface{}{"proto", "3"}},
+		{name: "attribute", frame: "|1\r\n+ttl\r\n:3\r\n$1\r\nv\r\n", want: "v"},
+		{name: "push", frame: ">2\r\n$10\r\ninvalidate\r\n*1\r\n$3\r\nkey\r\n", want: pushFrame{"invalidate", []interface{}{"key"}}},
+		{name: "flush push", frame: ">2\r\n$10\r\ninvalidate\r\n_\r\n", want: pushFrame{"invalidate", nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw bytes.Buffer
+			// A trailing frame must be left unread.
+			r := bufio.NewReader(strings.NewReader(tt.frame + "+next\r\n"))
+			got, err := readFrame(r, &raw)
+			if err != nil {
+				t.Fatalf("readFrame() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("readFrame() mismatch (-want +got):\n%s", diff)
+			}
+			if raw.String() != tt.frame {
+				t.Errorf("readFrame() raw = %q, want %q", raw.String(), tt.frame)
+			}
+		})
+	}
+}
+
+func Test_readFrame_Malformed(t *testing.T) {
+	for _, frame := range []string{"+OK\n", "$x\r\n", "*2\r\n+a\r\n", "$5\r\nab"} {
+		if _, err := readFrame(bufio.NewReader(strings.NewReader(frame)), new(bytes.Buffer)); err == nil {
+			t.Errorf("readFrame(%q) error = nil, want an error", frame)
+		}
+	}
+}
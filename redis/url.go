@@ -1,7 +1,11 @@
 package redis
 
 import (
+	"errors"
+	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bartventer/gocache/internal/urlparser"
@@ -16,27 +20,44 @@ var paramKeyBlacklist = map[string]struct{}{
 	"onconnect":                  {},
 	"credentialsprovider":        {},
 	"credentialsprovidercontext": {},
+	"clock":                      {},
+	urlparser.ParamTLSCAFile:     {},
+	urlparser.ParamTLSCertFile:   {},
+	urlparser.ParamTLSKeyFile:    {},
+	urlparser.ParamTLSServerName: {},
 }
 
 // optionsFromURL parses a [url.URL] into [redis.Options].
 //
 // The URL should have the following format:
 //
-//	redis://host:port
+//	redis[s]://[[username]:password@]host:port[/db]
+//
+// or, for a server listening on a unix domain socket:
+//
+//	redis+unix://[[username]:password@]/path/to/redis.sock[?db=<db>]
+//
+// The credentials and the database number are taken from the URL, as with redis-cli.
+// The rediss scheme enables TLS, verifying the server against the system roots.
 //
 // All redis client options can be set as query parameters, except for the following:
 //   - [redis.Options.Addr]
 //   - Any option that is a function
-//   - Any options defined in cache.Options
+//   - [Config.Clock]
+//
+// The options defined in [Config] can also be set as query parameters, and TLS can be
+// configured from files with the tlscafile, tlscertfile, tlskeyfile and tlsservername
+// parameters, which also enable it.
 //
 // Example:
 //
-//	redis://localhost:6379?maxretries=5&minretrybackoff=512ms
+//	redis://localhost:6379?maxretries=5&minretrybackoff=512ms&notifykeyspaceevents=true
 //
 // This will return a redis.Options with the Addr set to "localhost:6379",
-// MaxRetries set to 5, and MinRetryBackoff set to 512ms.
+// MaxRetries set to 5, and MinRetryBackoff set to 512ms, and enable keyspace
+// notifications for Watch.
 func optionsFromURL(u *url.URL) (Options, error) {
-	var opts Options
+	opts := Options{Config: &Config{}}
 
 	// Parse the query parameters into a map
 	parser := urlparser.New(
@@ -53,8 +74,33 @@ func optionsFromURL(u *url.URL) (Options, error) {
 		return Options{}, err
 	}
 
-	// Set the Addr from the URL
-	opts.Addr = u.Host
+	// Set the Addr, credentials and DB from the URL
+	if u.Scheme == SchemeUnix {
+		if u.Host+u.Path == "" {
+			return Options{}, errors.New("missing socket path")
+		}
+		opts.Network = "unix"
+		opts.Addr = u.Host + u.Path
+	} else {
+		opts.Addr = u.Host
+		if db := strings.Trim(u.Path, "/"); db != "" {
+			n, err := strconv.Atoi(db)
+			if err != nil || n < 0 {
+				return Options{}, fmt.Errorf("invalid database number %q", db)
+			}
+			opts.DB = n
+		}
+	}
+	if u.User != nil {
+		opts.Username = u.User.Username()
+		opts.Password, _ = u.User.Password()
+	}
+
+	tlsConfig, err := urlparser.TLSConfigFromURL(u, opts.TLSConfig, u.Scheme == SchemeTLS)
+	if err != nil {
+		return Options{}, err
+	}
+	opts.TLSConfig = tlsConfig
 
 	return opts, nil
 }
@@ -0,0 +1,59 @@
+package rediscluster
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/pkg/driver"
+)
+
+// DelKeysWithOptions implements [driver.KeysDeleter].
+//
+// The matching keys of each master are deleted with UNLINK, which frees their memory in
+// the background, in pipelines of at most [Config.DelKeysBatchSize] keys as they are
+// scanned, so that neither the process nor the servers hold all of them at once. The
+// keys of each batch are unlinked by one command per hash slot, since keys of different
+// slots can't be deleted together. Servers without UNLINK fall back to DEL. With
+// [driver.DelKeysOptions.MaxKeys], the keys of all masters are counted before any is
+// deleted.
+//
+// Use [redisClusterCache.DelKeysReport] for the outcome on each master.
+func (r *redisClusterCache[K]) DelKeysWithOptions(ctx context.Context, pattern K, opts driver.DelKeysOptions) (int64, error) {
+	report, err := r.DelKeysReport(ctx, pattern, &NodeOptions{DelKeysOptions: opts})
+	n := report.Deleted()
+	if opts.DryRun {
+		n = report.Scanned()
+	}
+	return n, err
+}
+
+// DelKeysReport deletes the keys matching pattern as [redisClusterCache.DelKeysWithOptions]
+// does, and reports the keys found and deleted on each master. In dry-run mode, no keys
+// are deleted, and the keys that would be are reported as scanned. If more keys than
+// MaxKeys match, no keys are deleted and the report is empty.
+func (r *redisClusterCache[K]) DelKeysReport(ctx context.Context, pattern K, opts *NodeOptions) (Report, error) {
+	o := r.options(opts)
+	nodes, err := r.masters(ctx)
+	if err != nil {
+		return Report{}, gcerrors.NewWithScheme(Scheme, err)
+	}
+	if o.MaxKeys > 0 {
+		if err := r.deleter.CheckMax(ctx, r.client.ForEachMaster, string(pattern), o.MaxKeys); err != nil {
+			return Report{}, gcerrors.NewWithScheme(Scheme, err)
+		}
+	}
+	var matched atomic.Int64
+	report, err := runNodes(ctx, nodes, o, func(ctx context.Context, node masterNode, nr *NodeReport, progress func()) error {
+		var err error
+		nr.Scanned, nr.Deleted, err = r.deleter.Delete(ctx, node.client, string(pattern), o.DelKeysOptions, &matched, func(scanned, deleted int64) {
+			nr.Scanned, nr.Deleted = scanned, deleted
+			progress()
+		})
+		return err
+	})
+	if err != nil {
+		return report, gcerrors.NewWithScheme(Scheme, err)
+	}
+	return report, nil
+}
@@ -0,0 +1,41 @@
+package rediscluster
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelKeysWithOptions(t *testing.T) {
+	ctx := context.Background()
+	r := setupCache[string](t)
+	r.deleter.BatchSize = 10
+	for i := range 50 {
+		require.NoError(t, r.Set(ctx, "user:"+strconv.Itoa(i), "value"))
+	}
+	require.NoError(t, r.Set(ctx, "session:1", "value"))
+
+	n, err := r.DelKeysWithOptions(ctx, "user:*", driver.DelKeysOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), n)
+
+	n, err = r.DelKeysWithOptions(ctx, "user:*", driver.DelKeysOptions{MaxKeys: 20})
+	require.ErrorIs(t, err, cache.ErrMaxKeysExceeded)
+	assert.Equal(t, int64(0), n)
+	count, err := r.Count(ctx, "user:*")
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), count)
+
+	n, err = r.DelKeysWithOptions(ctx, "user:*", driver.DelKeysOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), n)
+
+	count, err = r.Count(ctx, "*")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,43 @@
+package rediscluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/internal/redisinfo"
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/redis/go-redis/v9"
+)
+
+// Diagnostics implements driver.Diagnoser.
+//
+// Every master and replica node is reported with its own connection pool and the
+// sections of the INFO command returned by default, which include memory, stats and
+// keyspace.
+func (r *redisClusterCache[K]) Diagnostics(ctx context.Context) (*driver.Diagnostics, error) {
+	var (
+		mu      sync.Mutex
+		servers []driver.ServerDiagnostics
+	)
+	err := r.client.ForEachShard(ctx, func(ctx context.Context, client *redis.Client) error {
+		server := driver.ServerDiagnostics{Addr: client.Options().Addr, Pool: redisinfo.PoolStats(client.PoolStats())}
+		info, err := client.Info(ctx).Result()
+		if err != nil {
+			server.Err = fmt.Errorf("error getting server info: %w", err)
+		} else {
+			server.Info = redisinfo.Parse(info)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		servers = append(servers, server)
+		return nil
+	})
+	if err != nil {
+		return nil, gcerrors.NewWithScheme(Scheme, fmt.Errorf("error listing nodes: %w", err))
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Addr < servers[j].Addr })
+	return &driver.Diagnostics{Pool: redisinfo.PoolStats(r.client.PoolStats()), Servers: servers}, nil
+}
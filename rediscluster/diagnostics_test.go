@@ -0,0 +1,32 @@
+package rediscluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnostics(t *testing.T) {
+	ctx := context.Background()
+	r := setupCache[string](t)
+	require.NoError(t, r.Set(ctx, "key", "value"))
+
+	d, err := r.Diagnostics(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, d.Pool)
+	assert.Positive(t, d.Pool.TotalConns)
+	require.NotEmpty(t, d.Servers)
+	var keys int
+	for _, server := range d.Servers {
+		require.NoError(t, server.Err)
+		assert.NotEmpty(t, server.Addr)
+		assert.NotNil(t, server.Pool)
+		assert.Contains(t, server.Info, "memory")
+		if _, ok := server.Info["keyspace"]["db0"]; ok {
+			keys++
+		}
+	}
+	assert.Positive(t, keys, "expected a node to report the key")
+}
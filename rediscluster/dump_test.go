@@ -0,0 +1,61 @@
+package rediscluster
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/bartventer/gocache/ramcache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpRestore_Ramcache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	src := cache.NewCache[string](setupCache[string](t))
+	dst := cache.NewCache[string](ramcache.New[string](ctx, &ramcache.Options{}))
+	t.Cleanup(func() {
+		dst.Close()
+	})
+
+	entries := map[string]string{
+		"{dump}user:1": "alice",
+		"{dump}user:2": "bob",
+		"user:3":       "carol",
+	}
+	for key, value := range entries {
+		require.NoError(t, src.Set(ctx, key, value))
+	}
+	require.NoError(t, src.SetWithTTL(ctx, "user:4", "dave", time.Hour))
+	require.NoError(t, src.Set(ctx, "session:1", "ignored"))
+
+	var buf bytes.Buffer
+	require.NoError(t, src.Dump(ctx, &buf, "*user:*"))
+	require.NoError(t, dst.Restore(ctx, &buf, nil))
+
+	for key, value := range entries {
+		got, err := dst.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, value, string(got))
+	}
+	got, err := dst.Get(ctx, "user:4")
+	require.NoError(t, err)
+	assert.Equal(t, "dave", string(got))
+
+	exists, err := dst.Exists(ctx, "session:1")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	var ttls []time.Duration
+	err = dst.Scan(ctx, "user:4", func(e driver.Entry[string]) error {
+		ttls = append(ttls, e.TTL)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, ttls, 1)
+	assert.Greater(t, ttls[0], 59*time.Minute)
+}
@@ -4,10 +4,14 @@ go 1.22
 
 toolchain go1.22.4
 
-replace github.com/bartventer/gocache => ../
+replace (
+	github.com/bartventer/gocache => ../
+	github.com/bartventer/gocache/ramcache => ../ramcache
+)
 
 require (
 	github.com/bartventer/gocache v1.15.0
+	github.com/bartventer/gocache/ramcache v1.15.0
 	github.com/docker/docker v27.1.1+incompatible
 	github.com/docker/go-connections v0.5.0
 	github.com/google/go-cmp v0.6.0
@@ -39,7 +43,6 @@ require (
 	github.com/gogo/protobuf v1.3.2 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/klauspost/compress v1.17.9 // indirect
-	github.com/kr/text v0.2.0 // indirect
 	github.com/lufia/plan9stats v0.0.0-20240513124658-fba389f38bae // indirect
 	github.com/magiconair/properties v1.8.7 // indirect
 	github.com/moby/docker-image-spec v1.3.1 // indirect
@@ -0,0 +1,227 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/bartventer/gocache/pkg/keymod"
+	"github.com/redis/go-redis/v9"
+)
+
+// slotGroup is a set of keys sharing a hash slot.
+type slotGroup struct {
+	slot int   // slot is the hash slot of the keys.
+	idx  []int // idx are the positions of the keys in the caller's keys.
+}
+
+// groupBySlot groups the n keys returned by keyAt by hash slot, in order of first
+// appearance.
+func groupBySlot(n int, keyAt func(i int) string) []*slotGroup {
+	var groups []*slotGroup
+	bySlot := make(map[int]*slotGroup)
+	for i := range n {
+		slot := keymod.Slot(keyAt(i))
+		g, ok := bySlot[slot]
+		if !ok {
+			g = &slotGroup{slot: slot}
+			bySlot[slot] = g
+			groups = append(groups, g)
+		}
+		g.idx = append(g.idx, i)
+	}
+	return groups
+}
+
+// nodeGroups groups the n keys returned by keyAt by hash slot, and the slots by the
+// master node owning them.
+func (r *redisClusterCache[K]) nodeGroups(ctx context.Context, n int, keyAt func(i int) string) (map[*redis.Client][]*slotGroup, error) {
+	nodes := make(map[*redis.Client][]*slotGroup)
+	for _, g := range groupBySlot(n, keyAt) {
+		node, err := r.client.MasterForKey(ctx, keyAt(g.idx[0]))
+		if err != nil {
+			return nil, fmt.Errorf("error finding the node of slot %d: %w", g.slot, err)
+		}
+		nodes[node] = append(nodes[node], g)
+	}
+	return nodes, nil
+}
+
+// isRedirect reports whether err redirects a command to another node, as happens while
+// slots are migrated.
+func isRedirect(err error) bool {
+	return err != nil && (strings.HasPrefix(err.Error(), "MOVED ") || strings.HasPrefix(err.Error(), "ASK "))
+}
+
+// execGroups sends the commands queued for the slot groups of each node in a single
+// pipeline, with the nodes concurrently. queue adds the commands of a group to pipe, and
+// returns a function collecting their results once the pipeline has been executed.
+//
+// Groups whose commands are redirected are retried with the cluster client, which
+// follows redirections.
+func (r *redisClusterCache[K]) execGroups(ctx context.Context, nodes map[*redis.Client][]*slotGroup, queue func(pipe redis.Pipeliner, g *slotGroup) func() error) error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	errs := make([]error, 0, len(nodes))
+	for node, groups := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			redirected, err := execPipeline(ctx, node, groups, queue)
+			if len(redirected) > 0 {
+				again, retryErr := execPipeline(ctx, r.client, redirected, queue)
+				err = errors.Join(err, retryErr)
+				for _, g := range again {
+					err = errors.Join(err, fmt.Errorf("keys of slot %d were redirected", g.slot))
+				}
+			}
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// execPipeline sends the commands queued for groups in a single pipeline of c, and
+// collects their results. It returns the groups whose commands were redirected, whose
+// results are not collected.
+func execPipeline(ctx context.Context, c redis.Cmdable, groups []*slotGroup, queue func(pipe redis.Pipeliner, g *slotGroup) func() error) ([]*slotGroup, error) {
+	collects := make([]func() error, len(groups))
+	ends := make([]int, len(groups))
+	// Errors are set on the commands, and returned by the collecting functions.
+	cmds, _ := c.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, g := range groups {
+			collects[i] = queue(pipe, g)
+			ends[i] = pipe.Len()
+		}
+		return nil
+	})
+	var redirected []*slotGroup
+	errs := make([]error, 0, len(groups))
+	start := 0
+	for i, g := range groups {
+		moved := false
+		for _, cmd := range cmds[start:ends[i]] {
+			moved = moved || isRedirect(cmd.Err())
+		}
+		start = ends[i]
+		if moved {
+			redirected = append(redirected, g)
+			continue
+		}
+		errs = append(errs, collects[i]())
+	}
+	return redirected, errors.Join(errs...)
+}
+
+// GetMulti implements [driver.Batcher].
+//
+// The keys are grouped by hash slot, each group read with a single MGET, and the groups
+// of each master node sent in one pipeline, with the nodes concurrently.
+func (r *redisClusterCache[K]) GetMulti(ctx context.Context, keys []K) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	nodes, err := r.nodeGroups(ctx, len(keys), func(i int) string { return string(keys[i]) })
+	if err != nil {
+		return nil, gcerrors.NewWithScheme(Scheme, err)
+	}
+	err = r.execGroups(ctx, nodes, func(pipe redis.Pipeliner, g *slotGroup) func() error {
+		args := make([]string, len(g.idx))
+		for j, i := range g.idx {
+			args[j] = string(keys[i])
+		}
+		cmd := pipe.MGet(ctx, args...)
+		return func() error {
+			vals, err := cmd.Result()
+			if err != nil {
+				return fmt.Errorf("error getting keys of slot %d: %w", g.slot, err)
+			}
+			for j, v := range vals {
+				if s, ok := v.(string); ok {
+					values[g.idx[j]] = []byte(s)
+				}
+			}
+			return nil
+		}
+	})
+	if err != nil {
+		return nil, gcerrors.NewWithScheme(Scheme, err)
+	}
+	return values, nil
+}
+
+// SetMulti implements [driver.Batcher].
+//
+// The entries are grouped by hash slot; the entries of a group without a TTL are set with
+// a single MSET, and the others with a SET each. The groups of each master node are sent
+// in one pipeline, with the nodes concurrently.
+func (r *redisClusterCache[K]) SetMulti(ctx context.Context, entries []driver.Entry[K]) error {
+	nodes, err := r.nodeGroups(ctx, len(entries), func(i int) string { return string(entries[i].Key) })
+	if err != nil {
+		return gcerrors.NewWithScheme(Scheme, err)
+	}
+	err = r.execGroups(ctx, nodes, func(pipe redis.Pipeliner, g *slotGroup) func() error {
+		var cmds []*redis.StatusCmd
+		var pairs []interface{}
+		for _, i := range g.idx {
+			e := entries[i]
+			if e.TTL > 0 {
+				cmds = append(cmds, pipe.Set(ctx, string(e.Key), e.Value, e.TTL))
+			} else {
+				pairs = append(pairs, string(e.Key), e.Value)
+			}
+		}
+		if len(pairs) > 0 {
+			cmds = append(cmds, pipe.MSet(ctx, pairs...))
+		}
+		return func() error {
+			for _, cmd := range cmds {
+				if err := cmd.Err(); err != nil {
+					return fmt.Errorf("error setting keys of slot %d: %w", g.slot, err)
+				}
+			}
+			return nil
+		}
+	})
+	if err != nil {
+		return gcerrors.NewWithScheme(Scheme, err)
+	}
+	return nil
+}
+
+// DelMulti implements [driver.Batcher].
+//
+// The keys are grouped by hash slot, each group deleted with a single DEL, and the groups
+// of each master node sent in one pipeline, with the nodes concurrently.
+func (r *redisClusterCache[K]) DelMulti(ctx context.Context, keys []K) (int64, error) {
+	var deleted atomic.Int64
+	nodes, err := r.nodeGroups(ctx, len(keys), func(i int) string { return string(keys[i]) })
+	if err != nil {
+		return 0, gcerrors.NewWithScheme(Scheme, err)
+	}
+	err = r.execGroups(ctx, nodes, func(pipe redis.Pipeliner, g *slotGroup) func() error {
+		args := make([]string, len(g.idx))
+		for j, i := range g.idx {
+			args[j] = string(keys[i])
+		}
+		cmd := pipe.Del(ctx, args...)
+		return func() error {
+			n, err := cmd.Result()
+			if err != nil {
+				return fmt.Errorf("error deleting keys of slot %d: %w", g.slot, err)
+			}
+			deleted.Add(n)
+			return nil
+		}
+	})
+	if err != nil {
+		return deleted.Load(), gcerrors.NewWithScheme(Scheme, err)
+	}
+	return deleted.Load(), nil
+}
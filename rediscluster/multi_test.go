@@ -0,0 +1,57 @@
+package rediscluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/bartventer/gocache/pkg/keymod"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_groupBySlot(t *testing.T) {
+	keys := []string{
+		string(keymod.Key("name").TagPrefix("user1")),
+		"foo",
+		string(keymod.Key("email").TagPrefix("user1")),
+		"bar",
+		"foo",
+	}
+	groups := groupBySlot(len(keys), func(i int) string { return keys[i] })
+	require.Len(t, groups, 3)
+	assert.Equal(t, keymod.Slot("user1"), groups[0].slot)
+	assert.Equal(t, []int{0, 2}, groups[0].idx)
+	assert.Equal(t, keymod.Slot("foo"), groups[1].slot)
+	assert.Equal(t, []int{1, 4}, groups[1].idx)
+	assert.Equal(t, []int{3}, groups[2].idx)
+}
+
+func TestMulti(t *testing.T) {
+	ctx := context.Background()
+	r := setupCache[string](t)
+
+	keys := []string{"{user1}name", "{user1}email", "foo", "bar", "{user2}name", "missing"}
+	entries := make([]driver.Entry[string], 0, len(keys)-1)
+	for _, key := range keys[:len(keys)-1] {
+		entries = append(entries, driver.Entry[string]{Key: key, Value: []byte("value of " + key)})
+	}
+	entries[2].TTL = time.Hour
+	require.NoError(t, r.SetMulti(ctx, entries))
+
+	values, err := r.GetMulti(ctx, keys)
+	require.NoError(t, err)
+	require.Len(t, values, len(keys))
+	for i, key := range keys[:len(keys)-1] {
+		assert.Equal(t, "value of "+key, string(values[i]))
+	}
+	assert.Nil(t, values[len(keys)-1])
+
+	n, err := r.DelMulti(ctx, keys)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(keys)-1), n)
+	count, err := r.Count(ctx, "*")
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,196 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrNodeSkipped is reported for the master nodes not operated on, or interrupted,
+// because another node failed, unless [NodeOptions.ContinueOnError] is set. The report
+// of an interrupted node keeps the keys scanned and deleted before the interruption.
+var ErrNodeSkipped = errors.New("rediscluster: node skipped after another node failed")
+
+// NodeReport is the outcome of a cluster-wide operation on a master node.
+type NodeReport struct {
+	Addr    string // Addr is the address of the node.
+	Scanned int64  // Scanned is the number of keys matching the pattern found on the node.
+	Deleted int64  // Deleted is the number of keys deleted from the node.
+	Err     error  // Err is the error the operation failed with on the node, if any.
+}
+
+// Report is the outcome of a cluster-wide operation.
+type Report struct {
+	Nodes []NodeReport // Nodes are the reports of the master nodes, sorted by address.
+}
+
+// Scanned returns the number of keys matching the pattern found on all nodes.
+func (r Report) Scanned() int64 {
+	var n int64
+	for _, node := range r.Nodes {
+		n += node.Scanned
+	}
+	return n
+}
+
+// Deleted returns the number of keys deleted from all nodes.
+func (r Report) Deleted() int64 {
+	var n int64
+	for _, node := range r.Nodes {
+		n += node.Deleted
+	}
+	return n
+}
+
+// Failed returns the reports of the nodes the operation failed on, including the
+// skipped nodes.
+func (r Report) Failed() []NodeReport {
+	var failed []NodeReport
+	for _, node := range r.Nodes {
+		if node.Err != nil {
+			failed = append(failed, node)
+		}
+	}
+	return failed
+}
+
+// NodeOptions are the options for the cluster-wide operations reporting per node. The
+// zero value uses the [Config] of the cache.
+type NodeOptions struct {
+	// DelKeysOptions are the options for [redisClusterCache.DelKeysReport].
+	driver.DelKeysOptions
+
+	// ContinueOnError goes on with the other nodes when one fails. It's implied by
+	// [Config.ContinueOnNodeError].
+	ContinueOnError bool
+
+	// Concurrency is the maximum number of nodes operated on concurrently. It defaults
+	// to [Config.NodeConcurrency].
+	Concurrency int
+
+	// Progress is called with the report of a node as the operation progresses on it:
+	// after each batch of keys deleted by DelKeysReport, and when the node is done. Calls
+	// are serialized.
+	Progress func(NodeReport)
+}
+
+// options returns opts with the defaults of the cache configuration applied.
+func (r *redisClusterCache[K]) options(opts *NodeOptions) NodeOptions {
+	var o NodeOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.ContinueOnError = o.ContinueOnError || r.config.ContinueOnNodeError
+	if o.Concurrency <= 0 {
+		o.Concurrency = r.config.NodeConcurrency
+	}
+	return o
+}
+
+// masterNode is a master node of the cluster.
+type masterNode struct {
+	addr   string        // addr is the address of the node.
+	client *redis.Client // client is the client of the node.
+}
+
+// masters returns the master nodes of the cluster, sorted by address.
+func (r *redisClusterCache[K]) masters(ctx context.Context) ([]masterNode, error) {
+	var mu sync.Mutex
+	var nodes []masterNode
+	err := r.client.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+		mu.Lock()
+		defer mu.Unlock()
+		nodes = append(nodes, masterNode{addr: client.Options().Addr, client: client})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing master nodes: %w", err)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].addr < nodes[j].addr })
+	return nodes, nil
+}
+
+// runNodes runs op on each node, with at most opts.Concurrency nodes at once, and
+// reports the outcome on each node in order of nodes. op updates the report of its node,
+// and calls progress to have it passed to opts.Progress.
+//
+// Unless opts.ContinueOnError is set, the first failure cancels the operation on the
+// other nodes, those not started yet or interrupted by the cancellation are reported as
+// skipped, and only that failure is returned. Otherwise the failures of all nodes are
+// returned.
+func runNodes(parent context.Context, nodes []masterNode, opts NodeOptions, op func(ctx context.Context, node masterNode, report *NodeReport, progress func()) error) (Report, error) {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	report := Report{Nodes: make([]NodeReport, len(nodes))}
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex // mu serializes calls to opts.Progress, and guards firstErr.
+		firstErr error
+	)
+	notify := func(nr *NodeReport) {
+		if opts.Progress == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		opts.Progress(*nr)
+	}
+	sem := make(chan struct{}, max(opts.Concurrency, 1))
+	for i, node := range nodes {
+		nr := &report.Nodes[i]
+		nr.Addr = node.addr
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			mu.Lock()
+			failed := firstErr != nil && !opts.ContinueOnError
+			mu.Unlock()
+			if failed {
+				nr.Err = ErrNodeSkipped
+				return
+			}
+			if err := op(ctx, node, nr, func() { notify(nr) }); err != nil {
+				nr.Err = fmt.Errorf("node %s: %w", node.addr, err)
+			}
+			mu.Lock()
+			// A node interrupted by the failure of another was skipped, rather than
+			// failed, unless the caller canceled the operation.
+			if nr.Err != nil && firstErr != nil && !opts.ContinueOnError && parent.Err() == nil && errors.Is(nr.Err, context.Canceled) {
+				nr.Err = ErrNodeSkipped
+			}
+			mu.Unlock()
+			notify(nr)
+			if nr.Err == nil || errors.Is(nr.Err, ErrNodeSkipped) {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if firstErr == nil {
+				firstErr = nr.Err
+				if !opts.ContinueOnError {
+					cancel()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !opts.ContinueOnError {
+		return report, firstErr
+	}
+	var errs []error
+	for _, node := range report.Nodes {
+		errs = append(errs, node.Err)
+	}
+	return report, errors.Join(errs...)
+}
@@ -0,0 +1,145 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testNodes returns n master nodes without clients, for operations that don't use them.
+func testNodes(n int) []masterNode {
+	nodes := make([]masterNode, n)
+	for i := range nodes {
+		nodes[i].addr = fmt.Sprintf("node%d:7000", i)
+	}
+	return nodes
+}
+
+func Test_runNodes(t *testing.T) {
+	var running, peak atomic.Int32
+	var progress []NodeReport
+	report, err := runNodes(context.Background(), testNodes(6), NodeOptions{
+		Concurrency: 2,
+		Progress:    func(nr NodeReport) { progress = append(progress, nr) },
+	}, func(_ context.Context, node masterNode, nr *NodeReport, progress func()) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		nr.Scanned, nr.Deleted = 2, 1
+		progress()
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), peak.Load(), "expected at most 2 nodes at once")
+	require.Len(t, report.Nodes, 6)
+	assert.Equal(t, "node0:7000", report.Nodes[0].Addr)
+	assert.Equal(t, int64(12), report.Scanned())
+	assert.Equal(t, int64(6), report.Deleted())
+	assert.Empty(t, report.Failed())
+	assert.Len(t, progress, 12, "expected a call per progress and per node done")
+}
+
+func Test_runNodes_StopOnError(t *testing.T) {
+	errFailed := errors.New("failed")
+	report, err := runNodes(context.Background(), testNodes(4), NodeOptions{Concurrency: 1},
+		func(_ context.Context, node masterNode, nr *NodeReport, _ func()) error {
+			if node.addr == "node1:7000" {
+				return errFailed
+			}
+			nr.Scanned = 1
+			return nil
+		})
+	require.ErrorIs(t, err, errFailed)
+	assert.NotErrorIs(t, err, ErrNodeSkipped)
+	assert.NoError(t, report.Nodes[0].Err)
+	assert.ErrorIs(t, report.Nodes[1].Err, errFailed)
+	assert.ErrorIs(t, report.Nodes[2].Err, ErrNodeSkipped)
+	assert.ErrorIs(t, report.Nodes[3].Err, ErrNodeSkipped)
+	assert.Len(t, report.Failed(), 3)
+	assert.Equal(t, int64(1), report.Scanned())
+}
+
+func Test_runNodes_Interrupted(t *testing.T) {
+	errFailed := errors.New("failed")
+	started := make(chan struct{})
+	report, err := runNodes(context.Background(), testNodes(2), NodeOptions{Concurrency: 2},
+		func(ctx context.Context, node masterNode, nr *NodeReport, _ func()) error {
+			if node.addr == "node1:7000" {
+				<-started
+				return errFailed
+			}
+			nr.Scanned = 1
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	require.ErrorIs(t, err, errFailed)
+	assert.ErrorIs(t, report.Nodes[0].Err, ErrNodeSkipped)
+	assert.NotErrorIs(t, report.Nodes[0].Err, context.Canceled)
+	assert.Equal(t, int64(1), report.Nodes[0].Scanned)
+	assert.ErrorIs(t, report.Nodes[1].Err, errFailed)
+}
+
+func Test_runNodes_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := runNodes(ctx, testNodes(1), NodeOptions{},
+		func(ctx context.Context, _ masterNode, _ *NodeReport, _ func()) error {
+			return ctx.Err()
+		})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, report.Nodes[0].Err, ErrNodeSkipped)
+}
+
+func Test_runNodes_ContinueOnError(t *testing.T) {
+	errFailed := errors.New("failed")
+	report, err := runNodes(context.Background(), testNodes(4), NodeOptions{Concurrency: 1, ContinueOnError: true},
+		func(_ context.Context, node masterNode, nr *NodeReport, _ func()) error {
+			if node.addr == "node1:7000" || node.addr == "node3:7000" {
+				return errFailed
+			}
+			nr.Scanned = 1
+			return nil
+		})
+	require.ErrorIs(t, err, errFailed)
+	assert.ErrorContains(t, err, "node1:7000")
+	assert.ErrorContains(t, err, "node3:7000")
+	assert.Len(t, report.Failed(), 2)
+	assert.Equal(t, int64(2), report.Scanned())
+}
+
+func TestReports(t *testing.T) {
+	ctx := context.Background()
+	r := setupCache[string](t)
+	for i := range 20 {
+		require.NoError(t, r.Set(ctx, fmt.Sprintf("user:%d", i), "value"))
+	}
+
+	report, err := r.CountReport(ctx, "user:*", nil)
+	require.NoError(t, err)
+	assert.Len(t, report.Nodes, 3)
+	assert.Equal(t, int64(20), report.Scanned())
+
+	var calls atomic.Int32
+	report, err = r.DelKeysReport(ctx, "user:*", &NodeOptions{Progress: func(NodeReport) { calls.Add(1) }})
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), report.Deleted())
+	assert.Positive(t, calls.Load())
+
+	require.NoError(t, r.Set(ctx, "key", "value"))
+	report, err = r.ClearReport(ctx, &NodeOptions{Concurrency: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), report.Deleted())
+}
@@ -3,12 +3,26 @@ package rediscluster
 // Options for the Redis cluster cache.
 
 import (
+	"time"
+
 	"github.com/redis/go-redis/v9"
 )
 
 const (
 	// DefaultCountLimit is the default value for the [Config.CountLimit] option.
 	DefaultCountLimit = 10
+
+	// DefaultDelKeysBatchSize is the default value for the [Config.DelKeysBatchSize] option.
+	DefaultDelKeysBatchSize = 1000
+
+	// DefaultNodeConcurrency is the default value for the [Config.NodeConcurrency] option.
+	DefaultNodeConcurrency = 16
+
+	// DefaultAutoPipelineWindow is the default value for the [Config.AutoPipelineWindow] option.
+	DefaultAutoPipelineWindow = 100 * time.Microsecond
+
+	// DefaultAutoPipelineMaxSize is the default value for the [Config.AutoPipelineMaxSize] option.
+	DefaultAutoPipelineMaxSize = 100
 )
 
 type (
@@ -21,6 +35,47 @@ type (
 		//
 		// [redis scan]: https://redis.io/docs/latest/commands/scan/
 		CountLimit int64
+
+		// DelKeysBatchSize is the maximum number of keys deleted by each pipeline of
+		// DelKeys, which deletes the matching keys of each master in batches as they are
+		// scanned.
+		// The default value is 1000.
+		DelKeysBatchSize int
+
+		// NodeConcurrency is the maximum number of master nodes Count, DelKeys and Clear
+		// operate on concurrently.
+		// The default value is 16.
+		NodeConcurrency int
+
+		// ContinueOnNodeError makes Count, DelKeys and Clear go on with the other master
+		// nodes when one fails, rather than stopping at the first failure. The errors of
+		// all failed nodes are returned.
+		ContinueOnNodeError bool
+
+		// NotifyKeyspaceEvents enables the keyspace notifications required by Watch on every
+		// master node, by adding the "Kg$xe" flags to the notify-keyspace-events configuration.
+		// Leave it disabled if notifications are configured on the servers, or if the CONFIG
+		// command is not available.
+		//
+		// Refer to [redis keyspace notifications] for more information.
+		//
+		// [redis keyspace notifications]: https://redis.io/docs/latest/develop/use/keyspace-notifications/
+		NotifyKeyspaceEvents bool
+
+		// AutoPipeline sends the commands of concurrent Get, Set, SetWithTTL, Exists and Del
+		// calls in shared pipelines, split into one round trip per node, which raises the
+		// throughput of many concurrent callers at the price of up to AutoPipelineWindow of
+		// added latency. Queued commands are not canceled with the context of their call.
+		AutoPipeline bool
+
+		// AutoPipelineWindow is how long the first command of a pipeline waits for others.
+		// The default value is 100µs.
+		AutoPipelineWindow time.Duration
+
+		// AutoPipelineMaxSize is the maximum number of commands in a pipeline; a full
+		// pipeline is sent without waiting for the window to elapse.
+		// The default value is 100.
+		AutoPipelineMaxSize int
 	}
 
 	// ClusterOptions is an alias for the [redis.ClusterOptions] type.
@@ -38,4 +93,16 @@ func (c *Config) revise() {
 	if c.CountLimit <= 0 {
 		c.CountLimit = DefaultCountLimit
 	}
+	if c.DelKeysBatchSize <= 0 {
+		c.DelKeysBatchSize = DefaultDelKeysBatchSize
+	}
+	if c.NodeConcurrency <= 0 {
+		c.NodeConcurrency = DefaultNodeConcurrency
+	}
+	if c.AutoPipelineWindow <= 0 {
+		c.AutoPipelineWindow = DefaultAutoPipelineWindow
+	}
+	if c.AutoPipelineMaxSize <= 0 {
+		c.AutoPipelineMaxSize = DefaultAutoPipelineMaxSize
+	}
 }
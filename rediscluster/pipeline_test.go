@@ -0,0 +1,56 @@
+package rediscluster
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bartventer/gocache/internal/batch"
+)
+
+// benchmarkParallel runs op b.N times over many goroutines against a Redis cluster
+// container, with auto-pipelining enabled if autoPipeline is true.
+func benchmarkParallel(b *testing.B, autoPipeline bool, op func(ctx context.Context, r *redisClusterCache[string], key string) error) {
+	ctx := context.Background()
+	r := setupCache[string](b)
+	if autoPipeline {
+		r.config.AutoPipeline = true
+		r.config.revise()
+		r.pipe = batch.NewPipeline(r.client, r.config.AutoPipelineWindow, r.config.AutoPipelineMaxSize)
+	}
+	for i := range 1000 {
+		if err := r.Set(ctx, "key"+strconv.Itoa(i), "value"); err != nil {
+			b.Fatal(err)
+		}
+	}
+	var n atomic.Int64
+	b.SetParallelism(64)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			key := "key" + strconv.FormatInt(n.Add(1)%1000, 10)
+			if err := op(ctx, r, key); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkGet(b *testing.B) {
+	get := func(ctx context.Context, r *redisClusterCache[string], key string) error {
+		_, err := r.Get(ctx, key)
+		return err
+	}
+	b.Run("PerCall", func(b *testing.B) { benchmarkParallel(b, false, get) })
+	b.Run("AutoPipeline", func(b *testing.B) { benchmarkParallel(b, true, get) })
+}
+
+func BenchmarkSet(b *testing.B) {
+	set := func(ctx context.Context, r *redisClusterCache[string], key string) error {
+		return r.Set(ctx, key, "value")
+	}
+	b.Run("PerCall", func(b *testing.B) { benchmarkParallel(b, false, set) })
+	b.Run("AutoPipeline", func(b *testing.B) { benchmarkParallel(b, true, set) })
+}
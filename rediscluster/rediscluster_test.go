@@ -56,7 +56,7 @@ func TestRedisClusterCache_New(t *testing.T) {
 }
 
 // setupRedisCluster creates a new Redis cluster container.
-func setupCache[K driver.String](t *testing.T) *redisClusterCache[K] {
+func setupCache[K driver.String](t testing.TB) *redisClusterCache[K] {
 	t.Helper()
 	// Create a new Redis cluster container
 	ctx := context.Background()
@@ -112,7 +112,9 @@ func setupCache[K driver.String](t *testing.T) *redisClusterCache[K] {
 	if err != nil {
 		t.Fatalf("Failed to ping Redis cluster container: %v", err)
 	}
-	return &redisClusterCache[K]{client: client, config: &Config{CountLimit: 100}}
+	config := &Config{CountLimit: 100, NotifyKeyspaceEvents: true}
+	config.revise()
+	return &redisClusterCache[K]{client: client, config: config}
 }
 
 type harness[K driver.String] struct {
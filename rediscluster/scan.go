@@ -0,0 +1,21 @@
+package rediscluster
+
+import (
+	"context"
+
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/internal/rediskeys"
+	"github.com/bartventer/gocache/pkg/driver"
+)
+
+// Scan implements driver.Scanner.
+//
+// Keys are enumerated with SCAN on every master node concurrently, and their values and
+// TTLs are fetched from the owning node in pipelines of [Config.CountLimit] keys. Keys that
+// do not hold string values are skipped. Calls to fn are serialized.
+func (r *redisClusterCache[K]) Scan(ctx context.Context, pattern K, fn func(driver.Entry[K]) error) error {
+	if err := rediskeys.Scan(ctx, r.client.ForEachMaster, string(pattern), r.config.CountLimit, fn); err != nil {
+		return gcerrors.NewWithScheme(Scheme, err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package rediscluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/bartventer/gocache/pkg/keymod"
+)
+
+// ErrCrossSlot is returned when running a script with keys that hash to different slots,
+// which Redis Cluster cannot run atomically. Use hash tags to put the keys in one slot.
+var ErrCrossSlot = errors.New("rediscluster: keys of a script must hash to the same slot")
+
+// RunScript implements driver.ScriptRunner.
+//
+// The keys must hash to the same slot, so that the script runs on a single node;
+// otherwise an error wrapping [ErrCrossSlot] is returned.
+func (r *redisClusterCache[K]) RunScript(ctx context.Context, script *driver.Script, keys []K, args ...interface{}) (interface{}, error) {
+	skeys := make([]string, len(keys))
+	for i, key := range keys {
+		skeys[i] = string(key)
+		if i > 0 && keymod.Slot(skeys[i]) != keymod.Slot(skeys[0]) {
+			return nil, gcerrors.NewWithScheme(Scheme, fmt.Errorf("error running script %s with keys %s and %s: %w", script.Name, skeys[0], skeys[i], ErrCrossSlot))
+		}
+	}
+	result, err := r.scripts.Run(ctx, r.client, script, skeys, args)
+	if err != nil {
+		return nil, gcerrors.NewWithScheme(Scheme, err)
+	}
+	return result, nil
+}
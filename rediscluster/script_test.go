@@ -0,0 +1,44 @@
+package rediscluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunScript_CrossSlot(t *testing.T) {
+	r := New[string](context.Background(), &Options{ClusterOptions: ClusterOptions{Addrs: []string{"localhost:7000"}}})
+	defer r.Close()
+	script := &driver.Script{Name: "test:mget", Lua: "return redis.call('MGET', unpack(KEYS))"}
+	_, err := r.RunScript(context.Background(), script, []string{"foo", "bar"})
+	require.ErrorIs(t, err, ErrCrossSlot)
+}
+
+func TestRunScript(t *testing.T) {
+	ctx := context.Background()
+	r := setupCache[string](t)
+	c := cache.NewCache[string](r)
+
+	require.NoError(t, c.SetWithTTL(ctx, "lock", "owner1", time.Second))
+	got, err := c.RunScript(ctx, cache.ScriptGetEx, []string{"lock"}, time.Minute.Milliseconds())
+	require.NoError(t, err)
+	assert.Equal(t, "owner1", got)
+	ttl, err := r.client.PTTL(ctx, "lock").Result()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, 50*time.Second)
+
+	got, err = c.RunScript(ctx, cache.ScriptDelIfEqual, []string{"lock"}, "owner1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), got)
+
+	// Keys sharing a hash tag are in the same slot.
+	script := &driver.Script{Name: "test:mset", Lua: "return redis.call('MSET', KEYS[1], ARGV[1], KEYS[2], ARGV[2])"}
+	got, err = r.RunScript(ctx, script, []string{"{user1}:name", "{user1}:email"}, "alice", "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "OK", got)
+}
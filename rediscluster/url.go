@@ -1,6 +1,7 @@
 package rediscluster
 
 import (
+	"fmt"
 	"net/url"
 	"strings"
 	"time"
@@ -18,18 +19,29 @@ var paramKeyBlacklist = map[string]struct{}{
 	"onconnect":                  {},
 	"credentialsprovider":        {},
 	"credentialsprovidercontext": {},
+	urlparser.ParamTLSCAFile:     {},
+	urlparser.ParamTLSCertFile:   {},
+	urlparser.ParamTLSKeyFile:    {},
+	urlparser.ParamTLSServerName: {},
 }
 
 // optionsFromURL parses a [url.URL] into [redis.ClusterOptions].
 //
 // The URL should have the following format:
 //
-//	rediscluster://<host1>:<port1>,<host2>:<port2>,...,<hostN>:<portN>[?query]
+//	rediscluster[s]://[[username]:password@]<host1>:<port1>,<host2>:<port2>,...,<hostN>:<portN>[/0][?query]
+//
+// The credentials are taken from the URL. Redis Cluster only has database 0, so any
+// other database number is rejected. The redisclusters scheme enables TLS, verifying
+// the nodes against the system roots.
 //
 // All cluster options can be set as query parameters, except for the following:
 //   - Addrs
 //   - Any option that is a function
-//   - Any options defined in cache.Options
+//
+// The options defined in [Config] can also be set as query parameters, and TLS can be
+// configured from files with the tlscafile, tlscertfile, tlskeyfile and tlsservername
+// parameters, which also enable it.
 //
 // Example:
 //
@@ -38,7 +50,7 @@ var paramKeyBlacklist = map[string]struct{}{
 // This will return a redis.ClusterOptions with the Addrs set to ["localhost:6379", "localhost:6380"],
 // MaxRetries set to 5, and MinRetryBackoff set to 1000ms.
 func optionsFromURL(u *url.URL) (Options, error) {
-	var opts Options
+	opts := Options{Config: &Config{}}
 
 	// Parse the query parameters into a map
 	parser := urlparser.New(
@@ -55,8 +67,21 @@ func optionsFromURL(u *url.URL) (Options, error) {
 		return Options{}, err
 	}
 
-	// Set the Addrs from the URL
+	// Set the Addrs and credentials from the URL
 	opts.Addrs = strings.Split(u.Host, ",")
+	if u.User != nil {
+		opts.Username = u.User.Username()
+		opts.Password, _ = u.User.Password()
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" && db != "0" {
+		return Options{}, fmt.Errorf("invalid database number %q: Redis Cluster only supports database 0", db)
+	}
+
+	tlsConfig, err := urlparser.TLSConfigFromURL(u, opts.TLSConfig, u.Scheme == SchemeTLS)
+	if err != nil {
+		return Options{}, err
+	}
+	opts.TLSConfig = tlsConfig
 
 	return opts, nil
 }
@@ -0,0 +1,26 @@
+package rediscluster
+
+import (
+	"context"
+
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/internal/keyspace"
+	"github.com/bartventer/gocache/pkg/driver"
+)
+
+// Watch implements driver.Watcher.
+//
+// Watch subscribes to Redis keyspace notifications on every master node, since each node
+// only publishes notifications for the keys it owns. Notifications must be enabled on the
+// servers (see [Config.NotifyKeyspaceEvents]). Set notifications are reported as
+// [driver.EventSet], del, unlink and evicted notifications as [driver.EventDelete], and
+// expired notifications as [driver.EventExpire]; other notifications are ignored. Events
+// are dropped while the channel's buffer of [driver.WatchBufferSize] events is full.
+func (r *redisClusterCache[K]) Watch(ctx context.Context, pattern K) (<-chan driver.Event[K], error) {
+	// Redis Cluster only supports database 0.
+	events, err := keyspace.Watch[K](ctx, r.client.ForEachMaster, 0, string(pattern), r.config.NotifyKeyspaceEvents)
+	if err != nil {
+		return nil, gcerrors.NewWithScheme(Scheme, err)
+	}
+	return events, nil
+}
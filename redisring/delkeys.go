@@ -0,0 +1,23 @@
+package redisring
+
+import (
+	"context"
+
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/pkg/driver"
+)
+
+// DelKeysWithOptions implements [driver.KeysDeleter].
+//
+// The matching keys of each live shard are deleted with UNLINK, which frees their memory
+// in the background, in batches of at most [Config.DelKeysBatchSize] keys as they are
+// scanned, so that neither the process nor the servers hold all of them at once. Servers
+// without UNLINK fall back to DEL. With [driver.DelKeysOptions.MaxKeys], the keys of all
+// shards are counted before any is deleted.
+func (r *redisRingCache[K]) DelKeysWithOptions(ctx context.Context, pattern K, opts driver.DelKeysOptions) (int64, error) {
+	n, err := r.deleter.DeleteAll(ctx, r.client.ForEachShard, string(pattern), opts)
+	if err != nil {
+		return n, gcerrors.NewWithScheme(Scheme, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,62 @@
+package redisring
+
+// Options for the Redis Ring cache.
+
+import (
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultCountLimit is the default value for the [Config.CountLimit] option.
+	DefaultCountLimit = 10
+
+	// DefaultDelKeysBatchSize is the default value for the [Config.DelKeysBatchSize] option.
+	DefaultDelKeysBatchSize = 1000
+)
+
+type (
+	// Config is a configuration for [gocache] to customize the Redis Ring cache.
+	Config struct {
+		// CountLimit is the hint to the SCAN command about the amount of work to be done at each call.
+		// The default value is 10.
+		//
+		// Refer to [redis scan] for more information.
+		//
+		// [redis scan]: https://redis.io/docs/latest/commands/scan/
+		CountLimit int64
+
+		// DelKeysBatchSize is the maximum number of keys deleted by each command of DelKeys,
+		// which deletes the matching keys of each shard in batches as they are scanned.
+		// The default value is 1000.
+		DelKeysBatchSize int
+
+		// NotifyKeyspaceEvents enables the keyspace notifications required by Watch on every
+		// shard, by adding the "Kg$xe" flags to the notify-keyspace-events configuration.
+		// Leave it disabled if notifications are configured on the servers, or if the CONFIG
+		// command is not available.
+		//
+		// Refer to [redis keyspace notifications] for more information.
+		//
+		// [redis keyspace notifications]: https://redis.io/docs/latest/develop/use/keyspace-notifications/
+		NotifyKeyspaceEvents bool
+	}
+
+	// RingOptions is an alias for the [redis.RingOptions] type.
+	RingOptions = redis.RingOptions
+
+	// Options is the configuration for the Redis Ring cache.
+	Options struct {
+		*Config
+		RingOptions
+	}
+)
+
+// revise revises the configuration options to ensure they contain sensible values.
+func (c *Config) revise() {
+	if c.CountLimit <= 0 {
+		c.CountLimit = DefaultCountLimit
+	}
+	if c.DelKeysBatchSize <= 0 {
+		c.DelKeysBatchSize = DefaultDelKeysBatchSize
+	}
+}
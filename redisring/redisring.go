@@ -0,0 +1,250 @@
+/*
+Package redisring provides a Redis Ring implementation of the [driver.Cache] interface.
+It uses the go-redis Ring client, which shards keys across independent Redis servers on
+the client side.
+
+# URL Format:
+
+The URL should have the following format:
+
+	redisring://[[<username>]:<password>@]<name1>=<host1>:<port1>,...,<nameN>=<hostN>:<portN>[?query]
+
+Each <name>=<host>:<port> pair corresponds to a shard. You can specify any number of
+shards, each separated by a comma. Keys are distributed over the shards by consistent
+hashing of their names (rendezvous hashing by default), so a shard keeps its keys when
+its address changes, and adding or removing a shard only moves the keys of that shard.
+A shard given without a name is named after its address.
+
+The optional query part can be used to configure the ring options through query
+parameters. The keys of the query parameters should match the case-insensitive field
+names of the [Options] structure (excluding [redis.RingOptions.Addrs]). The
+tlscafile, tlscertfile, tlskeyfile and tlsservername query parameters configure TLS, as
+with the redis driver.
+
+# Usage
+
+	import (
+	    "context"
+	    "log"
+
+	    cache "github.com/bartventer/gocache"
+	    _ "github.com/bartventer/gocache/redisring"
+	)
+
+	func main() {
+	    ctx := context.Background()
+	    urlStr := "redisring://shard1=localhost:6379,shard2=localhost:6380?heartbeatfrequency=500ms"
+	    c, err := cache.OpenCache(ctx, urlStr)
+	    if err != nil {
+	        log.Fatalf("Failed to initialize cache: %v", err)
+	    }
+	    // ... use c with the cache.Cache interface
+	}
+
+You can create a Redis Ring cache with [New]:
+
+	import (
+	    "context"
+	    "time"
+
+	    "github.com/bartventer/gocache/redisring"
+	)
+
+	func main() {
+	    ctx := context.Background()
+	    c := redisring.New[string](ctx, &redisring.Options{
+	        RingOptions: redisring.RingOptions{
+	            Addrs: map[string]string{
+	                "shard1": "localhost:6379",
+	                "shard2": "localhost:6380",
+	            },
+	            HeartbeatFrequency: 500 * time.Millisecond,
+	        },
+	    })
+	    // ... use c with the cache.Cache interface
+	}
+
+# Shards
+
+The ring pings every shard each [redis.RingOptions.HeartbeatFrequency], and stops
+routing keys to a shard that fails to respond, until it does again. Keys of a shard
+that is down are therefore read from and written to another shard meanwhile.
+
+Count, DelKeys, Clear, Scan and Watch operate on every live shard concurrently.
+
+# Watching Keys
+
+Watch is implemented with Redis [keyspace notifications], subscribing to every live
+shard. Notifications are disabled by default on the servers; set
+[Config.NotifyKeyspaceEvents] (or the notifykeyspaceevents query parameter) to have
+them enabled on all shards when Watch is called:
+
+	redisring://shard1=localhost:6379,shard2=localhost:6380?notifykeyspaceevents=true
+
+[keyspace notifications]: https://redis.io/docs/latest/develop/use/keyspace-notifications/
+*/
+package redisring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/internal/rediskeys"
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/bartventer/gocache/pkg/keymod"
+	"github.com/redis/go-redis/v9"
+)
+
+// Scheme is the cache scheme for Redis Ring.
+const Scheme = "redisring"
+
+func init() { //nolint:gochecknoinits // This is the entry point of the package.
+	cache.RegisterCache(Scheme, &redisRingCache[string]{})
+	cache.RegisterCache(Scheme, &redisRingCache[keymod.Key]{})
+}
+
+// redisRingCache is a Redis Ring implementation of the cache.Cache interface.
+type redisRingCache[K driver.String] struct {
+	once   sync.Once   // once ensures that the cache is initialized only once.
+	client *redis.Ring // client is the Redis Ring client.
+	config *Config     // config is the cache configuration.
+
+	deleter rediskeys.Deleter // deleter deletes the keys matching a pattern.
+}
+
+// New returns a new Redis Ring cache implementation.
+func New[K driver.String](ctx context.Context, opts *Options) *redisRingCache[K] {
+	r := &redisRingCache[K]{}
+	if opts == nil {
+		opts = &Options{}
+	}
+	r.init(ctx, opts.Config, &opts.RingOptions)
+	return r
+}
+
+// Ensure RedisRingCache implements the cache.Cache interface.
+var _ driver.Cache[string] = new(redisRingCache[string])
+var _ driver.Cache[keymod.Key] = new(redisRingCache[keymod.Key])
+var _ driver.Watcher[string] = new(redisRingCache[string])
+var _ driver.Watcher[keymod.Key] = new(redisRingCache[keymod.Key])
+var _ driver.Scanner[string] = new(redisRingCache[string])
+var _ driver.Scanner[keymod.Key] = new(redisRingCache[keymod.Key])
+var _ driver.KeysDeleter[string] = new(redisRingCache[string])
+var _ driver.KeysDeleter[keymod.Key] = new(redisRingCache[keymod.Key])
+
+// OpenCacheURL implements [cache.URLOpener].
+func (r *redisRingCache[K]) OpenCacheURL(ctx context.Context, u *url.URL) (*cache.GenericCache[K], error) {
+	opts, err := optionsFromURL(u)
+	if err != nil {
+		return nil, gcerrors.NewWithScheme(Scheme, fmt.Errorf("error parsing URL: %w", err))
+	}
+	r.init(ctx, opts.Config, &opts.RingOptions)
+	return cache.NewCache(r), nil
+}
+
+func (r *redisRingCache[K]) init(_ context.Context, config *Config, options *redis.RingOptions) {
+	r.once.Do(func() {
+		if config == nil {
+			config = &Config{}
+		}
+		config.revise()
+		r.config = config
+		r.deleter.Count = config.CountLimit
+		r.deleter.BatchSize = config.DelKeysBatchSize
+		r.client = redis.NewRing(options)
+	})
+}
+
+// Count implements cache.Cache.
+func (r *redisRingCache[K]) Count(ctx context.Context, pattern K) (int64, error) {
+	var count atomic.Int64
+	err := r.client.ForEachShard(ctx, func(ctx context.Context, client *redis.Client) error {
+		iter := client.Scan(ctx, 0, string(pattern), r.config.CountLimit).Iterator()
+		for iter.Next(ctx) {
+			count.Add(1)
+		}
+		return iter.Err()
+	})
+	if err != nil {
+		return 0, gcerrors.NewWithScheme(Scheme, fmt.Errorf("error counting keys: %w", err))
+	}
+	return count.Load(), nil
+}
+
+// Exists implements cache.Cache.
+func (r *redisRingCache[K]) Exists(ctx context.Context, key K) (bool, error) {
+	n, err := r.client.Exists(ctx, string(key)).Result()
+	if err != nil {
+		return false, gcerrors.NewWithScheme(Scheme, fmt.Errorf("error checking key %s: %w", key, err))
+	}
+	return n > 0, nil
+}
+
+// Del implements cache.Cache.
+func (r *redisRingCache[K]) Del(ctx context.Context, key K) error {
+	delCount, err := r.client.Del(ctx, string(key)).Result()
+	if err != nil {
+		return gcerrors.NewWithScheme(Scheme, fmt.Errorf("error deleting key %s: %w", key, err))
+	}
+	if delCount == 0 {
+		return gcerrors.NewWithScheme(Scheme, errors.Join(cache.ErrKeyNotFound, fmt.Errorf("key %s not found", key)))
+	}
+	return nil
+}
+
+// DelKeys implements cache.Cache.
+//
+// Keys are deleted in batches as they are scanned; see [redisRingCache.DelKeysWithOptions].
+func (r *redisRingCache[K]) DelKeys(ctx context.Context, pattern K) error {
+	_, err := r.DelKeysWithOptions(ctx, pattern, driver.DelKeysOptions{})
+	return err
+}
+
+// Clear implements cache.Cache.
+func (r *redisRingCache[K]) Clear(ctx context.Context) error {
+	return r.client.ForEachShard(ctx, func(ctx context.Context, client *redis.Client) error {
+		return client.FlushDB(ctx).Err()
+	})
+}
+
+// Get implements cache.Cache.
+func (r *redisRingCache[K]) Get(ctx context.Context, key K) ([]byte, error) {
+	val, err := r.client.Get(ctx, string(key)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, gcerrors.NewWithScheme(Scheme, errors.Join(cache.ErrKeyNotFound, fmt.Errorf("key %s not found: %w", key, err)))
+		} else {
+			return nil, gcerrors.NewWithScheme(Scheme, fmt.Errorf("error getting key %s: %w", key, err))
+		}
+	}
+	return val, nil
+}
+
+// Set implements cache.Cache.
+func (r *redisRingCache[K]) Set(ctx context.Context, key K, value interface{}) error {
+	return r.client.Set(ctx, string(key), value, 0).Err()
+}
+
+// SetWithTTL implements cache.Cache.
+func (r *redisRingCache[K]) SetWithTTL(ctx context.Context, key K, value interface{}, ttl time.Duration) error {
+	return r.client.Set(ctx, string(key), value, ttl).Err()
+}
+
+// Ping implements cache.Cache.
+func (r *redisRingCache[K]) Ping(ctx context.Context) error {
+	return r.client.ForEachShard(ctx, func(ctx context.Context, client *redis.Client) error {
+		return client.Ping(ctx).Err()
+	})
+}
+
+// Close implements cache.Cache.
+func (r *redisRingCache[K]) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,169 @@
+package redisring
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/bartventer/gocache/pkg/drivertest"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// Defines the default Redis port and the addresses of the shards of the test ring.
+const (
+	defaultPort = "6379"
+	shard1Addr  = "localhost:6379"
+	shard2Addr  = "localhost:6380"
+)
+
+func TestRedisRingCache_OpenCacheURL(t *testing.T) {
+	r := &redisRingCache[string]{}
+
+	u, err := url.Parse("redisring://shard1=" + shard1Addr + ",shard2=" + shard2Addr + "?heartbeatfrequency=500ms&db=2")
+	require.NoError(t, err)
+
+	_, err = r.OpenCacheURL(context.Background(), u)
+	require.NoError(t, err)
+	assert.NotNil(t, r.client)
+	assert.Equal(t, 2, r.client.Options().DB)
+	assert.Len(t, r.client.Options().Addrs, 2)
+}
+
+func TestRedisRingCache_New(t *testing.T) {
+	ctx := context.Background()
+	r := New[string](ctx, &Options{
+		RingOptions: RingOptions{
+			Addrs: map[string]string{
+				"shard1": shard1Addr,
+				"shard2": shard2Addr,
+			},
+		},
+	})
+	require.NotNil(t, r)
+	assert.NotNil(t, r.client)
+	assert.Equal(t, int64(DefaultCountLimit), r.config.CountLimit)
+}
+
+// startShard starts a Redis container to serve as a shard of the ring, and returns its
+// endpoint.
+func startShard(t *testing.T) string {
+	t.Helper()
+	ctx := context.Background()
+	req := testcontainers.ContainerRequest{
+		Image:        "redis:alpine",
+		ExposedPorts: []string{defaultPort},
+		WaitingFor:   wait.ForLog("Ready to accept connections"),
+		Tmpfs:        map[string]string{"/data": "rw"},
+	}
+	redisC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start Redis container: %v", err)
+	}
+	t.Cleanup(func() {
+		if cleanupErr := redisC.Terminate(ctx); cleanupErr != nil {
+			t.Fatalf("Failed to terminate Redis container: %v", cleanupErr)
+		}
+	})
+	endpoint, err := redisC.Endpoint(ctx, "")
+	if err != nil {
+		t.Fatalf("Failed to get Redis container endpoint: %v", err)
+	}
+	return endpoint
+}
+
+// setupCache creates a new Redis Ring cache with two shards, each a test container.
+func setupCache[K driver.String](t *testing.T) *redisRingCache[K] {
+	t.Helper()
+	// Create a new Redis Ring cache
+	client := redis.NewRing(&redis.RingOptions{
+		Addrs: map[string]string{
+			"shard1": startShard(t),
+			"shard2": startShard(t),
+		},
+		MaxRetries:      5,
+		MinRetryBackoff: 1000 * time.Millisecond,
+	})
+	t.Cleanup(func() {
+		client.Close()
+	})
+	err := client.Ping(context.Background()).Err()
+	if err != nil {
+		t.Fatalf("Failed to ping Redis Ring: %v", err)
+	}
+	config := &Config{CountLimit: 100, NotifyKeyspaceEvents: true}
+	config.revise()
+	return &redisRingCache[K]{client: client, config: config}
+}
+
+func TestShards(t *testing.T) {
+	ctx := context.Background()
+	r := setupCache[string](t)
+	for i := range 100 {
+		require.NoError(t, r.Set(ctx, fmt.Sprintf("key:%d", i), "value"))
+	}
+
+	// Keys are spread over both shards, and pattern operations see all of them.
+	var (
+		mu    sync.Mutex
+		sizes []int64
+	)
+	require.NoError(t, r.client.ForEachShard(ctx, func(ctx context.Context, client *redis.Client) error {
+		n, err := client.DBSize(ctx).Result()
+		mu.Lock()
+		defer mu.Unlock()
+		sizes = append(sizes, n)
+		return err
+	}))
+	require.Len(t, sizes, 2)
+	assert.Positive(t, sizes[0])
+	assert.Positive(t, sizes[1])
+
+	n, err := r.Count(ctx, "key:*")
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), n)
+	require.NoError(t, r.DelKeys(ctx, "key:*"))
+	n, err = r.Count(ctx, "key:*")
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+type harness[K driver.String] struct {
+	cache *redisRingCache[K]
+}
+
+func (h *harness[K]) MakeCache(ctx context.Context) (driver.Cache[K], error) {
+	return h.cache, nil
+}
+
+func (h *harness[K]) Close() {
+	// Cleanup is handled in setup function
+}
+
+func (h *harness[K]) Options() drivertest.Options {
+	return drivertest.Options{
+		PatternMatchingDisabled: false,
+		CloseIsNoop:             false,
+	}
+}
+
+func newHarness[K driver.String](ctx context.Context, t *testing.T) (drivertest.Harness[K], error) {
+	cache := setupCache[K](t)
+	return &harness[K]{
+		cache: cache,
+	}, nil
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness[string])
+}
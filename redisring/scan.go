@@ -0,0 +1,21 @@
+package redisring
+
+import (
+	"context"
+
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/internal/rediskeys"
+	"github.com/bartventer/gocache/pkg/driver"
+)
+
+// Scan implements driver.Scanner.
+//
+// Keys are enumerated with SCAN on every live shard concurrently, and their values and
+// TTLs are fetched from the shard in pipelines of [Config.CountLimit] keys. Keys that do
+// not hold string values are skipped. Calls to fn are serialized.
+func (r *redisRingCache[K]) Scan(ctx context.Context, pattern K, fn func(driver.Entry[K]) error) error {
+	if err := rediskeys.Scan(ctx, r.client.ForEachShard, string(pattern), r.config.CountLimit, fn); err != nil {
+		return gcerrors.NewWithScheme(Scheme, err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package redisring
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bartventer/gocache/internal/urlparser"
+	"github.com/mitchellh/mapstructure"
+)
+
+// paramKeyBlacklist is a list of keys that should not be set on the Redis Ring options.
+var paramKeyBlacklist = map[string]struct{}{
+	"addrs":                      {},
+	"newclient":                  {},
+	"newconsistenthash":          {},
+	"dialer":                     {},
+	"onconnect":                  {},
+	urlparser.ParamTLSCAFile:     {},
+	urlparser.ParamTLSCertFile:   {},
+	urlparser.ParamTLSKeyFile:    {},
+	urlparser.ParamTLSServerName: {},
+}
+
+// optionsFromURL parses a [url.URL] into [redis.RingOptions].
+//
+// The URL should have the following format:
+//
+//	redisring://[[username]:password@]<name1>=<host1>:<port1>,...,<nameN>=<hostN>:<portN>[?query]
+//
+// Each shard is named; the names, not the addresses, decide which shard a key is stored
+// on. A shard given without a name is named after its address.
+//
+// All ring options can be set as query parameters, except for the following:
+//   - [redis.RingOptions.Addrs]
+//   - Any option that is a function
+//
+// The options defined in [Config] can also be set as query parameters, and TLS can be
+// configured from files with the tlscafile, tlscertfile, tlskeyfile and tlsservername
+// parameters, which also enable it.
+//
+// Example:
+//
+//	redisring://shard1=localhost:6379,shard2=localhost:6380?heartbeatfrequency=500ms
+//
+// This will return a redis.RingOptions with the Addrs set to
+// {"shard1": "localhost:6379", "shard2": "localhost:6380"} and HeartbeatFrequency set
+// to 500ms.
+func optionsFromURL(u *url.URL) (Options, error) {
+	opts := Options{Config: &Config{}}
+
+	// Parse the query parameters into a map
+	parser := urlparser.New(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		mapstructure.StringToTimeHookFunc(time.RFC3339),
+		mapstructure.StringToIPNetHookFunc(),
+		mapstructure.StringToIPHookFunc(),
+		mapstructure.RecursiveStructToMapHookFunc(),
+		urlparser.StringToTLSConfigHookFunc(),
+		urlparser.StringToCertificateHookFunc(),
+	)
+	if err := parser.OptionsFromURL(u, &opts, paramKeyBlacklist); err != nil {
+		return Options{}, err
+	}
+
+	// Set the shards and credentials from the URL
+	if u.Host == "" {
+		return Options{}, errors.New("missing shards")
+	}
+	opts.Addrs = make(map[string]string)
+	for _, shard := range strings.Split(u.Host, ",") {
+		name, addr, ok := strings.Cut(shard, "=")
+		if !ok {
+			name, addr = shard, shard
+		}
+		if name == "" || addr == "" {
+			return Options{}, fmt.Errorf("invalid shard %q", shard)
+		}
+		if _, dup := opts.Addrs[name]; dup {
+			return Options{}, fmt.Errorf("duplicate shard name %q", name)
+		}
+		opts.Addrs[name] = addr
+	}
+	if u.User != nil {
+		opts.Username = u.User.Username()
+		opts.Password, _ = u.User.Password()
+	}
+
+	tlsConfig, err := urlparser.TLSConfigFromURL(u, opts.TLSConfig, false)
+	if err != nil {
+		return Options{}, err
+	}
+	opts.TLSConfig = tlsConfig
+
+	return opts, nil
+}
@@ -0,0 +1,229 @@
+/*
+Package redissentinel provides a Redis Sentinel implementation of the [driver.Cache] interface.
+It uses the go-redis failover client, which asks the sentinels for the address of the
+current master and reconnects to the new master after a failover.
+
+# URL Format:
+
+The URL should have the following format:
+
+	redissentinel://<host1>:<port1>,<host2>:<port2>,...,<hostN>:<portN>?mastername=<name>[&query]
+
+Each <host>:<port> pair corresponds to a Redis Sentinel. You can specify any number of
+sentinels, each separated by a comma. The mastername query parameter is the name of the
+master monitored by the sentinels, and is required.
+
+The optional query part can be used to configure the failover options through query
+parameters. The keys of the query parameters should match the case-insensitive field
+names of the [Options] structure (excluding [redis.FailoverOptions.SentinelAddrs]).
+
+# Usage
+
+	import (
+	    "context"
+	    "log"
+
+	    cache "github.com/bartventer/gocache"
+	    _ "github.com/bartventer/gocache/redissentinel"
+	)
+
+	func main() {
+	    ctx := context.Background()
+	    urlStr := "redissentinel://localhost:26379,localhost:26380?mastername=mymaster&db=2"
+	    c, err := cache.OpenCache(ctx, urlStr)
+	    if err != nil {
+	        log.Fatalf("Failed to initialize cache: %v", err)
+	    }
+	    // ... use c with the cache.Cache interface
+	}
+
+You can create a Redis Sentinel cache with [New]:
+
+	import (
+	    "context"
+
+	    "github.com/bartventer/gocache/redissentinel"
+	)
+
+	func main() {
+	    ctx := context.Background()
+	    c := redissentinel.New[string](ctx, &redissentinel.Options{
+	        FailoverOptions: redissentinel.FailoverOptions{
+	            MasterName:    "mymaster",
+	            SentinelAddrs: []string{"localhost:26379", "localhost:26380"},
+	            DB:            2,
+	        },
+	    })
+	    // ... use c with the cache.Cache interface
+	}
+
+# Watching Keys
+
+Watch is implemented with Redis [keyspace notifications] on the master. Notifications
+are disabled by default on the server; set [Config.NotifyKeyspaceEvents] (or the
+notifykeyspaceevents query parameter) to have them enabled when Watch is called:
+
+	redissentinel://localhost:26379?mastername=mymaster&notifykeyspaceevents=true
+
+The configuration is not replicated, so after a failover the new master only emits
+notifications if they are configured on it too.
+
+[keyspace notifications]: https://redis.io/docs/latest/develop/use/keyspace-notifications/
+*/
+package redissentinel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	cache "github.com/bartventer/gocache"
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/internal/rediskeys"
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/bartventer/gocache/pkg/keymod"
+	"github.com/redis/go-redis/v9"
+)
+
+// Scheme is the cache scheme for Redis Sentinel.
+const Scheme = "redissentinel"
+
+func init() { //nolint:gochecknoinits // This is the entry point of the package.
+	cache.RegisterCache(Scheme, &redisCache[string]{})
+	cache.RegisterCache(Scheme, &redisCache[keymod.Key]{})
+}
+
+// redisCache is a Redis Sentinel implementation of the cache.Cache interface.
+type redisCache[K driver.String] struct {
+	once   sync.Once     // once ensures that the cache is initialized only once.
+	client *redis.Client // client is the Redis failover client.
+	config *Config       // config is the cache configuration.
+
+	deleter rediskeys.Deleter // deleter deletes the keys matching a pattern.
+}
+
+// New returns a new Redis Sentinel cache implementation.
+func New[K driver.String](ctx context.Context, opts *Options) *redisCache[K] {
+	r := &redisCache[K]{}
+	if opts == nil {
+		opts = &Options{}
+	}
+	r.init(ctx, opts.Config, &opts.FailoverOptions)
+	return r
+}
+
+// Ensure RedisCache implements the cache.Cache interface.
+var _ driver.Cache[string] = new(redisCache[string])
+var _ driver.Cache[keymod.Key] = new(redisCache[keymod.Key])
+var _ driver.Watcher[string] = new(redisCache[string])
+var _ driver.Watcher[keymod.Key] = new(redisCache[keymod.Key])
+var _ driver.Scanner[string] = new(redisCache[string])
+var _ driver.Scanner[keymod.Key] = new(redisCache[keymod.Key])
+var _ driver.KeysDeleter[string] = new(redisCache[string])
+var _ driver.KeysDeleter[keymod.Key] = new(redisCache[keymod.Key])
+
+// OpenCacheURL implements [cache.URLOpener].
+func (r *redisCache[K]) OpenCacheURL(ctx context.Context, u *url.URL) (*cache.GenericCache[K], error) {
+	opts, err := optionsFromURL(u)
+	if err != nil {
+		return nil, gcerrors.NewWithScheme(Scheme, fmt.Errorf("error parsing URL: %w", err))
+	}
+	r.init(ctx, opts.Config, &opts.FailoverOptions)
+	return cache.NewCache(r), nil
+}
+
+func (r *redisCache[K]) init(_ context.Context, config *Config, options *redis.FailoverOptions) {
+	r.once.Do(func() {
+		if config == nil {
+			config = &Config{}
+		}
+		config.revise()
+		r.config = config
+		r.deleter.Count = config.CountLimit
+		r.deleter.BatchSize = config.DelKeysBatchSize
+		r.client = redis.NewFailoverClient(options)
+	})
+}
+
+// Count implements cache.Cache.
+func (r *redisCache[K]) Count(ctx context.Context, pattern K) (int64, error) {
+	var count int64
+	iter := r.client.Scan(ctx, 0, string(pattern), r.config.CountLimit).Iterator()
+	for iter.Next(ctx) {
+		count++
+	}
+	if err := iter.Err(); err != nil {
+		return 0, gcerrors.NewWithScheme(Scheme, fmt.Errorf("error counting keys: %w", err))
+	}
+	return count, nil
+}
+
+// Exists implements cache.Cache.
+func (r *redisCache[K]) Exists(ctx context.Context, key K) (bool, error) {
+	n, err := r.client.Exists(ctx, string(key)).Result()
+	if err != nil {
+		return false, gcerrors.NewWithScheme(Scheme, fmt.Errorf("error checking key %s: %w", key, err))
+	}
+	return n > 0, nil
+}
+
+// Del implements cache.Cache.
+func (r *redisCache[K]) Del(ctx context.Context, key K) error {
+	delCount, err := r.client.Del(ctx, string(key)).Result()
+	if err != nil {
+		return gcerrors.NewWithScheme(Scheme, fmt.Errorf("error deleting key %s: %w", key, err))
+	}
+	if delCount == 0 {
+		return gcerrors.NewWithScheme(Scheme, errors.Join(cache.ErrKeyNotFound, fmt.Errorf("key %s not found", key)))
+	}
+	return nil
+}
+
+// DelKeys implements cache.Cache.
+//
+// Keys are deleted in batches as they are scanned; see [redisCache.DelKeysWithOptions].
+func (r *redisCache[K]) DelKeys(ctx context.Context, pattern K) error {
+	_, err := r.DelKeysWithOptions(ctx, pattern, driver.DelKeysOptions{})
+	return err
+}
+
+// Clear implements cache.Cache.
+func (r *redisCache[K]) Clear(ctx context.Context) error {
+	return r.client.FlushDB(ctx).Err()
+}
+
+// Get implements cache.Cache.
+func (r *redisCache[K]) Get(ctx context.Context, key K) ([]byte, error) {
+	val, err := r.client.Get(ctx, string(key)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, gcerrors.NewWithScheme(Scheme, errors.Join(cache.ErrKeyNotFound, fmt.Errorf("key %s not found: %w", key, err)))
+		} else {
+			return nil, gcerrors.NewWithScheme(Scheme, fmt.Errorf("error getting key %s: %w", key, err))
+		}
+	}
+	return val, nil
+}
+
+// Set implements cache.Cache.
+func (r *redisCache[K]) Set(ctx context.Context, key K, value interface{}) error {
+	return r.client.Set(ctx, string(key), value, 0).Err()
+}
+
+// SetWithTTL implements cache.Cache.
+func (r *redisCache[K]) SetWithTTL(ctx context.Context, key K, value interface{}, ttl time.Duration) error {
+	return r.client.Set(ctx, string(key), value, ttl).Err()
+}
+
+// Close implements cache.Cache.
+func (r *redisCache[K]) Close() error {
+	return r.client.Close()
+}
+
+// Ping implements cache.Cache.
+func (r *redisCache[K]) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
@@ -0,0 +1,145 @@
+package redissentinel
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/bartventer/gocache/pkg/drivertest"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// Defines the Redis and Sentinel network addresses inside the test container.
+const (
+	masterName   = "mymaster"
+	redisPort    = "6379"
+	sentinelPort = "26379"
+	masterAddr   = "127.0.0.1:" + redisPort
+	sentinelAddr = "localhost:" + sentinelPort
+)
+
+// sentinelScript starts a Redis server and a Sentinel monitoring it in the same container.
+const sentinelScript = `redis-server --port ` + redisPort + ` --daemonize yes &&
+printf 'port ` + sentinelPort + `\nsentinel monitor ` + masterName + ` 127.0.0.1 ` + redisPort + ` 1\n' > /tmp/sentinel.conf &&
+exec redis-sentinel /tmp/sentinel.conf`
+
+func TestRedisSentinelCache_OpenCacheURL(t *testing.T) {
+	r := &redisCache[string]{}
+
+	u, err := url.Parse("redissentinel://" + sentinelAddr + "?mastername=" + masterName + "&db=2")
+	require.NoError(t, err)
+
+	_, err = r.OpenCacheURL(context.Background(), u)
+	require.NoError(t, err)
+	assert.NotNil(t, r.client)
+	assert.Equal(t, 2, r.client.Options().DB)
+}
+
+func TestRedisSentinelCache_New(t *testing.T) {
+	ctx := context.Background()
+	r := New[string](ctx, &Options{
+		FailoverOptions: FailoverOptions{
+			MasterName:    masterName,
+			SentinelAddrs: []string{sentinelAddr},
+		},
+	})
+	require.NotNil(t, r)
+	assert.NotNil(t, r.client)
+}
+
+// setupCache creates a new Redis Sentinel cache with a test container.
+func setupCache[K driver.String](t *testing.T) *redisCache[K] {
+	t.Helper()
+	// Create a new container running a Redis master and a Sentinel
+	ctx := context.Background()
+	req := testcontainers.ContainerRequest{
+		Image:        "redis:alpine",
+		ExposedPorts: []string{redisPort, sentinelPort},
+		Cmd:          []string{"sh", "-c", sentinelScript},
+		WaitingFor:   wait.ForLog("+monitor master " + masterName),
+		Tmpfs:        map[string]string{"/data": "rw"},
+	}
+	redisC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start Redis Sentinel container: %v", err)
+	}
+	t.Cleanup(func() {
+		if cleanupErr := redisC.Terminate(ctx); cleanupErr != nil {
+			t.Fatalf("Failed to terminate Redis Sentinel container: %v", cleanupErr)
+		}
+	})
+	// Get the container endpoints
+	masterEndpoint, err := redisC.PortEndpoint(ctx, redisPort, "")
+	if err != nil {
+		t.Fatalf("Failed to get Redis container endpoint: %v", err)
+	}
+	sentinelEndpoint, err := redisC.PortEndpoint(ctx, sentinelPort, "")
+	if err != nil {
+		t.Fatalf("Failed to get Sentinel container endpoint: %v", err)
+	}
+	// The Sentinel reports the master address inside the container, which is mapped to a
+	// different port on the host.
+	var dialer net.Dialer
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if addr == masterAddr {
+			addr = masterEndpoint
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+	// Create a new Redis Sentinel cache
+	client := redis.NewFailoverClient(&redis.FailoverOptions{
+		MasterName:      masterName,
+		SentinelAddrs:   []string{sentinelEndpoint},
+		Dialer:          dial,
+		MaxRetries:      5,
+		MinRetryBackoff: 1000 * time.Millisecond,
+	})
+	t.Cleanup(func() {
+		client.Close()
+	})
+	err = client.Ping(context.Background()).Err()
+	if err != nil {
+		t.Fatalf("Failed to ping Redis master: %v", err)
+	}
+	return &redisCache[K]{client: client, config: &Config{CountLimit: 100, NotifyKeyspaceEvents: true}}
+}
+
+type harness[K driver.String] struct {
+	cache *redisCache[K]
+}
+
+func (h *harness[K]) MakeCache(ctx context.Context) (driver.Cache[K], error) {
+	return h.cache, nil
+}
+
+func (h *harness[K]) Close() {
+	// Cleanup is handled in setup function
+}
+
+func (h *harness[K]) Options() drivertest.Options {
+	return drivertest.Options{
+		PatternMatchingDisabled: false,
+		CloseIsNoop:             false,
+	}
+}
+
+func newHarness[K driver.String](ctx context.Context, t *testing.T) (drivertest.Harness[K], error) {
+	cache := setupCache[K](t)
+	return &harness[K]{
+		cache: cache,
+	}, nil
+}
+
+func TestConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness[string])
+}
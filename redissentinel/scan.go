@@ -0,0 +1,21 @@
+package redissentinel
+
+import (
+	"context"
+
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/internal/keyspace"
+	"github.com/bartventer/gocache/internal/rediskeys"
+	"github.com/bartventer/gocache/pkg/driver"
+)
+
+// Scan implements driver.Scanner.
+//
+// Keys are enumerated with SCAN on the master, and their values and TTLs are fetched in
+// pipelines of [Config.CountLimit] keys. Keys that do not hold string values are skipped.
+func (r *redisCache[K]) Scan(ctx context.Context, pattern K, fn func(driver.Entry[K]) error) error {
+	if err := rediskeys.Scan(ctx, keyspace.Single(r.client), string(pattern), r.config.CountLimit, fn); err != nil {
+		return gcerrors.NewWithScheme(Scheme, err)
+	}
+	return nil
+}
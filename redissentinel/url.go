@@ -0,0 +1,64 @@
+package redissentinel
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bartventer/gocache/internal/urlparser"
+	"github.com/mitchellh/mapstructure"
+)
+
+// paramKeyBlacklist is a list of keys that should not be set on the Redis Sentinel options.
+var paramKeyBlacklist = map[string]struct{}{
+	"sentineladdrs": {},
+	"dialer":        {},
+	"onconnect":     {},
+}
+
+// optionsFromURL parses a [url.URL] into [redis.FailoverOptions].
+//
+// The URL should have the following format:
+//
+//	redissentinel://<host1>:<port1>,<host2>:<port2>,...,<hostN>:<portN>?mastername=<name>[&query]
+//
+// All failover options can be set as query parameters, except for the following:
+//   - SentinelAddrs
+//   - Any option that is a function
+//
+// The mastername parameter is required. The options defined in [Config] can also be set
+// as query parameters.
+//
+// Example:
+//
+//	redissentinel://localhost:26379,localhost:26380?mastername=mymaster&db=2
+//
+// This will return a redis.FailoverOptions with the SentinelAddrs set to
+// ["localhost:26379", "localhost:26380"], MasterName set to "mymaster" and DB set to 2.
+func optionsFromURL(u *url.URL) (Options, error) {
+	opts := Options{Config: &Config{}}
+
+	// Parse the query parameters into a map
+	parser := urlparser.New(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		mapstructure.StringToTimeHookFunc(time.RFC3339),
+		mapstructure.StringToIPNetHookFunc(),
+		mapstructure.StringToIPHookFunc(),
+		mapstructure.RecursiveStructToMapHookFunc(),
+		urlparser.StringToTLSConfigHookFunc(),
+		urlparser.StringToCertificateHookFunc(),
+	)
+	if err := parser.OptionsFromURL(u, &opts, paramKeyBlacklist); err != nil {
+		return Options{}, err
+	}
+	if opts.MasterName == "" {
+		return Options{}, errors.New("missing mastername parameter")
+	}
+
+	// Set the SentinelAddrs from the URL
+	opts.SentinelAddrs = strings.Split(u.Host, ",")
+
+	return opts, nil
+}
@@ -0,0 +1,126 @@
+package redissentinel
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/redis/go-redis/v9"
+)
+
+func Test_optionsFromURL(t *testing.T) {
+	type args struct {
+		u *url.URL
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Options
+		wantErr bool
+	}{
+		{
+			name: "parses valid URL",
+			args: args{
+				u: mustParseURL("redissentinel://localhost:26379,localhost:26380?mastername=mymaster&db=2&maxretries=5&minretrybackoff=512ms"),
+			},
+			want: Options{
+				Config: &Config{},
+				FailoverOptions: redis.FailoverOptions{
+					MasterName:      "mymaster",
+					SentinelAddrs:   []string{"localhost:26379", "localhost:26380"},
+					DB:              2,
+					MaxRetries:      5,
+					MinRetryBackoff: 512 * time.Millisecond,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parses sentinel credentials and routing",
+			args: args{
+				u: mustParseURL("redissentinel://localhost:26379?mastername=mymaster&sentinelpassword=secret&routebylatency=true"),
+			},
+			want: Options{
+				Config: &Config{},
+				FailoverOptions: redis.FailoverOptions{
+					MasterName:       "mymaster",
+					SentinelAddrs:    []string{"localhost:26379"},
+					SentinelPassword: "secret",
+					RouteByLatency:   true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ignores blacklisted parameters",
+			args: args{
+				u: mustParseURL("redissentinel://localhost:26379?mastername=mymaster&sentineladdrs=someotherhost:26379"),
+			},
+			want: Options{
+				Config: &Config{},
+				FailoverOptions: redis.FailoverOptions{
+					MasterName:    "mymaster",
+					SentinelAddrs: []string{"localhost:26379"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parses config parameters",
+			args: args{
+				u: mustParseURL("redissentinel://localhost:26379?mastername=mymaster&countlimit=50&delkeysbatchsize=500&notifykeyspaceevents=true"),
+			},
+			want: Options{
+				Config: &Config{
+					CountLimit:           50,
+					DelKeysBatchSize:     500,
+					NotifyKeyspaceEvents: true,
+				},
+				FailoverOptions: redis.FailoverOptions{
+					MasterName:    "mymaster",
+					SentinelAddrs: []string{"localhost:26379"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "returns error for missing master name",
+			args: args{
+				u: mustParseURL("redissentinel://localhost:26379?db=2"),
+			},
+			want:    Options{},
+			wantErr: true,
+		},
+		{
+			name: "returns error for invalid parameters",
+			args: args{
+				u: mustParseURL("redissentinel://localhost:26379?mastername=mymaster&maxretries=invalid"),
+			},
+			want:    Options{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := optionsFromURL(tt.args.u)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("optionsFromURL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreUnexported(redis.FailoverOptions{})); diff != "" {
+				t.Errorf("optionsFromURL() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func mustParseURL(s string) *url.URL {
+	u, err := url.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
@@ -0,0 +1,24 @@
+package redissentinel
+
+import (
+	"context"
+
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/internal/keyspace"
+	"github.com/bartventer/gocache/pkg/driver"
+)
+
+// Watch implements driver.Watcher.
+//
+// Watch subscribes to Redis keyspace notifications on the master, which must be enabled
+// on it (see [Config.NotifyKeyspaceEvents]). Set notifications are reported as
+// [driver.EventSet], del, unlink and evicted notifications as [driver.EventDelete], and
+// expired notifications as [driver.EventExpire]; other notifications are ignored. Events
+// are dropped while the channel's buffer of [driver.WatchBufferSize] events is full.
+func (r *redisCache[K]) Watch(ctx context.Context, pattern K) (<-chan driver.Event[K], error) {
+	events, err := keyspace.Watch[K](ctx, keyspace.Single(r.client), r.client.Options().DB, string(pattern), r.config.NotifyKeyspaceEvents)
+	if err != nil {
+		return nil, gcerrors.NewWithScheme(Scheme, err)
+	}
+	return events, nil
+}
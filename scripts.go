@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bartventer/gocache/internal/gcerrors"
+	"github.com/bartventer/gocache/pkg/driver"
+)
+
+// Script is an operation run atomically by a cache. See [RegisterScript].
+type Script = driver.Script
+
+// Names of the built-in scripts.
+const (
+	// ScriptGetEx gets the value of a key and sets its time-to-live, in milliseconds.
+	// It's run with the key and the time-to-live, and returns the value, or nil if the
+	// key does not exist. Like the Redis PEXPIRE command, a time-to-live that is not
+	// positive removes the key after its value is read.
+	ScriptGetEx = "gocache:getex"
+
+	// ScriptDelIfEqual removes a key if its value is equal to the given one, as done to
+	// release a lock. It's run with the key and the value, and returns 1 if the key was
+	// removed, or 0 otherwise.
+	ScriptDelIfEqual = "gocache:delifequal"
+
+	// ScriptCappedPush pushes values to the head of a list and trims it to a maximum
+	// length, as done to keep the latest entries of a log. It's run with the key, the
+	// maximum length and the values, pushed one after the other as by the Redis LPUSH
+	// command, and returns the length of the list.
+	ScriptCappedPush = "gocache:cappedpush"
+)
+
+// errWrongArity is returned by the built-in scripts when run with the wrong number of
+// keys or arguments.
+var errWrongArity = errors.New("wrong number of keys or arguments")
+
+// scriptRegistry holds the registered scripts by name.
+type scriptRegistry struct {
+	mu      sync.RWMutex
+	scripts map[string]*Script
+}
+
+var defaultScripts = &scriptRegistry{scripts: map[string]*Script{
+	ScriptGetEx: {
+		Name: ScriptGetEx,
+		Lua: `local v = redis.call('GET', KEYS[1])
+if v then redis.call('PEXPIRE', KEYS[1], ARGV[1]) end
+return v`,
+		Func: func(s driver.ScriptStore, keys []string, args []string) (interface{}, error) {
+			if len(keys) != 1 || len(args) != 1 {
+				return nil, errWrongArity
+			}
+			ms, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid expire time %q", args[0])
+			}
+			v, ok, err := s.Get(keys[0])
+			if err != nil || !ok {
+				return nil, err
+			}
+			if _, err := s.Expire(keys[0], time.Duration(ms)*time.Millisecond); err != nil {
+				return nil, err
+			}
+			return string(v), nil
+		},
+	},
+	ScriptDelIfEqual: {
+		Name: ScriptDelIfEqual,
+		Lua: `if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0`,
+		Func: func(s driver.ScriptStore, keys []string, args []string) (interface{}, error) {
+			if len(keys) != 1 || len(args) != 1 {
+				return nil, errWrongArity
+			}
+			v, ok, err := s.Get(keys[0])
+			if err != nil {
+				return nil, err
+			}
+			if !ok || string(v) != args[0] {
+				return int64(0), nil
+			}
+			if _, err := s.Del(keys[0]); err != nil {
+				return nil, err
+			}
+			return int64(1), nil
+		},
+	},
+	ScriptCappedPush: {
+		Name: ScriptCappedPush,
+		Lua: `local max = tonumber(ARGV[1])
+if not max or max < 1 or max % 1 ~= 0 then
+  return redis.error_reply('invalid maximum length ' .. ARGV[1])
+end
+local n = redis.call('LPUSH', KEYS[1], unpack(ARGV, 2))
+redis.call('LTRIM', KEYS[1], 0, max - 1)
+return math.min(n, max)`,
+		Func: func(s driver.ScriptStore, keys []string, args []string) (interface{}, error) {
+			if len(keys) != 1 || len(args) < 2 {
+				return nil, errWrongArity
+			}
+			limit, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil || limit < 1 {
+				return nil, fmt.Errorf("invalid maximum length %q", args[0])
+			}
+			values := make([][]byte, len(args)-1)
+			for i, arg := range args[1:] {
+				values[i] = []byte(arg)
+			}
+			n, err := s.LPush(keys[0], values...)
+			if err != nil {
+				return nil, err
+			}
+			if err := s.LTrim(keys[0], 0, limit-1); err != nil {
+				return nil, err
+			}
+			return min(n, limit), nil
+		},
+	},
+}}
+
+// RegisterScript registers a script under its name, to be run with
+// [GenericCache.RunScript]. If a script is already registered under the name, it panics.
+func RegisterScript(script *Script) {
+	defaultScripts.mu.Lock()
+	defer defaultScripts.mu.Unlock()
+	if _, exists := defaultScripts.scripts[script.Name]; exists {
+		panic(gcerrors.New(errors.New("script already registered: " + script.Name)))
+	}
+	defaultScripts.scripts[script.Name] = script
+}
+
+// RunScript runs the script registered under name atomically, with the given keys and
+// arguments, and returns its result. See [Script] for the types of results.
+//
+// It returns an error wrapping [ErrScriptNotFound] if no script is registered under
+// name, and an error wrapping [ErrNotSupported] if the underlying driver does not
+// implement [driver.ScriptRunner].
+func (c *GenericCache[K]) RunScript(ctx context.Context, name string, keys []K, args ...interface{}) (interface{}, error) {
+	defaultScripts.mu.RLock()
+	script, ok := defaultScripts.scripts[name]
+	defaultScripts.mu.RUnlock()
+	if !ok {
+		return nil, gcerrors.New(errors.Join(ErrScriptNotFound, fmt.Errorf("script %s not registered", name)))
+	}
+	r, ok := c.driver.(driver.ScriptRunner[K])
+	if !ok {
+		return nil, gcerrors.New(errors.Join(ErrNotSupported, errors.New("RunScript operation not supported")))
+	}
+	return r.RunScript(ctx, script, keys, args...)
+}
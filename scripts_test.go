@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bartventer/gocache/pkg/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapScriptStore is a [driver.ScriptStore] of the entries of a mapCache.
+type mapScriptStore struct{ *mapCache }
+
+func (s mapScriptStore) Get(key string) ([]byte, bool, error) {
+	e, ok := s.entries[key]
+	return e.Value, ok, nil
+}
+
+func (s mapScriptStore) Set(key string, value []byte, ttl time.Duration) error {
+	s.entries[key] = driver.Entry[string]{Key: key, Value: value, TTL: ttl}
+	return nil
+}
+
+func (s mapScriptStore) Del(key string) (bool, error) {
+	_, ok := s.entries[key]
+	delete(s.entries, key)
+	return ok, nil
+}
+
+func (s mapScriptStore) TTL(key string) (time.Duration, bool, error) {
+	e, ok := s.entries[key]
+	return e.TTL, ok, nil
+}
+
+func (s mapScriptStore) Expire(key string, ttl time.Duration) (bool, error) {
+	e, ok := s.entries[key]
+	if ok && ttl <= 0 {
+		delete(s.entries, key)
+	} else if ok {
+		e.TTL = ttl
+		s.entries[key] = e
+	}
+	return ok, nil
+}
+
+func (s mapScriptStore) LPush(key string, values ...[]byte) (int64, error) {
+	for _, v := range values {
+		s.lists[key] = append([][]byte{v}, s.lists[key]...)
+	}
+	return int64(len(s.lists[key])), nil
+}
+
+// bounds returns the bounds of the elements from start to stop of a list of n elements.
+func bounds(n, start, stop int64) (lo, hi int64) {
+	if start < 0 {
+		start = max(start+n, 0)
+	}
+	if stop < 0 {
+		stop += n
+	}
+	if stop = min(stop, n-1); start > stop {
+		return 0, 0
+	}
+	return start, stop + 1
+}
+
+func (s mapScriptStore) LRange(key string, start, stop int64) ([][]byte, error) {
+	lo, hi := bounds(int64(len(s.lists[key])), start, stop)
+	return s.lists[key][lo:hi], nil
+}
+
+func (s mapScriptStore) LTrim(key string, start, stop int64) error {
+	lo, hi := bounds(int64(len(s.lists[key])), start, stop)
+	if lo == hi {
+		delete(s.lists, key)
+	} else {
+		s.lists[key] = s.lists[key][lo:hi]
+	}
+	return nil
+}
+
+func (m *mapCache) RunScript(_ context.Context, script *Script, keys []string, args ...interface{}) (interface{}, error) {
+	sargs := make([]string, len(args))
+	for i, arg := range args {
+		sargs[i] = arg.(string)
+	}
+	return script.Func(mapScriptStore{m}, keys, sargs)
+}
+
+func TestRunScript(t *testing.T) {
+	ctx := context.Background()
+	m := newMapCache()
+	c := NewCache[string](m)
+	m.entries["lock"] = driver.Entry[string]{Key: "lock", Value: []byte("owner1")}
+
+	got, err := c.RunScript(ctx, ScriptGetEx, []string{"lock"}, "60000")
+	require.NoError(t, err)
+	assert.Equal(t, "owner1", got)
+	assert.Equal(t, time.Minute, m.entries["lock"].TTL)
+	got, err = c.RunScript(ctx, ScriptGetEx, []string{"missing"}, "60000")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	_, err = c.RunScript(ctx, ScriptGetEx, []string{"lock"})
+	require.Error(t, err)
+	_, err = c.RunScript(ctx, ScriptGetEx, []string{"lock"}, "soon")
+	require.Error(t, err)
+
+	got, err = c.RunScript(ctx, ScriptDelIfEqual, []string{"lock"}, "owner2")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), got)
+	got, err = c.RunScript(ctx, ScriptDelIfEqual, []string{"lock"}, "owner1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), got)
+	assert.Empty(t, m.entries)
+
+	// Like PEXPIRE, a time-to-live that is not positive removes the key.
+	m.entries["temp"] = driver.Entry[string]{Key: "temp", Value: []byte("value")}
+	got, err = c.RunScript(ctx, ScriptGetEx, []string{"temp"}, "0")
+	require.NoError(t, err)
+	assert.Equal(t, "value", got)
+	assert.Empty(t, m.entries)
+}
+
+func TestRunScript_CappedPush(t *testing.T) {
+	ctx := context.Background()
+	m := newMapCache()
+	c := NewCache[string](m)
+
+	got, err := c.RunScript(ctx, ScriptCappedPush, []string{"log"}, "3", "a", "b")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), got)
+	got, err = c.RunScript(ctx, ScriptCappedPush, []string{"log"}, "3", "c", "d")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), got)
+	assert.Equal(t, [][]byte{[]byte("d"), []byte("c"), []byte("b")}, m.lists["log"])
+
+	_, err = c.RunScript(ctx, ScriptCappedPush, []string{"log"}, "3")
+	require.Error(t, err)
+	_, err = c.RunScript(ctx, ScriptCappedPush, []string{"log"}, "0", "e")
+	require.Error(t, err)
+}
+
+func TestRunScript_NotFound(t *testing.T) {
+	_, err := NewCache[string](newMapCache()).RunScript(context.Background(), "missing", nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrScriptNotFound)
+}
+
+func TestRunScript_NotSupported(t *testing.T) {
+	var c struct{ driver.Cache[string] }
+	_, err := NewCache[string](c).RunScript(context.Background(), ScriptGetEx, []string{"key"}, "1000")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestRegisterScript(t *testing.T) {
+	script := &Script{Name: "test:register"}
+	RegisterScript(script)
+	t.Cleanup(func() {
+		defaultScripts.mu.Lock()
+		defer defaultScripts.mu.Unlock()
+		delete(defaultScripts.scripts, script.Name)
+	})
+	assert.Panics(t, func() { RegisterScript(script) })
+}